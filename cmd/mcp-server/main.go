@@ -5,17 +5,28 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/joho/godotenv"
+	"github.com/openai/openai-go"
 
 	"github.com/mike-a-ellis/eino-docs-mcp/internal/embedding"
 	ghclient "github.com/mike-a-ellis/eino-docs-mcp/internal/github"
+	"github.com/mike-a-ellis/eino-docs-mcp/internal/indexer"
+	"github.com/mike-a-ellis/eino-docs-mcp/internal/markdown"
 	mcpserver "github.com/mike-a-ellis/eino-docs-mcp/internal/mcp"
+	"github.com/mike-a-ellis/eino-docs-mcp/internal/metadata"
+	"github.com/mike-a-ellis/eino-docs-mcp/internal/prompts"
+	"github.com/mike-a-ellis/eino-docs-mcp/internal/rerank"
+	"github.com/mike-a-ellis/eino-docs-mcp/internal/scheduler"
+	"github.com/mike-a-ellis/eino-docs-mcp/internal/source"
 	"github.com/mike-a-ellis/eino-docs-mcp/internal/storage"
+	"github.com/mike-a-ellis/eino-docs-mcp/internal/webhook"
 )
 
 func main() {
@@ -29,28 +40,32 @@ func main() {
 	defer cancel()
 
 	// Configuration from environment
-	qdrantHost := getEnv("QDRANT_HOST", "localhost")
-	qdrantPort := getEnvInt("QDRANT_PORT", 6334)
+	storeCfg := storage.Config{
+		Backend:      getEnv("STORAGE_BACKEND", storage.BackendQdrant),
+		QdrantHost:   getEnv("QDRANT_HOST", "localhost"),
+		QdrantPort:   getEnvInt("QDRANT_PORT", 6334),
+		EmbeddedPath: getEnv("EMBEDDED_STORE_PATH", "data/eino-docs.idx"),
+	}
 	port := getEnv("PORT", "8080")
 
 	// Initialize storage
-	store, err := storage.NewQdrantStorage(qdrantHost, qdrantPort)
+	store, err := storage.Open(storeCfg)
 	if err != nil {
-		log.Fatalf("failed to connect to Qdrant: %v", err)
+		log.Fatalf("failed to open %s storage: %v", storeCfg.Backend, err)
 	}
 	defer store.Close()
 
-	// Ensure collection exists
-	if err := store.EnsureCollection(ctx); err != nil {
-		log.Fatalf("failed to ensure collection: %v", err)
+	// Initialize embedder
+	embedder, err := embedding.New(ctx, embedding.ConfigFromEnv())
+	if err != nil {
+		log.Fatalf("failed to create embedder: %v", err)
 	}
 
-	// Initialize embedding client
-	embeddingClient, err := embedding.NewClient()
-	if err != nil {
-		log.Fatalf("failed to create embedding client: %v", err)
+	// Ensure collection exists and was built with the same embedding model
+	spec := storage.EmbeddingSpec{Model: embedder.Model(), Dimension: embedder.Dimension()}
+	if err := store.EnsureCollection(ctx, spec); err != nil {
+		log.Fatalf("failed to ensure collection: %v", err)
 	}
-	embedder := embedding.NewEmbedder(embeddingClient, 0) // Use default batch size
 
 	// Initialize GitHub client
 	ghClient, err := ghclient.NewClient(ctx)
@@ -58,13 +73,85 @@ func main() {
 		log.Fatalf("failed to create GitHub client: %v", err)
 	}
 
+	// Load the documentation sources served and synced (the Eino User Manual by default)
+	sources, err := source.LoadConfig(os.Getenv("SOURCES_FILE"))
+	if err != nil {
+		log.Fatalf("failed to load sources: %v", err)
+	}
+
+	// Load the prompt library (built-in templates plus optional overrides)
+	promptLibrary, err := prompts.Load(os.Getenv("PROMPTS_DIR"))
+	if err != nil {
+		log.Fatalf("failed to load prompts: %v", err)
+	}
+
+	// The llm reranker and auto-sync's metadata generator share the embedding provider's client
+	rerankCfg := rerank.ConfigFromEnv()
+	autoSyncInterval := time.Duration(getEnvInt("AUTO_SYNC_MINUTES", 0)) * time.Minute
+	webhookSecret := os.Getenv("GITHUB_WEBHOOK_SECRET")
+	syncEnabled := autoSyncInterval > 0 || webhookSecret != ""
+	var chatClient *openai.Client
+	if rerankCfg.Provider == rerank.ProviderLLM || syncEnabled {
+		embeddingClient, err := embedding.NewClient(embedding.ConfigFromEnv())
+		if err != nil {
+			log.Fatalf("failed to create chat client: %v", err)
+		}
+		chatClient = embeddingClient.Client()
+	}
+
+	// Initialize the optional reranker
+	reranker, err := rerank.New(rerankCfg, chatClient)
+	if err != nil {
+		log.Fatalf("failed to create reranker: %v", err)
+	}
+
+	// Initialize the optional auto-sync scheduler: poll GitHub and index new commits
+	// incrementally, or re-index the files of webhook pushes, with the default
+	// chunking settings. Polling and webhooks share it, so only one sync runs at a
+	// time, covering every source
+	var sched *scheduler.Scheduler
+	if syncEnabled {
+		generator := metadata.NewGenerator(chatClient).WithModel(os.Getenv("METADATA_MODEL"))
+		pipelines := make([]*indexer.Pipeline, len(sources))
+		for i, cfg := range sources {
+			pipelines[i] = indexer.NewPipeline(ghclient.NewFetcher(ghClient, cfg), markdown.NewChunker(), embedder, generator, store, slog.Default()).
+				WithConfig(cfg)
+		}
+		if docsDir := os.Getenv("DOCS_DIR"); docsDir != "" {
+			if len(sources) > 1 {
+				log.Fatalf("DOCS_DIR replaces a single source, but %d are configured", len(sources))
+			}
+			// Sync from a local checkout, which something else keeps pulled
+			local, err := source.NewLocal(docsDir)
+			if err != nil {
+				log.Fatalf("failed to open docs directory: %v", err)
+			}
+			pipelines[0] = pipelines[0].WithSource(local)
+			log.Printf("Syncing from local docs directory %s", docsDir)
+		}
+		sched = scheduler.New(indexer.NewPipelines(pipelines...), autoSyncInterval, slog.Default())
+	}
+
 	// Create MCP server
 	server := mcpserver.NewServer(&mcpserver.Config{
-		Storage:  store,
-		Embedder: embedder,
-		GitHub:   ghClient,
+		Storage:   store,
+		Embedder:  embedder,
+		GitHub:    ghClient,
+		Prompts:   promptLibrary,
+		Reranker:  reranker,
+		Scheduler: sched,
+		Sources:   sources,
 	})
 
+	// Publish indexed documents as resources and notify subscribers after syncs
+	pollInterval := time.Duration(getEnvInt("RESOURCE_POLL_SECONDS", int(mcpserver.DefaultResourcePollInterval/time.Second))) * time.Second
+	go server.WatchResources(ctx, pollInterval)
+
+	if autoSyncInterval > 0 {
+		log.Printf("Auto-sync enabled, checking GitHub every %s", autoSyncInterval)
+		go sched.Start(ctx)
+	}
+
 	// Create HTTP server with multiple endpoints
 	mux := http.NewServeMux()
 
@@ -79,6 +166,14 @@ func main() {
 	mcpHTTPHandler := mcpserver.NewHTTPHandler(server, nil)
 	mux.Handle("/mcp", mcpHTTPHandler)
 
+	// GitHub push webhook (re-indexes the documents a push touched)
+	if webhookSecret != "" {
+		queue := webhook.NewQueue(sched, slog.Default())
+		go queue.Run(ctx)
+		mux.Handle("/webhooks/github", webhook.NewHandler([]byte(webhookSecret), sources, queue, slog.Default()))
+		log.Printf("GitHub webhook enabled at /webhooks/github")
+	}
+
 	// Check if running in server mode (HTTP) or stdio mode (local development)
 	serverMode := getEnv("SERVER_MODE", "false") == "true"
 
@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"log/slog"
 	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
 	"github.com/joho/godotenv"
@@ -13,40 +15,110 @@ import (
 
 	"github.com/mike-a-ellis/eino-docs-mcp/internal/embedding"
 	ghclient "github.com/mike-a-ellis/eino-docs-mcp/internal/github"
+	"github.com/mike-a-ellis/eino-docs-mcp/internal/goapi"
 	"github.com/mike-a-ellis/eino-docs-mcp/internal/indexer"
 	"github.com/mike-a-ellis/eino-docs-mcp/internal/markdown"
 	"github.com/mike-a-ellis/eino-docs-mcp/internal/metadata"
+	"github.com/mike-a-ellis/eino-docs-mcp/internal/source"
 	"github.com/mike-a-ellis/eino-docs-mcp/internal/storage"
 )
 
 var rootCmd = &cobra.Command{
 	Use:   "eino-sync",
 	Short: "Eino User Manual documentation indexing tool",
-	Long:  "CLI tool for managing Eino User Manual documentation index in Qdrant",
+	Long:  "CLI tool for managing Eino User Manual documentation index in Qdrant or an embedded index file",
 }
 
 var syncCmd = &cobra.Command{
 	Use:   "sync",
 	Short: "Re-index all documentation from GitHub",
-	Long: `Clears existing index and rebuilds from latest GitHub commit.
+	Long: `Rebuilds the index from latest GitHub commit.
 
 This command:
-1. Connects to Qdrant and verifies health
-2. Clears the existing document collection
-3. Fetches all Eino User Manual documentation from GitHub
+1. Opens the storage backend and verifies health
+2. Creates a new, empty generation collection (Qdrant) or clears the index (embedded)
+3. Fetches all documentation of every source (the Eino User Manual by default) from GitHub
 4. Generates embeddings and metadata for each document
-5. Stores documents and chunks in Qdrant
+5. Stores documents and chunks in the new generation
+6. Validates the generation and atomically switches the live alias to it
+
+The live index keeps serving the previous generation until step 6, and a failed
+run leaves it untouched. The embedded backend rebuilds in memory and only writes
+the index file once the same validation passes. Older generations are kept for "eino-sync rollback".
 
 Environment variables:
-  QDRANT_HOST    Qdrant hostname (default: localhost)
-  QDRANT_PORT    Qdrant gRPC port (default: 6334)
-  OPENAI_API_KEY OpenAI API key for embeddings (required)
-  GITHUB_TOKEN   GitHub token for higher rate limits (optional)`,
+  STORAGE_BACKEND     "qdrant" (default) or "embedded"
+  EMBEDDED_STORE_PATH Index file for the embedded backend (default: data/eino-docs.idx)
+  QDRANT_HOST         Qdrant hostname (default: localhost)
+  QDRANT_PORT         Qdrant gRPC port (default: 6334)
+  OPENAI_API_KEY      OpenAI API key for embeddings (required)
+  GITHUB_TOKEN        GitHub token for higher rate limits (optional)
+  SOURCES_FILE        YAML file listing the documentation sources (see --sources)
+
+With --sources (or SOURCES_FILE), every documentation source listed in the file
+(a docs directory in a GitHub repository, with its own name) is indexed into the
+same index, side by side. Without it, only the Eino User Manual is indexed.
+
+With --incremental, the collection is kept and only documents whose Git blob
+SHA changed are re-indexed. Documents removed upstream are deleted.
+
+With --docs-dir (or DOCS_DIR), documents are read from a local directory (the
+content/en/docs/eino folder of a cloudwego.github.io checkout, or any branch of
+it) instead of GitHub. It replaces the only source, so it cannot be combined with
+a sources file listing several. The revision recorded is the checkout's HEAD commit, or a
+hash of the documents' contents when the directory is not in a git working copy.
+
+With --go-source, the exported API of the cloudwego/eino module (a local checkout
+or a .tar.gz source archive) is indexed too, replacing previously indexed symbols.
+Full syncs build a new index, so pass it on every full sync to keep lookup_symbol working.`,
 	RunE: runSync,
 }
 
+var rollbackCmd = &cobra.Command{
+	Use:   "rollback [generation]",
+	Short: "Switch the live index back to a previous generation",
+	Long: `Atomically points the live alias at an older generation collection.
+
+Without an argument, rolls back to the generation built before the active one.
+Use "eino-sync generations" to list available generations. Qdrant backend only.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runRollback,
+}
+
+var generationsCmd = &cobra.Command{
+	Use:   "generations",
+	Short: "List index generations and show which one is live",
+	Args:  cobra.NoArgs,
+	RunE:  runGenerations,
+}
+
+var (
+	incremental     bool
+	keepGenerations int
+	maxFailedRatio  float64
+	chunkMaxTokens  int
+	chunkOverlap    int
+	concurrency     int
+	goSource        string
+	docsDir         string
+	sourcesFile     string
+	goCommit        string
+)
+
 func init() {
+	syncCmd.Flags().BoolVar(&incremental, "incremental", false, "Only re-index documents that changed since the last sync")
+	syncCmd.Flags().IntVar(&keepGenerations, "keep", 3, "Number of generations to keep, including the live one")
+	syncCmd.Flags().Float64Var(&maxFailedRatio, "max-failed-ratio", 0.1, "Abort without switching the live index if more than this fraction of documents fail")
+	syncCmd.Flags().IntVar(&chunkMaxTokens, "chunk-max-tokens", markdown.DefaultMaxTokens, "Maximum tokens per chunk; larger sections are split at H3/H4, paragraphs, then sentences")
+	syncCmd.Flags().IntVar(&chunkOverlap, "chunk-overlap", markdown.DefaultOverlapTokens, "Tokens of trailing context repeated at the start of the next chunk when a section is split (0 disables)")
+	syncCmd.Flags().IntVar(&concurrency, "concurrency", indexer.DefaultConcurrency, "Number of documents processed in parallel")
+	syncCmd.Flags().StringVar(&docsDir, "docs-dir", os.Getenv("DOCS_DIR"), "Read documents from this local docs directory instead of GitHub (default $DOCS_DIR)")
+	syncCmd.Flags().StringVar(&sourcesFile, "sources", os.Getenv("SOURCES_FILE"), "YAML file listing the documentation sources to index (default $SOURCES_FILE; the Eino User Manual alone if unset)")
+	syncCmd.Flags().StringVar(&goSource, "go-source", "", "Checkout directory or .tar.gz archive of the cloudwego/eino Go module whose API to index")
+	syncCmd.Flags().StringVar(&goCommit, "go-commit", "", "Commit SHA of --go-source, recorded with the indexed symbols")
 	rootCmd.AddCommand(syncCmd)
+	rootCmd.AddCommand(rollbackCmd)
+	rootCmd.AddCommand(generationsCmd)
 }
 
 func main() {
@@ -59,77 +131,243 @@ func main() {
 }
 
 func runSync(cmd *cobra.Command, args []string) error {
-	ctx := context.Background()
+	// Ctrl-C stops handing out documents and lets in-flight ones wind down
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 	start := time.Now()
 
 	fmt.Println("Starting sync...")
 	fmt.Println()
 
 	// Get environment configuration
-	qdrantHost := getEnv("QDRANT_HOST", "localhost")
-	qdrantPort := getEnvInt("QDRANT_PORT", 6334)
+	storeCfg := storageConfig()
 
-	// 1. Connect to Qdrant
-	fmt.Printf("Connecting to Qdrant at %s:%d...\n", qdrantHost, qdrantPort)
-	store, err := storage.NewQdrantStorage(qdrantHost, qdrantPort)
+	// 1. Open storage backend
+	if storeCfg.Backend == storage.BackendEmbedded {
+		fmt.Printf("Opening embedded index at %s...\n", storeCfg.EmbeddedPath)
+	} else {
+		fmt.Printf("Connecting to Qdrant at %s:%d...\n", storeCfg.QdrantHost, storeCfg.QdrantPort)
+	}
+	store, err := storage.Open(storeCfg)
 	if err != nil {
-		return fmt.Errorf("Failed to connect to Qdrant: %w", err)
+		return fmt.Errorf("Failed to open storage: %w", err)
 	}
 	defer store.Close()
 
-	// 2. Check health
-	if err := store.Health(ctx); err != nil {
-		return fmt.Errorf("Qdrant health check failed: %w", err)
+	// A full rebuild of the embedded index is only written out if it succeeds; until
+	// then the cleared or partial index stays in memory and is discarded on failure
+	embeddedStore, isEmbedded := store.(*storage.EmbeddedStorage)
+	succeeded := false
+	if isEmbedded && !incremental {
+		defer func() {
+			if succeeded {
+				return
+			}
+			if err := embeddedStore.Discard(); err != nil {
+				fmt.Printf("Warning: failed to discard partial index: %v\n", err)
+			}
+		}()
 	}
-	fmt.Println("Qdrant healthy")
 
-	// 3. Ensure collection exists
-	if err := store.EnsureCollection(ctx); err != nil {
-		return fmt.Errorf("Failed to ensure collection: %w", err)
+	// 2. Check health
+	if err := store.Health(ctx); err != nil {
+		return fmt.Errorf("Storage health check failed: %w", err)
 	}
+	fmt.Println("Storage healthy")
 
-	// 4. Initialize embedding client
-	embeddingClient, err := embedding.NewClient()
+	// 3. Initialize embedding client
+	embeddingCfg := embedding.ConfigFromEnv()
+	embeddingClient, err := embedding.NewClient(embeddingCfg)
 	if err != nil {
 		return fmt.Errorf("Failed to create embedding client: %w", err)
 	}
-	embedder := embedding.NewEmbedder(embeddingClient, 0) // Use default batch size
+	embedder := embedding.NewOpenAIEmbedder(embeddingClient, embeddingCfg.Model, embeddingCfg.Dimension, 0) // Use default batch size
+	if err := embedder.DetectDimension(ctx); err != nil {
+		return fmt.Errorf("Failed to initialize embedder: %w", err)
+	}
+	spec := storage.EmbeddingSpec{Model: embedder.Model(), Dimension: embedder.Dimension()}
+	fmt.Printf("Embedding model: %s\n", spec)
+
+	// 4. Incremental syncs add to the existing index, so its embedding model must match.
+	// Full syncs record the model in the collection they build (step 7).
+	if incremental {
+		if err := store.EnsureCollection(ctx, spec); err != nil {
+			return fmt.Errorf("Failed to ensure collection: %w", err)
+		}
+		if qdrantStore, ok := store.(*storage.QdrantStorage); ok {
+			sparse, err := qdrantStore.SparseEnabled(ctx)
+			if err != nil {
+				return fmt.Errorf("Failed to read collection config: %w", err)
+			}
+			if !sparse {
+				fmt.Println("Warning: the collection predates keyword search; chunks are stored without BM25 vectors")
+				fmt.Println("         and search is dense-only. Run a full sync to build a generation with them.")
+			}
+		}
+	}
 
-	// 5. Initialize GitHub client
-	ghClient, err := ghclient.NewClient(ctx)
+	// 5. Choose the documentation sources, each read from GitHub or, for a single
+	// source, optionally from a local directory
+	sources, err := source.LoadConfig(sourcesFile)
 	if err != nil {
-		return fmt.Errorf("Failed to create GitHub client: %w", err)
+		return fmt.Errorf("Failed to load sources: %w", err)
+	}
+	srcs := make([]source.Source, len(sources))
+	sourceName := "GitHub"
+	if docsDir != "" {
+		if len(sources) > 1 {
+			return fmt.Errorf("--docs-dir replaces a single source, but %d are configured", len(sources))
+		}
+		srcs[0], err = source.NewLocal(docsDir)
+		if err != nil {
+			return fmt.Errorf("Failed to open docs directory: %w", err)
+		}
+		sourceName = docsDir
+	} else {
+		ghClient, err := ghclient.NewClient(ctx)
+		if err != nil {
+			return fmt.Errorf("Failed to create GitHub client: %w", err)
+		}
+		for i, cfg := range sources {
+			srcs[i] = ghclient.NewFetcher(ghClient, cfg)
+		}
 	}
 
 	// 6. Initialize other components
-	chunker := markdown.NewChunker()
+	chunker := markdown.NewChunker(markdown.Options{
+		MaxTokens:     chunkMaxTokens,
+		OverlapTokens: overlapOption(chunkOverlap),
+	})
 	// Use the same OpenAI client from embeddings for metadata generation
-	generator := metadata.NewGenerator(embeddingClient.Client())
-	fetcher := ghclient.NewFetcher(ghClient, "cloudwego", "cloudwego.github.io", "content/en/docs/eino")
+	generator := metadata.NewGenerator(embeddingClient.Client()).WithModel(os.Getenv("METADATA_MODEL"))
 
-	// 7. Clear existing collection
-	fmt.Println()
-	fmt.Println("Clearing existing collection...")
-	if err := store.ClearCollection(ctx); err != nil {
-		return fmt.Errorf("Failed to clear collection: %w", err)
+	// 7. Choose the write target. Full Qdrant syncs build a new generation behind the
+	// live alias; incremental syncs and the embedded backend write in place.
+	var target storage.Store = store
+	qdrantStore, isQdrant := store.(*storage.QdrantStorage)
+	blueGreen := isQdrant && !incremental
+	var generation string
+
+	switch {
+	case blueGreen:
+		generation, err = qdrantStore.CreateGeneration(ctx, spec)
+		if err != nil {
+			return fmt.Errorf("Failed to create generation: %w", err)
+		}
+		fmt.Println()
+		fmt.Printf("Building new generation %s\n", generation)
+		target = qdrantStore.WithCollection(generation)
+	case !incremental:
+		fmt.Println()
+		fmt.Println("Clearing existing collection...")
+		if err := store.ClearCollection(ctx); err != nil {
+			return fmt.Errorf("Failed to clear collection: %w", err)
+		}
+		if err := store.EnsureCollection(ctx, spec); err != nil {
+			return fmt.Errorf("Failed to ensure collection: %w", err)
+		}
+		fmt.Println("Collection cleared")
 	}
-	fmt.Println("Collection cleared")
 
-	// 8. Initialize pipeline and run indexing
+	// discardGeneration drops a generation that will never go live. It also runs after
+	// an interrupt, so it must not inherit the cancellation.
+	discardGeneration := func() {
+		if err := qdrantStore.DeleteGeneration(context.WithoutCancel(ctx), generation); err != nil {
+			fmt.Printf("Warning: failed to delete generation %s: %v\n", generation, err)
+		}
+	}
+
+	// 8. Initialize one pipeline per source and run indexing
 	fmt.Println()
-	fmt.Println("Indexing documents from GitHub...")
-	pipeline := indexer.NewPipeline(fetcher, chunker, embedder, generator, store, slog.Default())
+	pipelines := make([]*indexer.Pipeline, len(sources))
+	for i, cfg := range sources {
+		pipelines[i] = indexer.NewPipeline(srcs[i], chunker, embedder, generator, target, slog.Default()).
+			WithConfig(cfg).
+			WithConcurrency(concurrency)
+	}
+	pipeline := indexer.NewPipelines(pipelines...)
 
-	result, err := pipeline.IndexAll(ctx)
+	var result *indexer.IndexResult
+	if incremental {
+		fmt.Printf("Incrementally indexing changed documents from %s...\n", sourceName)
+		result, err = pipeline.IndexIncremental(ctx)
+	} else {
+		fmt.Printf("Indexing documents from %s...\n", sourceName)
+		result, err = pipeline.IndexAll(ctx)
+	}
 	if err != nil {
+		if blueGreen {
+			discardGeneration()
+		}
 		return fmt.Errorf("Indexing failed: %w", err)
 	}
 
+	// 8a. Index the Go API alongside the docs
+	symbolCount := 0
+	if goSource != "" {
+		fmt.Printf("Indexing Go API from %s...\n", goSource)
+		api, err := goapi.ParsePath(goSource)
+		if err == nil {
+			symbolCount, err = indexer.IndexSymbols(ctx, target, embedder, indexer.SymbolRepository, goCommit, api)
+		}
+		if err != nil {
+			if blueGreen {
+				discardGeneration()
+			}
+			return fmt.Errorf("Go API indexing failed: %w", err)
+		}
+	}
+
+	// 8b. Validate and promote the new generation
+	if isEmbedded && !incremental {
+		if err := validateGeneration(ctx, target, result); err != nil {
+			return fmt.Errorf("Validation failed, index file unchanged: %w", err)
+		}
+		if err := embeddedStore.Flush(); err != nil {
+			return fmt.Errorf("Failed to write index file: %w", err)
+		}
+		succeeded = true
+	}
+	if blueGreen {
+		if err := validateGeneration(ctx, target, result); err != nil {
+			discardGeneration()
+			return fmt.Errorf("Validation failed, live index unchanged: %w", err)
+		}
+		if err := qdrantStore.PromoteGeneration(ctx, generation); err != nil {
+			// Keep the generation if no index is live any more (the legacy collection
+			// was dropped but the alias was not created), so promotion can be retried
+			if active, activeErr := qdrantStore.ActiveGeneration(context.WithoutCancel(ctx)); activeErr == nil && active != "" {
+				discardGeneration()
+			} else {
+				fmt.Printf("Warning: no live index; keeping %s, run \"eino-sync rollback %s\" to retry\n", generation, generation)
+			}
+			return fmt.Errorf("Failed to switch live index: %w", err)
+		}
+		fmt.Printf("Live index switched to %s\n", generation)
+
+		pruned, err := qdrantStore.PruneGenerations(ctx, keepGenerations)
+		if err != nil {
+			fmt.Printf("Warning: failed to prune old generations: %v\n", err)
+		}
+		for _, name := range pruned {
+			fmt.Printf("Pruned old generation %s\n", name)
+		}
+	}
+
 	// 9. Print results
 	fmt.Println()
 	fmt.Println("Sync complete!")
-	fmt.Printf("  Documents: %d/%d\n", result.SuccessfulDocs, result.TotalDocs)
+	if incremental {
+		fmt.Printf("  Re-indexed: %d\n", result.SuccessfulDocs)
+		fmt.Printf("  Unchanged: %d\n", result.SkippedDocs)
+		fmt.Printf("  Removed: %d\n", len(result.DeletedDocs))
+	} else {
+		fmt.Printf("  Documents: %d/%d\n", result.SuccessfulDocs, result.TotalDocs)
+	}
 	fmt.Printf("  Chunks: %d\n", result.TotalChunks)
+	if goSource != "" {
+		fmt.Printf("  Go symbols: %d\n", symbolCount)
+	}
 	fmt.Printf("  Duration: %s\n", result.Duration.Round(time.Second))
 	fmt.Printf("  Commit: %s\n", result.CommitSHA)
 
@@ -138,7 +376,7 @@ func runSync(cmd *cobra.Command, args []string) error {
 		fmt.Println()
 		fmt.Println("Failed documents:")
 		for _, failed := range result.FailedDocs {
-			fmt.Printf("  - %s: %s\n", failed.Path, failed.Reason)
+			fmt.Printf("  - %s/%s: %s\n", failed.Source, failed.Path, failed.Reason)
 		}
 	}
 
@@ -148,6 +386,112 @@ func runSync(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// validateGeneration checks a freshly built generation before it goes live.
+func validateGeneration(ctx context.Context, store storage.Store, result *indexer.IndexResult) error {
+	if result.SuccessfulDocs == 0 {
+		return fmt.Errorf("no documents were indexed")
+	}
+
+	failedRatio := float64(len(result.FailedDocs)) / float64(result.TotalDocs)
+	if failedRatio > maxFailedRatio {
+		return fmt.Errorf("%d of %d documents failed (%.0f%%, limit %.0f%%)",
+			len(result.FailedDocs), result.TotalDocs, failedRatio*100, maxFailedRatio*100)
+	}
+
+	info, err := store.GetCollectionInfo(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to read generation info: %w", err)
+	}
+	expected := uint64(result.SuccessfulDocs + result.TotalChunks)
+	if info.PointsCount < expected {
+		return fmt.Errorf("generation has %d points, expected at least %d", info.PointsCount, expected)
+	}
+
+	return nil
+}
+
+func runRollback(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	store, err := openQdrant()
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	generations, err := store.ListGenerations(ctx)
+	if err != nil {
+		return fmt.Errorf("Failed to list generations: %w", err)
+	}
+
+	target := storage.PreviousGeneration(generations)
+	if len(args) > 0 {
+		target = args[0]
+	}
+	if target == "" {
+		return fmt.Errorf("No previous generation to roll back to")
+	}
+
+	if err := store.PromoteGeneration(ctx, target); err != nil {
+		return fmt.Errorf("Rollback failed: %w", err)
+	}
+
+	fmt.Printf("Live index switched to %s\n", target)
+	return nil
+}
+
+func runGenerations(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	store, err := openQdrant()
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	generations, err := store.ListGenerations(ctx)
+	if err != nil {
+		return fmt.Errorf("Failed to list generations: %w", err)
+	}
+
+	if len(generations) == 0 {
+		fmt.Println("No generations found")
+		return nil
+	}
+	for _, generation := range generations {
+		marker := " "
+		if generation.Active {
+			marker = "*"
+		}
+		fmt.Printf("%s %s\n", marker, generation.Name)
+	}
+	return nil
+}
+
+// openQdrant connects to Qdrant for generation management commands.
+func openQdrant() (*storage.QdrantStorage, error) {
+	cfg := storageConfig()
+	if cfg.Backend != storage.BackendQdrant {
+		return nil, fmt.Errorf("Generations are only supported by the qdrant storage backend")
+	}
+
+	store, err := storage.NewQdrantStorage(cfg.QdrantHost, cfg.QdrantPort)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to connect to Qdrant: %w", err)
+	}
+	return store, nil
+}
+
+// storageConfig reads storage backend settings from the environment.
+func storageConfig() storage.Config {
+	return storage.Config{
+		Backend:      getEnv("STORAGE_BACKEND", storage.BackendQdrant),
+		QdrantHost:   getEnv("QDRANT_HOST", "localhost"),
+		QdrantPort:   getEnvInt("QDRANT_PORT", 6334),
+		EmbeddedPath: getEnv("EMBEDDED_STORE_PATH", "data/eino-docs.idx"),
+	}
+}
+
 func getEnv(key, defaultValue string) string {
 	if v := os.Getenv(key); v != "" {
 		return v
@@ -164,3 +508,12 @@ func getEnvInt(key string, defaultValue int) int {
 	}
 	return defaultValue
 }
+
+// overlapOption maps the --chunk-overlap flag to markdown.Options, where zero means
+// "use the default" and a negative value disables overlap.
+func overlapOption(tokens int) int {
+	if tokens == 0 {
+		return -1
+	}
+	return tokens
+}
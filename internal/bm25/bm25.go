@@ -0,0 +1,153 @@
+// Package bm25 builds sparse lexical vectors for BM25-style keyword search.
+//
+// Document vectors carry the BM25 term-frequency component; the inverse document
+// frequency is applied at query time by the store (Qdrant's IDF modifier, or the
+// embedded store's own corpus statistics). Terms are hashed into a 32-bit index
+// space, so no vocabulary needs to be stored.
+package bm25
+
+import (
+	"hash/fnv"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+const (
+	// K1 controls term-frequency saturation.
+	K1 = 1.2
+	// B controls document-length normalization.
+	B = 0.75
+	// AvgDocLength is the assumed average chunk length in tokens. Chunks are indexed
+	// one at a time, so corpus statistics are not available at encode time.
+	AvgDocLength = 256
+)
+
+// Vector is a sparse vector of hashed term indices and weights, sorted by index.
+type Vector struct {
+	Indices []uint32
+	Values  []float32
+}
+
+// EncodeDocument returns the BM25 term-frequency vector for a chunk of text.
+func EncodeDocument(text string) Vector {
+	tokens := Tokenize(text)
+	if len(tokens) == 0 {
+		return Vector{}
+	}
+
+	counts := make(map[uint32]int)
+	for _, token := range tokens {
+		counts[hashToken(token)]++
+	}
+
+	docLen := float64(len(tokens))
+	norm := K1 * (1 - B + B*docLen/AvgDocLength)
+
+	weights := make(map[uint32]float32, len(counts))
+	for index, count := range counts {
+		tf := float64(count)
+		weights[index] = float32(tf * (K1 + 1) / (tf + norm))
+	}
+	return toVector(weights)
+}
+
+// EncodeQuery returns the query vector: each distinct term with weight 1.
+func EncodeQuery(text string) Vector {
+	weights := make(map[uint32]float32)
+	for _, token := range Tokenize(text) {
+		weights[hashToken(token)] = 1
+	}
+	return toVector(weights)
+}
+
+// Tokenize splits text into lowercase terms tuned for API documentation.
+// Identifiers are kept whole and also split into their parts, so a query for
+// "compose.NewGraph" matches "compose.NewGraph", "NewGraph", "new" and "graph".
+func Tokenize(text string) []string {
+	var tokens []string
+
+	for _, word := range strings.FieldsFunc(text, isSeparator) {
+		word = strings.Trim(word, "._")
+		if word == "" {
+			continue
+		}
+
+		lower := strings.ToLower(word)
+		tokens = append(tokens, lower)
+
+		parts := splitIdentifier(word)
+		if len(parts) > 1 {
+			for _, part := range parts {
+				tokens = append(tokens, strings.ToLower(part))
+			}
+		}
+	}
+
+	return tokens
+}
+
+// isSeparator reports whether r ends a word. Dots and underscores are kept so that
+// qualified identifiers like compose.NewGraph or with_callbacks stay together.
+func isSeparator(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '.' && r != '_'
+}
+
+// splitIdentifier splits at dots, underscores and camelCase boundaries.
+// "compose.NewGraph" -> ["compose", "New", "Graph"]; "HTTPClient" -> ["HTTP", "Client"].
+func splitIdentifier(word string) []string {
+	var parts []string
+	for _, segment := range strings.FieldsFunc(word, func(r rune) bool { return r == '.' || r == '_' }) {
+		parts = append(parts, splitCamel(segment)...)
+	}
+	return parts
+}
+
+// splitCamel splits a single identifier segment at case and letter/digit boundaries.
+func splitCamel(s string) []string {
+	runes := []rune(s)
+	var parts []string
+	start := 0
+
+	for i := 1; i < len(runes); i++ {
+		prev, cur := runes[i-1], runes[i]
+		boundary := false
+		switch {
+		case unicode.IsLower(prev) && unicode.IsUpper(cur):
+			boundary = true // newGraph -> new|Graph
+		case unicode.IsUpper(prev) && unicode.IsUpper(cur) && i+1 < len(runes) && unicode.IsLower(runes[i+1]):
+			boundary = true // HTTPClient -> HTTP|Client
+		case unicode.IsDigit(prev) != unicode.IsDigit(cur):
+			boundary = true // v2Client -> v|2|Client
+		}
+		if boundary {
+			parts = append(parts, string(runes[start:i]))
+			start = i
+		}
+	}
+	parts = append(parts, string(runes[start:]))
+	return parts
+}
+
+// hashToken maps a term into the 32-bit sparse index space.
+func hashToken(token string) uint32 {
+	h := fnv.New32a()
+	h.Write([]byte(token))
+	return h.Sum32()
+}
+
+// toVector converts a weight map into a Vector sorted by index.
+func toVector(weights map[uint32]float32) Vector {
+	v := Vector{
+		Indices: make([]uint32, 0, len(weights)),
+		Values:  make([]float32, 0, len(weights)),
+	}
+	for index := range weights {
+		v.Indices = append(v.Indices, index)
+	}
+	sort.Slice(v.Indices, func(i, j int) bool { return v.Indices[i] < v.Indices[j] })
+	for _, index := range v.Indices {
+		v.Values = append(v.Values, weights[index])
+	}
+	return v
+}
@@ -0,0 +1,71 @@
+package bm25
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestTokenize_Identifiers verifies qualified and camelCase identifiers are kept whole and split.
+func TestTokenize_Identifiers(t *testing.T) {
+	got := Tokenize("Use compose.NewGraph with WithCallbacks.")
+	want := []string{
+		"use",
+		"compose.newgraph", "compose", "new", "graph",
+		"with",
+		"withcallbacks", "with", "callbacks",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Tokenize mismatch:\n got %q\nwant %q", got, want)
+	}
+}
+
+// TestTokenize_Acronyms verifies acronym boundaries in identifiers.
+func TestTokenize_Acronyms(t *testing.T) {
+	got := Tokenize("HTTPClient")
+	want := []string{"httpclient", "http", "client"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Tokenize mismatch: got %q, want %q", got, want)
+	}
+}
+
+// TestEncodeDocument_TermFrequency verifies repeated terms weigh more but saturate.
+func TestEncodeDocument_TermFrequency(t *testing.T) {
+	once := EncodeDocument("ToolsNode agent")
+	thrice := EncodeDocument("ToolsNode ToolsNode ToolsNode agent")
+
+	weight := func(v Vector, token string) float32 {
+		index := hashToken(token)
+		for i, idx := range v.Indices {
+			if idx == index {
+				return v.Values[i]
+			}
+		}
+		return 0
+	}
+
+	w1, w3 := weight(once, "toolsnode"), weight(thrice, "toolsnode")
+	if w1 <= 0 || w3 <= w1 {
+		t.Errorf("Expected repeated term to weigh more: once=%f thrice=%f", w1, w3)
+	}
+	if w3 >= K1+1 {
+		t.Errorf("Term weight should saturate below k1+1, got %f", w3)
+	}
+}
+
+// TestEncode_SortedIndices verifies vectors are sorted by index with aligned values.
+func TestEncode_SortedIndices(t *testing.T) {
+	for _, v := range []Vector{EncodeDocument("alpha beta gamma delta"), EncodeQuery("alpha beta gamma delta")} {
+		if len(v.Indices) != 4 || len(v.Values) != 4 {
+			t.Fatalf("Expected 4 terms, got %d indices and %d values", len(v.Indices), len(v.Values))
+		}
+		for i := 1; i < len(v.Indices); i++ {
+			if v.Indices[i-1] >= v.Indices[i] {
+				t.Errorf("Indices not strictly sorted: %v", v.Indices)
+			}
+		}
+	}
+
+	if empty := EncodeDocument("  ... "); len(empty.Indices) != 0 {
+		t.Errorf("Expected empty vector for text without terms, got %v", empty.Indices)
+	}
+}
@@ -2,26 +2,117 @@ package embedding
 
 import (
 	"fmt"
+	"net/http"
 	"os"
+	"strconv"
 
 	"github.com/openai/openai-go"
+	"github.com/openai/openai-go/azure"
+	"github.com/openai/openai-go/option"
+
+	"github.com/mike-a-ellis/eino-docs-mcp/internal/ratelimit"
+)
+
+// Supported embedding providers.
+const (
+	// ProviderOpenAI uses the OpenAI API.
+	ProviderOpenAI = "openai"
+	// ProviderOpenAICompatible uses any server implementing the OpenAI embeddings API
+	// (Ollama, vLLM, LM Studio, ...) at Config.BaseURL.
+	ProviderOpenAICompatible = "openai-compatible"
+	// ProviderAzure uses an Azure OpenAI deployment; Config.Model is the deployment name.
+	ProviderAzure = "azure"
 )
 
+// DefaultAzureAPIVersion is used when no Azure OpenAI API version is configured.
+const DefaultAzureAPIVersion = "2024-06-01"
+
+// Config selects and configures an embedding provider.
+type Config struct {
+	Provider        string // ProviderOpenAI (default), ProviderOpenAICompatible or ProviderAzure
+	APIKey          string // API key (optional for local OpenAI-compatible servers)
+	BaseURL         string // Server base URL (openai-compatible), e.g. http://localhost:11434/v1
+	AzureEndpoint   string // Resource endpoint (azure), e.g. https://my-resource.openai.azure.com
+	AzureAPIVersion string // API version (azure, default DefaultAzureAPIVersion)
+	Model           string // Model or Azure deployment name (default DefaultModel)
+	Dimension       int    // Vector size; detected when zero and the model is not well known
+	BatchSize       int    // Texts per request (default DefaultBatchSize)
+}
+
+// ConfigFromEnv reads the embedding configuration from the environment:
+// EMBEDDING_PROVIDER, EMBEDDING_MODEL, EMBEDDING_DIMENSION, EMBEDDING_BASE_URL,
+// EMBEDDING_API_KEY (falls back to OPENAI_API_KEY), and for Azure
+// AZURE_OPENAI_ENDPOINT, AZURE_OPENAI_API_KEY and AZURE_OPENAI_API_VERSION.
+func ConfigFromEnv() Config {
+	cfg := Config{
+		Provider:        os.Getenv("EMBEDDING_PROVIDER"),
+		APIKey:          os.Getenv("EMBEDDING_API_KEY"),
+		BaseURL:         os.Getenv("EMBEDDING_BASE_URL"),
+		AzureEndpoint:   os.Getenv("AZURE_OPENAI_ENDPOINT"),
+		AzureAPIVersion: os.Getenv("AZURE_OPENAI_API_VERSION"),
+		Model:           os.Getenv("EMBEDDING_MODEL"),
+	}
+	if cfg.APIKey == "" {
+		if cfg.Provider == ProviderAzure {
+			cfg.APIKey = os.Getenv("AZURE_OPENAI_API_KEY")
+		} else {
+			cfg.APIKey = os.Getenv("OPENAI_API_KEY")
+		}
+	}
+	if v, err := strconv.Atoi(os.Getenv("EMBEDDING_DIMENSION")); err == nil {
+		cfg.Dimension = v
+	}
+	return cfg
+}
+
 // Client wraps the OpenAI client for embedding generation.
 type Client struct {
 	client *openai.Client
 }
 
-// NewClient creates a new OpenAI client for embedding generation.
-// It reads the OPENAI_API_KEY from the environment and returns an error if not set.
-func NewClient() (*Client, error) {
-	apiKey := os.Getenv("OPENAI_API_KEY")
-	if apiKey == "" {
-		return nil, fmt.Errorf("OPENAI_API_KEY environment variable not set")
+// NewClient creates an OpenAI API client for the configured provider.
+// Returns an error if a setting the provider requires is missing.
+// All requests made through the client share one rate-limit gate, so a 429 seen by
+// one caller (embeddings or metadata generation) pauses the others too.
+func NewClient(cfg Config) (*Client, error) {
+	opts := []option.RequestOption{
+		option.WithHTTPClient(&http.Client{Transport: ratelimit.NewTransport(nil, &ratelimit.Gate{})}),
+	}
+
+	switch cfg.Provider {
+	case "", ProviderOpenAI:
+		if cfg.APIKey == "" {
+			return nil, fmt.Errorf("OPENAI_API_KEY environment variable not set")
+		}
+		opts = append(opts, option.WithAPIKey(cfg.APIKey))
+
+	case ProviderOpenAICompatible:
+		if cfg.BaseURL == "" {
+			return nil, fmt.Errorf("EMBEDDING_BASE_URL is required for the %s provider", cfg.Provider)
+		}
+		// Local servers usually ignore the key, but the client always sends one
+		apiKey := cfg.APIKey
+		if apiKey == "" {
+			apiKey = "unused"
+		}
+		opts = append(opts, option.WithBaseURL(cfg.BaseURL), option.WithAPIKey(apiKey))
+
+	case ProviderAzure:
+		if cfg.AzureEndpoint == "" || cfg.APIKey == "" {
+			return nil, fmt.Errorf("AZURE_OPENAI_ENDPOINT and AZURE_OPENAI_API_KEY are required for the %s provider", cfg.Provider)
+		}
+		apiVersion := cfg.AzureAPIVersion
+		if apiVersion == "" {
+			apiVersion = DefaultAzureAPIVersion
+		}
+		opts = append(opts, azure.WithEndpoint(cfg.AzureEndpoint, apiVersion), azure.WithAPIKey(cfg.APIKey))
+
+	default:
+		return nil, fmt.Errorf("unknown embedding provider %q (expected %s, %s or %s)",
+			cfg.Provider, ProviderOpenAI, ProviderOpenAICompatible, ProviderAzure)
 	}
 
-	// openai-go automatically reads OPENAI_API_KEY from environment
-	client := openai.NewClient()
+	client := openai.NewClient(opts...)
 
 	return &Client{client: &client}, nil
 }
@@ -0,0 +1,127 @@
+package embedding
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestNewClient_ProviderValidation verifies each provider requires its settings.
+func TestNewClient_ProviderValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr bool
+	}{
+		{"openai without key", Config{Provider: ProviderOpenAI}, true},
+		{"openai", Config{APIKey: "sk-test"}, false},
+		{"compatible without base URL", Config{Provider: ProviderOpenAICompatible}, true},
+		{"compatible without key", Config{Provider: ProviderOpenAICompatible, BaseURL: "http://localhost:11434/v1"}, false},
+		{"azure without endpoint", Config{Provider: ProviderAzure, APIKey: "key"}, true},
+		{"azure", Config{Provider: ProviderAzure, APIKey: "key", AzureEndpoint: "https://example.openai.azure.com"}, false},
+		{"unknown provider", Config{Provider: "cohere", APIKey: "key"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewClient(tt.cfg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewClient() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestConfigFromEnv verifies environment variables map to the config, including the
+// provider-specific API key fallback.
+func TestConfigFromEnv(t *testing.T) {
+	t.Setenv("EMBEDDING_PROVIDER", ProviderAzure)
+	t.Setenv("EMBEDDING_API_KEY", "")
+	t.Setenv("AZURE_OPENAI_API_KEY", "azure-key")
+	t.Setenv("OPENAI_API_KEY", "openai-key")
+	t.Setenv("EMBEDDING_MODEL", "my-deployment")
+	t.Setenv("EMBEDDING_DIMENSION", "768")
+
+	cfg := ConfigFromEnv()
+	if cfg.APIKey != "azure-key" {
+		t.Errorf("APIKey: expected azure-key, got %q", cfg.APIKey)
+	}
+	if cfg.Model != "my-deployment" || cfg.Dimension != 768 {
+		t.Errorf("Model/Dimension: got %q/%d", cfg.Model, cfg.Dimension)
+	}
+}
+
+// TestNewOpenAIEmbedder_Defaults verifies known models need no dimension probe.
+func TestNewOpenAIEmbedder_Defaults(t *testing.T) {
+	e := NewOpenAIEmbedder(nil, "", 0, 0)
+	if e.Model() != DefaultModel || e.Dimension() != 1536 || e.batchSize != DefaultBatchSize {
+		t.Errorf("unexpected defaults: model=%s dimension=%d batch=%d", e.Model(), e.Dimension(), e.batchSize)
+	}
+
+	e = NewOpenAIEmbedder(nil, "some-local-model", 0, 0)
+	if e.Dimension() != 0 {
+		t.Errorf("unknown model should need detection, got dimension %d", e.Dimension())
+	}
+}
+
+// newEmbeddingsServer serves /embeddings with vectors of the given size and records
+// the dimensions parameter of the last request (0 when absent).
+func newEmbeddingsServer(t *testing.T, size int, dimensions *int) Config {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Input      []string `json:"input"`
+			Dimensions int      `json:"dimensions"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		*dimensions = req.Dimensions
+
+		vector := "[" + strings.TrimSuffix(strings.Repeat("0.5,", size), ",") + "]"
+		var data []string
+		for i := range req.Input {
+			data = append(data, fmt.Sprintf(`{"object":"embedding","index":%d,"embedding":%s}`, i, vector))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"object":"list","model":"test","data":[%s],"usage":{"prompt_tokens":1,"total_tokens":1}}`, strings.Join(data, ","))
+	}))
+	t.Cleanup(server.Close)
+	return Config{Provider: ProviderOpenAICompatible, BaseURL: server.URL}
+}
+
+// TestOpenAIEmbedder_Dimensions verifies a configured dimension is requested from the
+// API and vectors of any other size are rejected.
+func TestOpenAIEmbedder_Dimensions(t *testing.T) {
+	var dimensions int
+	cfg := newEmbeddingsServer(t, 4, &dimensions)
+	client, err := NewClient(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	e := NewOpenAIEmbedder(client, "text-embedding-3-small", 4, 0)
+	embeddings, err := e.GenerateEmbeddings(context.Background(), []string{"a", "b"})
+	if err != nil {
+		t.Fatalf("GenerateEmbeddings: %v", err)
+	}
+	if dimensions != 4 || len(embeddings) != 2 || len(embeddings[0]) != 4 {
+		t.Errorf("expected 2 vectors of the requested size 4, got %d (requested %d)", len(embeddings), dimensions)
+	}
+
+	// The model's own size is not requested: not every model accepts the parameter
+	e = NewOpenAIEmbedder(client, "text-embedding-3-small", 1536, 0)
+	if _, err := e.GenerateEmbeddings(context.Background(), []string{"a"}); err == nil {
+		t.Error("expected an error for 4-dimensional vectors from a 1536-dimensional model")
+	} else if !strings.Contains(err.Error(), "returned 4-dimensional embeddings, expected 1536") {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if dimensions != 0 {
+		t.Errorf("expected no dimensions parameter, got %d", dimensions)
+	}
+}
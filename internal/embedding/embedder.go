@@ -11,41 +11,124 @@ import (
 )
 
 const (
-	// EmbeddingModel is the OpenAI model used for generating embeddings.
-	EmbeddingModel = "text-embedding-3-small"
-
-	// EmbeddingDimension is the vector dimension for text-embedding-3-small.
-	// This matches storage.VectorDimension (1536).
-	EmbeddingDimension = 1536
+	// DefaultModel is the OpenAI model used when no model is configured.
+	DefaultModel = "text-embedding-3-small"
 
 	// DefaultBatchSize balances requests-per-minute vs tokens-per-minute rate limits.
 	// OpenAI supports up to 2048 texts per batch, but smaller batches reduce TPM pressure.
 	DefaultBatchSize = 500
 )
 
-// Embedder generates embeddings for text using OpenAI's text-embedding-3-small model.
+// knownDimensions lists vector sizes of common models so they need no probe request.
+var knownDimensions = map[string]int{
+	"text-embedding-3-small": 1536,
+	"text-embedding-3-large": 3072,
+	"text-embedding-ada-002": 1536,
+	"nomic-embed-text":       768,
+	"mxbai-embed-large":      1024,
+	"all-minilm":             384,
+}
+
+// Embedder generates embedding vectors for text.
+// The model name and dimension are recorded with the index so that queries are
+// embedded in the same vector space the documents were.
+type Embedder interface {
+	// GenerateEmbeddings returns one vector per input text, in order.
+	GenerateEmbeddings(ctx context.Context, texts []string) ([][]float32, error)
+	// Model returns the embedding model name.
+	Model() string
+	// Dimension returns the size of generated vectors.
+	Dimension() int
+}
+
+// Compile-time interface check.
+var _ Embedder = (*OpenAIEmbedder)(nil)
+
+// New creates the embedder described by cfg. When the dimension is neither configured
+// nor known for the model, one probe request determines it.
+func New(ctx context.Context, cfg Config) (Embedder, error) {
+	client, err := NewClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	embedder := NewOpenAIEmbedder(client, cfg.Model, cfg.Dimension, cfg.BatchSize)
+	if err := embedder.DetectDimension(ctx); err != nil {
+		return nil, err
+	}
+	return embedder, nil
+}
+
+// OpenAIEmbedder generates embeddings through the OpenAI embeddings API, which also
+// serves Azure OpenAI and OpenAI-compatible servers such as Ollama, vLLM and LM Studio.
 // It batches requests for efficiency and implements exponential backoff on rate limit errors.
-type Embedder struct {
-	client    *Client
-	batchSize int
+type OpenAIEmbedder struct {
+	client     *Client
+	model      string
+	dimension  int
+	dimensions int // Sent as the API's dimensions parameter; 0 for the model's own size
+	batchSize  int
 }
 
-// NewEmbedder creates a new Embedder with the given client and optional batch size.
-// If batchSize is 0, DefaultBatchSize (500) is used.
-func NewEmbedder(client *Client, batchSize int) *Embedder {
+// NewOpenAIEmbedder creates an embedder for the given model.
+// If model is empty, DefaultModel is used. If dimension is 0, the known dimension of
+// the model is used (0 if unknown); any other size is requested from the API, which
+// shortens vectors of models that support it. If batchSize is 0, DefaultBatchSize
+// (500) is used.
+func NewOpenAIEmbedder(client *Client, model string, dimension int, batchSize int) *OpenAIEmbedder {
+	if model == "" {
+		model = DefaultModel
+	}
+	dimensions := 0
+	if dimension <= 0 {
+		dimension = knownDimensions[model]
+	} else if dimension != knownDimensions[model] {
+		dimensions = dimension
+	}
 	if batchSize <= 0 {
 		batchSize = DefaultBatchSize
 	}
-	return &Embedder{
-		client:    client,
-		batchSize: batchSize,
+	return &OpenAIEmbedder{
+		client:     client,
+		model:      model,
+		dimension:  dimension,
+		dimensions: dimensions,
+		batchSize:  batchSize,
+	}
+}
+
+// Model returns the embedding model (or Azure deployment) name.
+func (e *OpenAIEmbedder) Model() string {
+	return e.model
+}
+
+// Dimension returns the size of generated vectors.
+func (e *OpenAIEmbedder) Dimension() int {
+	return e.dimension
+}
+
+// DetectDimension embeds a probe text to learn the vector size of a model that has
+// no configured or known dimension. No-op when the dimension is already set.
+func (e *OpenAIEmbedder) DetectDimension(ctx context.Context) error {
+	if e.dimension > 0 {
+		return nil
 	}
+
+	embeddings, err := e.GenerateEmbeddings(ctx, []string{"dimension probe"})
+	if err != nil {
+		return fmt.Errorf("failed to detect dimension of model %s: %w", e.model, err)
+	}
+	if len(embeddings) == 0 || len(embeddings[0]) == 0 {
+		return fmt.Errorf("model %s returned an empty embedding", e.model)
+	}
+	e.dimension = len(embeddings[0])
+	return nil
 }
 
 // GenerateEmbeddings generates embeddings for the given texts.
 // Returns [][]float32 to match storage.Chunk.Embedding type.
 // Batches requests and retries with exponential backoff on rate limit errors.
-func (e *Embedder) GenerateEmbeddings(ctx context.Context, texts []string) ([][]float32, error) {
+func (e *OpenAIEmbedder) GenerateEmbeddings(ctx context.Context, texts []string) ([][]float32, error) {
 	var allEmbeddings [][]float32
 
 	// Process in batches
@@ -65,17 +148,23 @@ func (e *Embedder) GenerateEmbeddings(ctx context.Context, texts []string) ([][]
 
 // embedBatchWithRetry generates embeddings for a single batch with retry logic.
 // Retries with exponential backoff on rate limit errors (HTTP 429).
-// Other errors are treated as permanent and fail immediately.
-func (e *Embedder) embedBatchWithRetry(ctx context.Context, texts []string) ([][]float32, error) {
+// Other errors, and vectors of a size other than Dimension, are treated as permanent
+// and fail immediately.
+func (e *OpenAIEmbedder) embedBatchWithRetry(ctx context.Context, texts []string) ([][]float32, error) {
 	var embeddings [][]float32
 
+	params := openai.EmbeddingNewParams{
+		Input: openai.EmbeddingNewParamsInputUnion{
+			OfArrayOfStrings: texts,
+		},
+		Model: e.model,
+	}
+	if e.dimensions > 0 {
+		params.Dimensions = openai.Int(int64(e.dimensions))
+	}
+
 	operation := func() error {
-		resp, err := e.client.client.Embeddings.New(ctx, openai.EmbeddingNewParams{
-			Input: openai.EmbeddingNewParamsInputUnion{
-				OfArrayOfStrings: texts,
-			},
-			Model: "text-embedding-3-small",
-		})
+		resp, err := e.client.client.Embeddings.New(ctx, params)
 		if err != nil {
 			// Check if retryable (rate limit error)
 			if isRateLimitError(err) {
@@ -88,6 +177,11 @@ func (e *Embedder) embedBatchWithRetry(ctx context.Context, texts []string) ([][
 		embeddings = make([][]float32, len(resp.Data))
 		for i, data := range resp.Data {
 			embeddings[i] = toFloat32(data.Embedding)
+			// The index would reject vectors of the wrong size; say why up front
+			if e.dimension > 0 && len(embeddings[i]) != e.dimension {
+				return backoff.Permanent(fmt.Errorf("model %s returned %d-dimensional embeddings, expected %d (check EMBEDDING_DIMENSION)",
+					e.model, len(embeddings[i]), e.dimension))
+			}
 		}
 		return nil
 	}
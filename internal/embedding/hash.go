@@ -0,0 +1,84 @@
+package embedding
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"math"
+
+	"github.com/mike-a-ellis/eino-docs-mcp/internal/bm25"
+)
+
+// DefaultHashDimension is the vector size used by NewHashEmbedder when none is given.
+const DefaultHashDimension = 256
+
+// HashEmbedder is a deterministic, offline Embedder for tests and local development.
+// Each token is hashed into one of dimension buckets with a hashed sign ("feature
+// hashing"), and the vector is L2-normalized. Texts sharing words get a positive
+// cosine similarity, which is enough to exercise search end-to-end without a network.
+type HashEmbedder struct {
+	dimension int
+}
+
+// Compile-time interface check.
+var _ Embedder = (*HashEmbedder)(nil)
+
+// NewHashEmbedder creates a hashing embedder. If dimension is 0, DefaultHashDimension is used.
+func NewHashEmbedder(dimension int) *HashEmbedder {
+	if dimension <= 0 {
+		dimension = DefaultHashDimension
+	}
+	return &HashEmbedder{dimension: dimension}
+}
+
+// GenerateEmbeddings returns one normalized hashed bag-of-words vector per text.
+func (e *HashEmbedder) GenerateEmbeddings(ctx context.Context, texts []string) ([][]float32, error) {
+	embeddings := make([][]float32, len(texts))
+	for i, text := range texts {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		embeddings[i] = e.embed(text)
+	}
+	return embeddings, nil
+}
+
+// Model returns a name that encodes the dimension, so indexes built with different
+// sizes are never mixed.
+func (e *HashEmbedder) Model() string {
+	return fmt.Sprintf("hash-%d", e.dimension)
+}
+
+// Dimension returns the size of generated vectors.
+func (e *HashEmbedder) Dimension() int {
+	return e.dimension
+}
+
+// embed hashes the tokens of text into a normalized vector.
+func (e *HashEmbedder) embed(text string) []float32 {
+	vector := make([]float32, e.dimension)
+	for _, token := range bm25.Tokenize(text) {
+		h := fnv.New64a()
+		h.Write([]byte(token))
+		sum := h.Sum64()
+
+		sign := float32(1)
+		if sum>>63 == 1 {
+			sign = -1
+		}
+		vector[sum%uint64(e.dimension)] += sign
+	}
+
+	var norm float64
+	for _, v := range vector {
+		norm += float64(v) * float64(v)
+	}
+	if norm == 0 {
+		return vector
+	}
+	scale := float32(1 / math.Sqrt(norm))
+	for i := range vector {
+		vector[i] *= scale
+	}
+	return vector
+}
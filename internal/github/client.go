@@ -6,6 +6,8 @@ import (
 
 	"github.com/gofri/go-github-ratelimit/github_ratelimit"
 	"github.com/google/go-github/v81/github"
+
+	"github.com/mike-a-ellis/eino-docs-mcp/internal/ratelimit"
 )
 
 // Client wraps the GitHub API client with rate limiting support
@@ -19,8 +21,9 @@ type Client struct {
 func NewClient(ctx context.Context) (*Client, error) {
 	// Create rate limit handler with default configuration
 	// This handles both primary rate limits (5000 req/hour authenticated, 60 unauthenticated)
-	// and secondary rate limits (abuse detection) with automatic retry
-	rateLimiter, err := github_ratelimit.NewRateLimitWaiterClient(nil)
+	// and secondary rate limits (abuse detection) with automatic retry.
+	// The shared gate underneath makes concurrent callers back off together.
+	rateLimiter, err := github_ratelimit.NewRateLimitWaiterClient(ratelimit.NewTransport(nil, &ratelimit.Gate{}))
 	if err != nil {
 		return nil, err
 	}
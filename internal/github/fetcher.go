@@ -1,56 +1,98 @@
 package github
 
 import (
+	"archive/tar"
+	"compress/gzip"
 	"context"
 	"encoding/base64"
 	"fmt"
+	"io"
+	"net/http"
 	"path"
 	"strings"
+	"sync"
 
 	"github.com/google/go-github/v81/github"
-)
 
-// Repository configuration constants
-const (
-	DefaultOwner    = "cloudwego"
-	DefaultRepo     = "cloudwego.github.io"
-	DefaultBasePath = "content/en/docs/eino"
+	"github.com/mike-a-ellis/eino-docs-mcp/internal/source"
 )
 
-// FetchedDoc represents a markdown document fetched from GitHub
-type FetchedDoc struct {
-	Path       string // Relative path within docs directory
-	Content    string // Full markdown content
-	SHA        string // File's Git blob SHA
-	URL        string // GitHub raw URL
-}
-
-// Fetcher handles fetching documentation from GitHub repositories
+// Fetcher handles fetching documentation from GitHub repositories.
+// It implements source.Source with a handful of API requests per sync: Revision
+// resolves the latest commit and pins the fetcher to it, List reads that commit's
+// tree in one recursive Git Trees request, and Fetch serves documents from a single
+// tarball of the commit. Every document of a sync therefore comes from the same commit.
 type Fetcher struct {
 	client   *Client
 	owner    string
 	repo     string
+	ref      string
 	basePath string
+
+	mu       sync.Mutex
+	snapshot *snapshot // Pinned commit; replaced when Revision finds a new one
 }
 
-// NewFetcher creates a new document fetcher
-func NewFetcher(client *Client, owner, repo, basePath string) *Fetcher {
+// snapshot holds the documents of one commit, downloaded on first use.
+type snapshot struct {
+	commit string
+
+	once  sync.Once
+	files map[string]string // Relative path -> content, from the tarball
+	err   error             // Archive download failure; documents are then fetched one by one
+}
+
+var _ source.Source = (*Fetcher)(nil)
+
+// NewFetcher creates a fetcher for the docs directory and branch of a source.
+func NewFetcher(client *Client, cfg source.Config) *Fetcher {
+	ref := cfg.Ref
+	if ref == "" {
+		ref = source.DefaultRef
+	}
 	return &Fetcher{
 		client:   client,
-		owner:    owner,
-		repo:     repo,
-		basePath: basePath,
+		owner:    cfg.Owner,
+		repo:     cfg.Repo,
+		ref:      ref,
+		basePath: strings.Trim(cfg.BasePath, "/"),
 	}
 }
 
-// ListDocs recursively lists all markdown files in the repository directory
-func (f *Fetcher) ListDocs(ctx context.Context) ([]string, error) {
-	return f.listDocsRecursive(ctx, f.basePath, "")
+// List lists all markdown files of the pinned commit along with their blob SHAs,
+// using one recursive Git Trees request. Trees too large for GitHub to return in
+// full fall back to walking the docs directory with the Contents API.
+func (f *Fetcher) List(ctx context.Context) ([]source.Entry, error) {
+	snap, err := f.pinned(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tree, _, err := f.client.Git.GetTree(ctx, f.owner, f.repo, snap.commit, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tree of %s: %w", snap.commit, err)
+	}
+	if tree.GetTruncated() {
+		return f.listDocsRecursive(ctx, snap.commit, f.basePath, "")
+	}
+
+	var docs []source.Entry
+	for _, entry := range tree.Entries {
+		if entry.GetType() != "blob" {
+			continue
+		}
+		rel, ok := strings.CutPrefix(entry.GetPath(), f.basePath+"/")
+		if !ok || !strings.HasSuffix(rel, ".md") {
+			continue
+		}
+		docs = append(docs, source.Entry{Path: rel, SHA: entry.GetSHA()})
+	}
+	return docs, nil
 }
 
-// listDocsRecursive recursively traverses directories to find all .md files
-func (f *Fetcher) listDocsRecursive(ctx context.Context, fullPath, relativePath string) ([]string, error) {
-	var docs []string
+// listDocsRecursive recursively traverses directories at ref to find all .md files
+func (f *Fetcher) listDocsRecursive(ctx context.Context, ref, fullPath, relativePath string) ([]source.Entry, error) {
+	var docs []source.Entry
 
 	// Get directory contents
 	_, dirContents, _, err := f.client.Repositories.GetContents(
@@ -58,7 +100,7 @@ func (f *Fetcher) listDocsRecursive(ctx context.Context, fullPath, relativePath
 		f.owner,
 		f.repo,
 		fullPath,
-		nil,
+		&github.RepositoryContentGetOptions{Ref: ref},
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get contents of %s: %w", fullPath, err)
@@ -76,13 +118,16 @@ func (f *Fetcher) listDocsRecursive(ctx context.Context, fullPath, relativePath
 		case "file":
 			// Only include markdown files
 			if strings.HasSuffix(*item.Name, ".md") {
-				docs = append(docs, itemRelPath)
+				docs = append(docs, source.Entry{
+					Path: itemRelPath,
+					SHA:  item.GetSHA(),
+				})
 			}
 
 		case "dir":
 			// Recursively process subdirectories
 			itemFullPath := path.Join(fullPath, *item.Name)
-			subDocs, err := f.listDocsRecursive(ctx, itemFullPath, itemRelPath)
+			subDocs, err := f.listDocsRecursive(ctx, ref, itemFullPath, itemRelPath)
 			if err != nil {
 				return nil, err
 			}
@@ -93,8 +138,52 @@ func (f *Fetcher) listDocsRecursive(ctx context.Context, fullPath, relativePath
 	return docs, nil
 }
 
-// FetchDoc fetches the content of a specific markdown file
-func (f *Fetcher) FetchDoc(ctx context.Context, relativePath string) (*FetchedDoc, error) {
+// Fetch returns a markdown file of the pinned commit. The first call downloads the
+// commit's tarball, which serves every later call; if the download fails, files are
+// fetched individually with the Contents API at the same commit.
+func (f *Fetcher) Fetch(ctx context.Context, relativePath string) (*source.Document, error) {
+	snap, err := f.pinned(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	snap.once.Do(func() {
+		snap.files, snap.err = f.downloadArchive(ctx, snap.commit)
+	})
+
+	var content []byte
+	if snap.err == nil {
+		text, ok := snap.files[relativePath]
+		if !ok {
+			return nil, fmt.Errorf("%s not found at commit %s", path.Join(f.basePath, relativePath), snap.commit)
+		}
+		content = []byte(text)
+	} else {
+		content, err = f.fetchContents(ctx, snap.commit, relativePath)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Permalink to the file at the pinned commit
+	permalink := fmt.Sprintf(
+		"https://github.com/%s/%s/blob/%s/%s",
+		f.owner,
+		f.repo,
+		snap.commit,
+		path.Join(f.basePath, relativePath),
+	)
+
+	return &source.Document{
+		Path:    relativePath,
+		Content: string(content),
+		SHA:     source.BlobSHA(content),
+		URL:     permalink,
+	}, nil
+}
+
+// fetchContents fetches one file at ref with the Contents API.
+func (f *Fetcher) fetchContents(ctx context.Context, ref, relativePath string) ([]byte, error) {
 	fullPath := path.Join(f.basePath, relativePath)
 
 	// Get file content from GitHub
@@ -103,7 +192,7 @@ func (f *Fetcher) FetchDoc(ctx context.Context, relativePath string) (*FetchedDo
 		f.owner,
 		f.repo,
 		fullPath,
-		nil,
+		&github.RepositoryContentGetOptions{Ref: ref},
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get content of %s: %w", fullPath, err)
@@ -118,30 +207,94 @@ func (f *Fetcher) FetchDoc(ctx context.Context, relativePath string) (*FetchedDo
 	if err != nil {
 		return nil, fmt.Errorf("failed to decode content of %s: %w", fullPath, err)
 	}
+	return content, nil
+}
 
-	// Build GitHub raw URL
-	rawURL := fmt.Sprintf(
-		"https://raw.githubusercontent.com/%s/%s/main/%s",
-		f.owner,
-		f.repo,
-		fullPath,
-	)
+// downloadArchive downloads the tarball of commit and returns the markdown files
+// under the docs directory, keyed by relative path. Only the redirect to the archive
+// counts against the API rate limit; the download itself does not.
+func (f *Fetcher) downloadArchive(ctx context.Context, commit string) (map[string]string, error) {
+	link, _, err := f.client.Repositories.GetArchiveLink(ctx, f.owner, f.repo, github.Tarball,
+		&github.RepositoryContentGetOptions{Ref: commit}, 1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get archive link: %w", err)
+	}
 
-	return &FetchedDoc{
-		Path:    relativePath,
-		Content: string(content),
-		SHA:     *fileContent.SHA,
-		URL:     rawURL,
-	}, nil
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, link.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := f.client.Client.Client().Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download archive: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download archive: %s", resp.Status)
+	}
+
+	gz, err := gzip.NewReader(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read archive: %w", err)
+	}
+	defer gz.Close()
+
+	// Entries are prefixed with a top-level "{owner}-{repo}-{sha}/" directory
+	files := make(map[string]string)
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read archive: %w", err)
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		_, repoPath, _ := strings.Cut(header.Name, "/")
+		rel, ok := strings.CutPrefix(repoPath, f.basePath+"/")
+		if !ok || !strings.HasSuffix(rel, ".md") {
+			continue
+		}
+		content, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s from archive: %w", repoPath, err)
+		}
+		files[rel] = string(content)
+	}
+	return files, nil
 }
 
-// GetLatestCommitSHA retrieves the SHA of the most recent commit affecting the docs directory
-func (f *Fetcher) GetLatestCommitSHA(ctx context.Context) (string, error) {
+// pinned returns the pinned commit's snapshot, resolving the latest commit first if
+// Revision has not been called.
+func (f *Fetcher) pinned(ctx context.Context) (*snapshot, error) {
+	f.mu.Lock()
+	snap := f.snapshot
+	f.mu.Unlock()
+	if snap != nil {
+		return snap, nil
+	}
+
+	if _, err := f.Revision(ctx); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.snapshot, nil
+}
+
+// Revision retrieves the SHA of the most recent commit on the source's branch affecting
+// the docs directory and pins List and Fetch to it. Later commits only touch other
+// parts of the repository, so the docs at this commit match the branch head.
+func (f *Fetcher) Revision(ctx context.Context) (string, error) {
 	commits, _, err := f.client.Repositories.ListCommits(
 		ctx,
 		f.owner,
 		f.repo,
 		&github.CommitsListOptions{
+			SHA:  f.ref,
 			Path: f.basePath,
 			ListOptions: github.ListOptions{
 				PerPage: 1,
@@ -160,5 +313,11 @@ func (f *Fetcher) GetLatestCommitSHA(ctx context.Context) (string, error) {
 		return "", fmt.Errorf("commit SHA is nil")
 	}
 
-	return *commits[0].SHA, nil
+	sha := *commits[0].SHA
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.snapshot == nil || f.snapshot.commit != sha {
+		f.snapshot = &snapshot{commit: sha}
+	}
+	return sha, nil
 }
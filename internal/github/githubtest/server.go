@@ -0,0 +1,326 @@
+// Package githubtest provides an in-memory fake of the GitHub REST API for tests.
+package githubtest
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/go-github/v81/github"
+
+	ghclient "github.com/mike-a-ellis/eino-docs-mcp/internal/github"
+)
+
+// Server fakes the GitHub endpoints used by the fetcher and the MCP server:
+// repository contents, git trees, tarball archives, commit listing and commit
+// comparison. Files live in memory and can be changed between calls to simulate
+// upstream edits. The fake keeps no history: every known commit serves the current
+// files.
+type Server struct {
+	*httptest.Server
+	owner string
+	repo  string
+
+	mu              sync.Mutex
+	files           map[string]string // Full repository path -> content
+	commits         []string          // Commit SHAs, newest first
+	requests        int               // REST API requests served, excluding archive downloads
+	archiveDisabled bool
+}
+
+// NewServer starts a fake for owner/repo with a single initial commit.
+// The server is closed when the test finishes.
+func NewServer(t testing.TB, owner, repo string) *Server {
+	s := &Server{
+		owner:   owner,
+		repo:    repo,
+		files:   make(map[string]string),
+		commits: []string{"0000000000000000000000000000000000000001"},
+	}
+
+	mux := http.NewServeMux()
+	prefix := fmt.Sprintf("/repos/%s/%s/", owner, repo)
+	mux.HandleFunc(prefix+"contents/", s.handleContents(prefix+"contents/"))
+	mux.HandleFunc(prefix+"git/trees/", s.handleTrees(prefix+"git/trees/"))
+	mux.HandleFunc(prefix+"tarball/", s.handleTarball(prefix+"tarball/"))
+	mux.HandleFunc(prefix+"commits", s.handleCommits)
+	mux.HandleFunc(prefix+"compare/", s.handleCompare(prefix+"compare/"))
+	api := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		s.requests++
+		s.mu.Unlock()
+		mux.ServeHTTP(w, r)
+	})
+
+	root := http.NewServeMux()
+	root.Handle("/", api)
+	root.HandleFunc(fmt.Sprintf("/codeload/%s/%s/tar.gz/", owner, repo), s.handleCodeload)
+
+	s.Server = httptest.NewServer(root)
+	t.Cleanup(s.Close)
+	return s
+}
+
+// Client returns a GitHub client that talks to the fake.
+func (s *Server) Client() *ghclient.Client {
+	client := github.NewClient(s.Server.Client())
+	client.BaseURL, _ = url.Parse(s.URL + "/")
+	return &ghclient.Client{Client: client}
+}
+
+// SetFile creates or replaces a file at its full repository path.
+func (s *Server) SetFile(filePath, content string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.files[filePath] = content
+}
+
+// DeleteFile removes a file.
+func (s *Server) DeleteFile(filePath string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.files, filePath)
+}
+
+// Commit records a new head commit.
+func (s *Server) Commit(sha string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.commits = append([]string{sha}, s.commits...)
+}
+
+// Requests returns the number of REST API requests served so far. Archive downloads
+// do not count, as on GitHub they are not rate limited.
+func (s *Server) Requests() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests
+}
+
+// DisableArchive makes tarball requests fail, as when archives are unavailable.
+func (s *Server) DisableArchive() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.archiveDisabled = true
+}
+
+// HeadSHA returns the newest commit SHA.
+func (s *Server) HeadSHA() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.commits[0]
+}
+
+// BlobSHA returns the Git blob SHA of content, as GitHub reports it.
+func BlobSHA(content string) string {
+	h := sha1.New()
+	fmt.Fprintf(h, "blob %d\x00", len(content))
+	h.Write([]byte(content))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// handleContents serves GET /repos/{owner}/{repo}/contents/{path} for files and directories.
+func (s *Server) handleContents(prefix string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		target := strings.Trim(strings.TrimPrefix(r.URL.Path, prefix), "/")
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		if content, ok := s.files[target]; ok {
+			writeJSON(w, &github.RepositoryContent{
+				Type:     github.Ptr("file"),
+				Name:     github.Ptr(path.Base(target)),
+				Path:     github.Ptr(target),
+				SHA:      github.Ptr(BlobSHA(content)),
+				Encoding: github.Ptr("base64"),
+				Content:  github.Ptr(base64.StdEncoding.EncodeToString([]byte(content))),
+			})
+			return
+		}
+
+		// Directory listing: immediate children of target
+		entries := make(map[string]*github.RepositoryContent)
+		for filePath, content := range s.files {
+			rest, ok := strings.CutPrefix(filePath, target+"/")
+			if !ok {
+				continue
+			}
+			name, _, isDir := strings.Cut(rest, "/")
+			if isDir {
+				entries[name] = &github.RepositoryContent{
+					Type: github.Ptr("dir"),
+					Name: github.Ptr(name),
+					Path: github.Ptr(path.Join(target, name)),
+				}
+				continue
+			}
+			entries[name] = &github.RepositoryContent{
+				Type: github.Ptr("file"),
+				Name: github.Ptr(name),
+				Path: github.Ptr(filePath),
+				SHA:  github.Ptr(BlobSHA(content)),
+			}
+		}
+		if len(entries) == 0 {
+			http.Error(w, `{"message": "Not Found"}`, http.StatusNotFound)
+			return
+		}
+
+		names := make([]string, 0, len(entries))
+		for name := range entries {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		listing := make([]*github.RepositoryContent, len(names))
+		for i, name := range names {
+			listing[i] = entries[name]
+		}
+		writeJSON(w, listing)
+	}
+}
+
+// handleTrees serves GET /repos/{owner}/{repo}/git/trees/{sha}?recursive=1 for a
+// known commit, listing every file as a blob.
+func (s *Server) handleTrees(prefix string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		sha := strings.TrimPrefix(r.URL.Path, prefix)
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		if !s.hasCommit(sha) {
+			http.Error(w, `{"message": "Not Found"}`, http.StatusNotFound)
+			return
+		}
+		tree := &github.Tree{SHA: github.Ptr(sha), Truncated: github.Ptr(false)}
+		for _, filePath := range s.sortedPaths() {
+			tree.Entries = append(tree.Entries, &github.TreeEntry{
+				Path: github.Ptr(filePath),
+				Type: github.Ptr("blob"),
+				SHA:  github.Ptr(BlobSHA(s.files[filePath])),
+			})
+		}
+		writeJSON(w, tree)
+	}
+}
+
+// handleTarball serves GET /repos/{owner}/{repo}/tarball/{ref} like GitHub: a
+// redirect to the archive download.
+func (s *Server) handleTarball(prefix string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ref := strings.TrimPrefix(r.URL.Path, prefix)
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		if s.archiveDisabled || !s.hasCommit(ref) {
+			http.Error(w, `{"message": "Not Found"}`, http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Location", fmt.Sprintf("%s/codeload/%s/%s/tar.gz/%s", s.URL, s.owner, s.repo, ref))
+		w.WriteHeader(http.StatusFound)
+	}
+}
+
+// handleCodeload serves the gzipped tarball of the current files, each under a
+// top-level "{owner}-{repo}-{ref}/" directory.
+func (s *Server) handleCodeload(w http.ResponseWriter, r *http.Request) {
+	ref := path.Base(r.URL.Path)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	for _, filePath := range s.sortedPaths() {
+		content := s.files[filePath]
+		_ = tw.WriteHeader(&tar.Header{
+			Name:     fmt.Sprintf("%s-%s-%s/%s", s.owner, s.repo, ref, filePath),
+			Mode:     0o644,
+			Size:     int64(len(content)),
+			Typeflag: tar.TypeReg,
+		})
+		_, _ = tw.Write([]byte(content))
+	}
+	_ = tw.Close()
+	_ = gz.Close()
+}
+
+// hasCommit reports whether sha is a known commit. Callers hold s.mu.
+func (s *Server) hasCommit(sha string) bool {
+	for _, commit := range s.commits {
+		if commit == sha {
+			return true
+		}
+	}
+	return false
+}
+
+// sortedPaths returns the file paths in order. Callers hold s.mu.
+func (s *Server) sortedPaths() []string {
+	paths := make([]string, 0, len(s.files))
+	for filePath := range s.files {
+		paths = append(paths, filePath)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// handleCommits serves GET /repos/{owner}/{repo}/commits, newest first.
+func (s *Server) handleCommits(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	commits := s.commits
+	if perPage, err := strconv.Atoi(r.URL.Query().Get("per_page")); err == nil && perPage < len(commits) {
+		commits = commits[:perPage]
+	}
+
+	result := make([]*github.RepositoryCommit, len(commits))
+	for i, sha := range commits {
+		result[i] = &github.RepositoryCommit{SHA: github.Ptr(sha)}
+	}
+	writeJSON(w, result)
+}
+
+// handleCompare serves GET /repos/{owner}/{repo}/compare/{base}...{head}. Any head
+// is treated as the newest commit; ahead_by counts commits made after base.
+func (s *Server) handleCompare(prefix string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		base, _, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, prefix), "...")
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		for i, sha := range s.commits {
+			if sha == base {
+				writeJSON(w, &github.CommitsComparison{
+					Status:  github.Ptr("ahead"),
+					AheadBy: github.Ptr(i),
+				})
+				return
+			}
+		}
+		http.Error(w, `{"message": "Not Found"}`, http.StatusNotFound)
+	}
+}
+
+// writeJSON encodes v as the response body.
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
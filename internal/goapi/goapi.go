@@ -0,0 +1,374 @@
+// Package goapi extracts the exported API of a Go module (types, interfaces, functions
+// and methods with their signatures and doc comments) using go/parser and go/doc.
+package goapi
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"go/ast"
+	"go/doc"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Symbol kinds.
+const (
+	KindType      = "type"
+	KindInterface = "interface"
+	KindFunc      = "func"
+	KindMethod    = "method"
+)
+
+// Symbol is one exported declaration.
+type Symbol struct {
+	Name        string // Declared name; methods are "Recv.Method"
+	Kind        string // KindType, KindInterface, KindFunc or KindMethod
+	Package     string // Import path: "github.com/cloudwego/eino/compose"
+	PackageName string // Package clause name: "compose"
+	Signature   string // Declaration without body or doc comment
+	Doc         string // Doc comment text
+	File        string // Source file relative to the module root
+	Line        int    // Line of the declaration in File
+}
+
+// sourceFile is a Go file or go.mod read from a checkout or archive.
+type sourceFile struct {
+	path string // Slash-separated, relative to the archive or directory root
+	src  []byte
+}
+
+// ParseDir extracts the exported API of the module checked out at root.
+func ParseDir(root string) ([]Symbol, error) {
+	var files []sourceFile
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if d.IsDir() {
+			if rel != "." && skipDir(d.Name()) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !wanted(rel) {
+			return nil
+		}
+		src, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		files = append(files, sourceFile{path: rel, src: src})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", root, err)
+	}
+	return parseFiles(files)
+}
+
+// ParseTarball extracts the exported API of a module from a gzipped tar archive, such
+// as a GitHub source tarball. The module root is the directory of the shallowest go.mod,
+// so the archive's top-level directory ("eino-1.2.3/") needs no special handling.
+func ParseTarball(r io.Reader) ([]Symbol, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open gzip stream: %w", err)
+	}
+	defer gz.Close()
+
+	var files []sourceFile
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read tarball: %w", err)
+		}
+		name := strings.TrimPrefix(path.Clean(header.Name), "./")
+		if header.Typeflag != tar.TypeReg || !wanted(name) || skipPath(path.Dir(name)) {
+			continue
+		}
+		src, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", name, err)
+		}
+		files = append(files, sourceFile{path: name, src: src})
+	}
+	return parseFiles(files)
+}
+
+// ParsePath extracts the API from a directory, or from a .tar.gz/.tgz archive.
+func ParsePath(p string) ([]Symbol, error) {
+	if !strings.HasSuffix(p, ".tar.gz") && !strings.HasSuffix(p, ".tgz") {
+		return ParseDir(p)
+	}
+	f, err := os.Open(p)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ParseTarball(f)
+}
+
+// wanted reports whether a file is needed: non-test Go files and go.mod.
+func wanted(name string) bool {
+	base := path.Base(name)
+	return base == "go.mod" || (strings.HasSuffix(base, ".go") && !strings.HasSuffix(base, "_test.go"))
+}
+
+// skipDir reports whether a directory holds no public API: internal packages, test
+// data, vendored code, examples, and hidden or underscore directories the go tool ignores.
+func skipDir(name string) bool {
+	switch name {
+	case "internal", "testdata", "vendor", "examples":
+		return true
+	}
+	return strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")
+}
+
+// skipPath reports whether any directory in a slash-separated path is skipped.
+func skipPath(dir string) bool {
+	for _, name := range strings.Split(dir, "/") {
+		if name != "." && skipDir(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseFiles locates the module root and extracts every package under it.
+func parseFiles(files []sourceFile) ([]Symbol, error) {
+	root, modulePath := "", ""
+	for _, f := range files {
+		if path.Base(f.path) != "go.mod" {
+			continue
+		}
+		dir := path.Dir(f.path)
+		if modulePath == "" || strings.Count(dir, "/") < strings.Count(root, "/") {
+			root, modulePath = dir, moduleName(f.src)
+		}
+	}
+	if modulePath == "" {
+		return nil, fmt.Errorf("no go.mod with a module path found")
+	}
+
+	// Group files by package directory, relative to the module root. Nested modules
+	// have their own go.mod and are left out.
+	nested := make(map[string]bool)
+	byDir := make(map[string][]sourceFile)
+	for _, f := range files {
+		rel, ok := relativeTo(root, f.path)
+		if !ok {
+			continue
+		}
+		if path.Base(rel) == "go.mod" {
+			if dir := path.Dir(rel); dir != "." {
+				nested[dir] = true
+			}
+			continue
+		}
+		byDir[path.Dir(rel)] = append(byDir[path.Dir(rel)], sourceFile{path: rel, src: f.src})
+	}
+
+	dirs := make([]string, 0, len(byDir))
+	for dir := range byDir {
+		if !insideAny(dir, nested) {
+			dirs = append(dirs, dir)
+		}
+	}
+	sort.Strings(dirs)
+
+	var symbols []Symbol
+	for _, dir := range dirs {
+		importPath := modulePath
+		if dir != "." {
+			importPath += "/" + dir
+		}
+		pkgSymbols, err := parsePackage(importPath, byDir[dir])
+		if err != nil {
+			return nil, fmt.Errorf("package %s: %w", importPath, err)
+		}
+		symbols = append(symbols, pkgSymbols...)
+	}
+	return symbols, nil
+}
+
+// parsePackage extracts the exported declarations of one package directory.
+// Files of package main and external test packages are ignored.
+func parsePackage(importPath string, files []sourceFile) ([]Symbol, error) {
+	fset := token.NewFileSet()
+	var parsed []*ast.File
+	for _, f := range files {
+		file, err := parser.ParseFile(fset, f.path, f.src, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+		if name := file.Name.Name; name == "main" || strings.HasSuffix(name, "_test") {
+			continue
+		}
+		parsed = append(parsed, file)
+	}
+	if len(parsed) == 0 {
+		return nil, nil
+	}
+
+	pkg, err := doc.NewFromFiles(fset, parsed, importPath)
+	if err != nil {
+		return nil, err
+	}
+
+	e := extractor{fset: fset, pkg: pkg}
+	for _, fn := range pkg.Funcs {
+		e.addFunc(fn, "")
+	}
+	for _, t := range pkg.Types {
+		e.addType(t)
+		for _, fn := range t.Funcs {
+			e.addFunc(fn, "") // Constructors are package-level functions
+		}
+		for _, fn := range t.Methods {
+			e.addFunc(fn, t.Name)
+		}
+	}
+	return e.symbols, nil
+}
+
+// extractor accumulates the symbols of one package.
+type extractor struct {
+	fset    *token.FileSet
+	pkg     *doc.Package
+	symbols []Symbol
+}
+
+// addType records a type declaration; interfaces get their own kind.
+func (e *extractor) addType(t *doc.Type) {
+	kind := KindType
+	var spec *ast.TypeSpec
+	for _, s := range t.Decl.Specs {
+		if ts, ok := s.(*ast.TypeSpec); ok && ts.Name.Name == t.Name {
+			spec = ts
+		}
+	}
+	if spec == nil {
+		return
+	}
+	if _, ok := spec.Type.(*ast.InterfaceType); ok {
+		kind = KindInterface
+	}
+
+	// Print just this type, even when it was declared in a grouped type (...) block
+	decl := &ast.GenDecl{Tok: token.TYPE, Specs: []ast.Spec{stripSpecComments(spec)}}
+	e.add(t.Name, kind, t.Doc, decl, spec.Pos())
+}
+
+// addFunc records a function, or a method when recv is set.
+func (e *extractor) addFunc(fn *doc.Func, recv string) {
+	if !token.IsExported(fn.Name) {
+		return
+	}
+	kind, name := KindFunc, fn.Name
+	if recv != "" {
+		kind, name = KindMethod, recv+"."+fn.Name
+	}
+	decl := *fn.Decl
+	decl.Doc, decl.Body = nil, nil
+	e.add(name, kind, fn.Doc, &decl, fn.Decl.Pos())
+}
+
+// add appends a symbol with its printed declaration.
+func (e *extractor) add(name, kind, docText string, decl ast.Node, pos token.Pos) {
+	var buf bytes.Buffer
+	if err := (&printer.Config{Mode: printer.UseSpaces | printer.TabIndent, Tabwidth: 8}).Fprint(&buf, e.fset, decl); err != nil {
+		return
+	}
+	position := e.fset.Position(pos)
+	e.symbols = append(e.symbols, Symbol{
+		Name:        name,
+		Kind:        kind,
+		Package:     e.pkg.ImportPath,
+		PackageName: e.pkg.Name,
+		Signature:   buf.String(),
+		Doc:         strings.TrimSpace(docText),
+		File:        position.Filename,
+		Line:        position.Line,
+	})
+}
+
+// stripSpecComments returns a copy of a type spec without the doc and line comments
+// of its fields and methods, which the printer would otherwise misplace.
+func stripSpecComments(spec *ast.TypeSpec) *ast.TypeSpec {
+	c := *spec
+	c.Doc, c.Comment = nil, nil
+	switch t := spec.Type.(type) {
+	case *ast.StructType:
+		s := *t
+		s.Fields = copyFields(t.Fields)
+		c.Type = &s
+	case *ast.InterfaceType:
+		i := *t
+		i.Methods = copyFields(t.Methods)
+		c.Type = &i
+	}
+	return &c
+}
+
+// copyFields copies a field list, dropping field comments.
+func copyFields(list *ast.FieldList) *ast.FieldList {
+	if list == nil {
+		return nil
+	}
+	c := *list
+	c.List = make([]*ast.Field, len(list.List))
+	for i, field := range list.List {
+		f := *field
+		f.Doc, f.Comment = nil, nil
+		c.List[i] = &f
+	}
+	return &c
+}
+
+// moduleName returns the module path declared in a go.mod file.
+func moduleName(gomod []byte) string {
+	for _, line := range strings.Split(string(gomod), "\n") {
+		if rest, ok := strings.CutPrefix(strings.TrimSpace(line), "module"); ok {
+			return strings.Trim(strings.TrimSpace(rest), `"`)
+		}
+	}
+	return ""
+}
+
+// relativeTo returns p relative to the slash-separated directory root.
+func relativeTo(root, p string) (string, bool) {
+	if root == "." {
+		return p, true
+	}
+	rest, ok := strings.CutPrefix(p, root+"/")
+	return rest, ok
+}
+
+// insideAny reports whether dir is one of dirs or below one of them.
+func insideAny(dir string, dirs map[string]bool) bool {
+	for d := range dirs {
+		if dir == d || strings.HasPrefix(dir, d+"/") {
+			return true
+		}
+	}
+	return false
+}
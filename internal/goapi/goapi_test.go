@@ -0,0 +1,150 @@
+package goapi
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// testModule is a small module with exported and unexported declarations, an internal
+// package, a command and a test file.
+var testModule = map[string]string{
+	"go.mod": "module github.com/cloudwego/eino\n\ngo 1.21\n",
+	"compose/graph.go": `package compose
+
+import "context"
+
+// Graph orchestrates nodes.
+type Graph[I, O any] struct {
+	// Name is the graph name.
+	Name  string
+	nodes map[string]any
+}
+
+// NewGraph creates a graph.
+func NewGraph[I, O any](opts ...Option) *Graph[I, O] {
+	return &Graph[I, O]{}
+}
+
+// Compile compiles the graph.
+func (g *Graph[I, O]) Compile(ctx context.Context) (Runnable[I, O], error) {
+	return nil, nil
+}
+
+func (g *Graph[I, O]) compile() {}
+
+type (
+	// Option configures a graph.
+	Option  func(*options)
+	options struct{}
+)
+
+// Runnable is a compiled graph.
+type Runnable[I, O any] interface {
+	// Invoke runs the graph.
+	Invoke(ctx context.Context, input I) (O, error)
+}
+`,
+	"compose/graph_test.go":   "package compose\n\nfunc TestHelper() {}\n",
+	"internal/safe/safe.go":   "package safe\n\nfunc Go() {}\n",
+	"cmd/tool/main.go":        "package main\n\nfunc Run() {}\n",
+	"schema/message.go":       "package schema\n\n// Message is a chat message.\ntype Message struct {\n\tContent string\n}\n",
+	"nested/go.mod":           "module github.com/cloudwego/eino/nested\n",
+	"nested/pkg/nested.go":    "package pkg\n\nfunc Nested() {}\n",
+	"examples/demo/demo.go":   "package demo\n\nfunc Demo() {}\n",
+	"compose/.hidden/skip.go": "package hidden\n\nfunc Skip() {}\n",
+}
+
+// TestParseDir verifies exported declarations are extracted with signatures, docs and
+// positions, and that internal, command, test and nested-module code is skipped.
+func TestParseDir(t *testing.T) {
+	root := t.TempDir()
+	for name, content := range testModule {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	symbols, err := ParseDir(root)
+	if err != nil {
+		t.Fatalf("ParseDir: %v", err)
+	}
+	checkSymbols(t, symbols)
+}
+
+// TestParseTarball verifies archives with a top-level directory are parsed like a checkout.
+func TestParseTarball(t *testing.T) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, content := range testModule {
+		header := &tar.Header{Name: "eino-1.2.3/" + name, Mode: 0o644, Size: int64(len(content)), Typeflag: tar.TypeReg}
+		if err := tw.WriteHeader(header); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	symbols, err := ParseTarball(&buf)
+	if err != nil {
+		t.Fatalf("ParseTarball: %v", err)
+	}
+	checkSymbols(t, symbols)
+}
+
+// checkSymbols asserts the symbols extracted from testModule.
+func checkSymbols(t *testing.T, symbols []Symbol) {
+	t.Helper()
+
+	byName := make(map[string]Symbol)
+	for _, s := range symbols {
+		byName[s.PackageName+"."+s.Name] = s
+	}
+	want := []string{"compose.Graph", "compose.NewGraph", "compose.Graph.Compile", "compose.Option", "compose.Runnable", "schema.Message"}
+	if len(symbols) != len(want) {
+		t.Errorf("expected %d symbols, got %d: %v", len(want), len(symbols), symbols)
+	}
+	for _, name := range want {
+		if _, ok := byName[name]; !ok {
+			t.Errorf("missing symbol %s", name)
+		}
+	}
+
+	compile := byName["compose.Graph.Compile"]
+	if compile.Kind != KindMethod || compile.Package != "github.com/cloudwego/eino/compose" {
+		t.Errorf("Compile: kind %q package %q", compile.Kind, compile.Package)
+	}
+	if compile.Signature != "func (g *Graph[I, O]) Compile(ctx context.Context) (Runnable[I, O], error)" {
+		t.Errorf("Compile signature: %q", compile.Signature)
+	}
+	if compile.Doc != "Compile compiles the graph." || compile.File != "compose/graph.go" || compile.Line != 18 {
+		t.Errorf("Compile doc/position: %q %s:%d", compile.Doc, compile.File, compile.Line)
+	}
+
+	if kind := byName["compose.Runnable"].Kind; kind != KindInterface {
+		t.Errorf("Runnable kind: %q", kind)
+	}
+	graph := byName["compose.Graph"].Signature
+	if !strings.Contains(graph, "Name string") || strings.Contains(graph, "nodes") || strings.Contains(graph, "graph name") {
+		t.Errorf("Graph signature should list exported fields only, without comments: %q", graph)
+	}
+	if option := byName["compose.Option"].Signature; option != "type Option func(*options)" {
+		t.Errorf("grouped type signature: %q", option)
+	}
+}
@@ -4,22 +4,32 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"sort"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
 
+	"github.com/mike-a-ellis/eino-docs-mcp/internal/bm25"
 	"github.com/mike-a-ellis/eino-docs-mcp/internal/embedding"
-	"github.com/mike-a-ellis/eino-docs-mcp/internal/github"
 	"github.com/mike-a-ellis/eino-docs-mcp/internal/markdown"
 	"github.com/mike-a-ellis/eino-docs-mcp/internal/metadata"
+	"github.com/mike-a-ellis/eino-docs-mcp/internal/source"
 	"github.com/mike-a-ellis/eino-docs-mcp/internal/storage"
 )
 
+// DefaultConcurrency is the number of documents processed in parallel. Each document
+// costs a GitHub fetch, a chat completion and an embedding request, so a handful of
+// workers keeps the APIs busy without tripping their rate limits.
+const DefaultConcurrency = 4
+
 // IndexResult contains statistics about an indexing operation.
 type IndexResult struct {
 	TotalDocs      int
 	TotalChunks    int
 	SuccessfulDocs int
+	SkippedDocs    int      // Unchanged documents (not counted by IndexAll)
+	DeletedDocs    []string // Paths removed because they no longer exist upstream (not set by IndexAll)
 	FailedDocs     []FailedDoc
 	CommitSHA      string
 	Duration       time.Duration
@@ -27,50 +37,116 @@ type IndexResult struct {
 
 // FailedDoc represents a document that failed to index.
 type FailedDoc struct {
+	Source string // Name of the source the document belongs to
 	Path   string
 	Reason string
 }
 
+// MetadataGenerator produces a document's summary and entity list.
+// *metadata.Generator calls an LLM; metadata.StubGenerator returns canned responses.
+type MetadataGenerator interface {
+	GenerateMetadata(ctx context.Context, path, content string) (*metadata.DocumentMetadata, error)
+}
+
 // Pipeline orchestrates the full indexing process from fetching to storage.
 type Pipeline struct {
-	fetcher   *github.Fetcher
-	chunker   *markdown.Chunker
-	embedder  *embedding.Embedder
-	generator *metadata.Generator
-	storage   *storage.QdrantStorage
-	logger    *slog.Logger
+	source      source.Source
+	config      source.Config // Source the documents are recorded under
+	chunker     *markdown.Chunker
+	embedder    embedding.Embedder
+	generator   MetadataGenerator
+	storage     storage.Store
+	logger      *slog.Logger
+	concurrency int
 }
 
 // NewPipeline creates a new indexing pipeline with the given components.
 func NewPipeline(
-	fetcher *github.Fetcher,
+	src source.Source,
 	chunker *markdown.Chunker,
-	embedder *embedding.Embedder,
-	generator *metadata.Generator,
-	storage *storage.QdrantStorage,
+	embedder embedding.Embedder,
+	generator MetadataGenerator,
+	storage storage.Store,
 	logger *slog.Logger,
 ) *Pipeline {
 	if logger == nil {
 		logger = slog.Default()
 	}
 	return &Pipeline{
-		fetcher:   fetcher,
-		chunker:   chunker,
-		embedder:  embedder,
-		generator: generator,
-		storage:   storage,
-		logger:    logger,
+		source:      src,
+		config:      source.DefaultConfig,
+		chunker:     chunker,
+		embedder:    embedder,
+		generator:   generator,
+		storage:     storage,
+		logger:      logger,
+		concurrency: DefaultConcurrency,
+	}
+}
+
+// WithConcurrency returns a copy of the pipeline that processes up to n documents in
+// parallel. Values below 1 keep the current setting.
+func (p *Pipeline) WithConcurrency(n int) *Pipeline {
+	c := *p
+	if n > 0 {
+		c.concurrency = n
+	}
+	return &c
+}
+
+// WithSource returns a copy of the pipeline that reads documents from src.
+func (p *Pipeline) WithSource(src source.Source) *Pipeline {
+	c := *p
+	c.source = src
+	return &c
+}
+
+// WithConfig returns a copy of the pipeline that records documents under the source
+// cfg describes: its name scopes every read and write of the index, so sources
+// sharing a store do not touch each other's documents.
+func (p *Pipeline) WithConfig(cfg source.Config) *Pipeline {
+	c := *p
+	c.config = cfg
+	c.logger = p.logger.With("source", cfg.Name)
+	return &c
+}
+
+// Config returns the source the pipeline records documents under.
+func (p *Pipeline) Config() source.Config {
+	return p.config
+}
+
+// Revision returns the source's current revision without indexing anything.
+func (p *Pipeline) Revision(ctx context.Context) (string, error) {
+	return p.source.Revision(ctx)
+}
+
+// IndexedRevision returns the revision recorded by the last completed sync, or ""
+// when the index has never been synced.
+func (p *Pipeline) IndexedRevision(ctx context.Context) (string, error) {
+	return p.storage.GetCommitSHA(ctx, p.config.Name)
+}
+
+// Flush writes buffered index changes to disk when the store buffers them (the
+// embedded store), so other processes sharing the index file see them. Other stores
+// write through and need no flush.
+func (p *Pipeline) Flush() error {
+	if f, ok := p.storage.(interface{ Flush() error }); ok {
+		return f.Flush()
 	}
+	return nil
 }
 
-// IndexAll fetches all documents from GitHub and indexes them in Qdrant.
+// IndexAll fetches all documents from the source and indexes them in the store.
 // Returns detailed statistics about the indexing operation.
+// Cancelling ctx stops handing out documents and returns the context's error once
+// in-flight documents have wound down.
 func (p *Pipeline) IndexAll(ctx context.Context) (*IndexResult, error) {
 	start := time.Now()
 	result := &IndexResult{}
 
 	// 1. Get latest commit SHA
-	commitSHA, err := p.fetcher.GetLatestCommitSHA(ctx)
+	commitSHA, err := p.source.Revision(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("get commit SHA: %w", err)
 	}
@@ -78,27 +154,40 @@ func (p *Pipeline) IndexAll(ctx context.Context) (*IndexResult, error) {
 	p.logger.Info("Starting indexing", "commit", commitSHA)
 
 	// 2. List all docs
-	paths, err := p.fetcher.ListDocs(ctx)
+	entries, err := p.source.List(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("list docs: %w", err)
 	}
+	paths := make([]string, len(entries))
+	for i, entry := range entries {
+		paths[i] = entry.Path
+	}
 	result.TotalDocs = len(paths)
-	p.logger.Info("Found documents", "count", len(paths))
+	p.logger.Info("Found documents", "count", len(paths), "workers", p.concurrency)
 
-	// 3. Process each document
-	for _, path := range paths {
+	// 3. Process documents in parallel
+	var mu sync.Mutex // Guards result
+	err = p.forEach(ctx, paths, func(path string) {
 		chunks, err := p.processDocument(ctx, path, commitSHA)
+
+		mu.Lock()
+		defer mu.Unlock()
 		if err != nil {
 			p.logger.Warn("Failed to process document", "path", path, "error", err)
 			result.FailedDocs = append(result.FailedDocs, FailedDoc{
+				Source: p.config.Name,
 				Path:   path,
 				Reason: err.Error(),
 			})
-			continue // Skip unparseable docs, continue with others
+			return // Skip unparseable docs, continue with others
 		}
 		result.SuccessfulDocs++
 		result.TotalChunks += chunks
+	})
+	if err != nil {
+		return nil, fmt.Errorf("indexing cancelled: %w", err)
 	}
+	sortFailedDocs(result.FailedDocs)
 
 	result.Duration = time.Since(start)
 	p.logger.Info("Indexing complete",
@@ -111,25 +200,333 @@ func (p *Pipeline) IndexAll(ctx context.Context) (*IndexResult, error) {
 	return result, nil
 }
 
+// IndexIncremental re-indexes only documents whose Git blob SHA changed since the last sync.
+// Unchanged documents are skipped, changed ones are replaced, and documents that no longer
+// exist upstream are removed along with their chunks. Changed documents are processed in
+// parallel; a cancelled run does not record the new commit SHA.
+func (p *Pipeline) IndexIncremental(ctx context.Context) (*IndexResult, error) {
+	start := time.Now()
+	result := &IndexResult{}
+
+	// 1. Get latest commit SHA
+	commitSHA, err := p.source.Revision(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get commit SHA: %w", err)
+	}
+	result.CommitSHA = commitSHA
+	p.logger.Info("Starting incremental indexing", "commit", commitSHA)
+
+	// 2. List upstream docs with blob SHAs
+	entries, err := p.source.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list docs: %w", err)
+	}
+	result.TotalDocs = len(entries)
+
+	// 3. Load blob SHAs recorded by the previous sync
+	indexed, err := p.storage.ListDocumentSHAs(ctx, p.config.Name)
+	if err != nil {
+		return nil, fmt.Errorf("list indexed docs: %w", err)
+	}
+	p.logger.Info("Found documents", "upstream", len(entries), "indexed", len(indexed))
+
+	// 4. Find new and changed documents
+	upstream := make(map[string]bool, len(entries))
+	var changed []string
+	for _, entry := range entries {
+		upstream[entry.Path] = true
+
+		indexedSHA, exists := indexed[entry.Path]
+		if exists && indexedSHA != "" && indexedSHA == entry.SHA {
+			result.SkippedDocs++
+			continue
+		}
+		changed = append(changed, entry.Path)
+	}
+
+	// 5. Re-index them in parallel
+	var mu sync.Mutex // Guards result
+	err = p.forEach(ctx, changed, func(path string) {
+		chunks, err := p.reindexDocument(ctx, path, commitSHA, indexed)
+
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil {
+			p.logger.Warn("Failed to process document", "path", path, "error", err)
+			result.FailedDocs = append(result.FailedDocs, FailedDoc{
+				Source: p.config.Name,
+				Path:   path,
+				Reason: err.Error(),
+			})
+			return
+		}
+		result.SuccessfulDocs++
+		result.TotalChunks += chunks
+	})
+	if err != nil {
+		return nil, fmt.Errorf("indexing cancelled: %w", err)
+	}
+
+	// 6. Remove documents that disappeared upstream
+	for path := range indexed {
+		if upstream[path] {
+			continue
+		}
+		if err := p.storage.DeleteDocumentByPath(ctx, path, p.config.Name); err != nil {
+			p.logger.Warn("Failed to remove deleted document", "path", path, "error", err)
+			result.FailedDocs = append(result.FailedDocs, FailedDoc{
+				Source: p.config.Name,
+				Path:   path,
+				Reason: err.Error(),
+			})
+			continue
+		}
+		p.logger.Info("Removed document", "path", path)
+		result.DeletedDocs = append(result.DeletedDocs, path)
+	}
+
+	sortFailedDocs(result.FailedDocs)
+
+	// 7. Record the commit if the whole index now reflects it; failed documents keep
+	// the old one so the scheduler retries them
+	if len(result.FailedDocs) == 0 {
+		if err := p.storage.UpdateCommitSHA(ctx, p.config.Name, commitSHA); err != nil {
+			return nil, fmt.Errorf("update commit SHA: %w", err)
+		}
+	}
+
+	result.Duration = time.Since(start)
+	p.logger.Info("Incremental indexing complete",
+		"reindexed", result.SuccessfulDocs,
+		"unchanged", result.SkippedDocs,
+		"deleted", len(result.DeletedDocs),
+		"failed", len(result.FailedDocs),
+		"chunks", result.TotalChunks,
+		"duration", result.Duration,
+	)
+
+	return result, nil
+}
+
+// IndexPaths re-indexes only the given documents, such as the files touched by a push.
+// Paths that still exist upstream are re-indexed if their blob SHA changed; paths
+// that no longer exist are removed. Other documents are left alone. The recorded
+// commit SHA advances only when every document then matches the upstream listing,
+// so a change the targeted run never heard about keeps the index marked stale until
+// IndexIncremental reconciles it.
+func (p *Pipeline) IndexPaths(ctx context.Context, paths []string) (*IndexResult, error) {
+	start := time.Now()
+	result := &IndexResult{TotalDocs: len(paths)}
+
+	// 1. Get latest commit SHA
+	commitSHA, err := p.source.Revision(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get commit SHA: %w", err)
+	}
+	result.CommitSHA = commitSHA
+	p.logger.Info("Starting targeted indexing", "commit", commitSHA, "paths", len(paths))
+
+	// 2. List upstream docs with blob SHAs, to tell changed paths from removed ones
+	entries, err := p.source.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list docs: %w", err)
+	}
+	upstream := make(map[string]string, len(entries))
+	for _, entry := range entries {
+		upstream[entry.Path] = entry.SHA
+	}
+
+	// 3. Load blob SHAs and the commit recorded by previous syncs. Re-indexed documents
+	// keep the recorded commit, which is stored on every document
+	indexed, err := p.storage.ListDocumentSHAs(ctx, p.config.Name)
+	if err != nil {
+		return nil, fmt.Errorf("list indexed docs: %w", err)
+	}
+	indexedCommit, err := p.storage.GetCommitSHA(ctx, p.config.Name)
+	if err != nil {
+		return nil, fmt.Errorf("get indexed commit SHA: %w", err)
+	}
+
+	// 4. Sort the requested paths into changed and removed documents
+	var changed, removed []string
+	for _, path := range paths {
+		upstreamSHA, exists := upstream[path]
+		indexedSHA, wasIndexed := indexed[path]
+		switch {
+		case !exists && wasIndexed:
+			removed = append(removed, path)
+		case !exists:
+			result.SkippedDocs++ // Removed before it was ever indexed
+		case wasIndexed && indexedSHA != "" && indexedSHA == upstreamSHA:
+			result.SkippedDocs++
+		default:
+			changed = append(changed, path)
+		}
+	}
+
+	// 5. Re-index changed documents in parallel
+	var mu sync.Mutex // Guards result
+	err = p.forEach(ctx, changed, func(path string) {
+		chunks, err := p.reindexDocument(ctx, path, indexedCommit, indexed)
+
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil {
+			p.logger.Warn("Failed to process document", "path", path, "error", err)
+			result.FailedDocs = append(result.FailedDocs, FailedDoc{
+				Source: p.config.Name,
+				Path:   path,
+				Reason: err.Error(),
+			})
+			return
+		}
+		result.SuccessfulDocs++
+		result.TotalChunks += chunks
+	})
+	if err != nil {
+		return nil, fmt.Errorf("indexing cancelled: %w", err)
+	}
+
+	// 6. Remove documents deleted upstream
+	for _, path := range removed {
+		if err := p.storage.DeleteDocumentByPath(ctx, path, p.config.Name); err != nil {
+			p.logger.Warn("Failed to remove deleted document", "path", path, "error", err)
+			result.FailedDocs = append(result.FailedDocs, FailedDoc{
+				Source: p.config.Name,
+				Path:   path,
+				Reason: err.Error(),
+			})
+			continue
+		}
+		p.logger.Info("Removed document", "path", path)
+		result.DeletedDocs = append(result.DeletedDocs, path)
+	}
+
+	sortFailedDocs(result.FailedDocs)
+
+	// 7. Record the new commit if the whole index now reflects it
+	if len(result.FailedDocs) == 0 && matchesUpstream(upstream, indexed, changed, result.DeletedDocs) {
+		if err := p.storage.UpdateCommitSHA(ctx, p.config.Name, commitSHA); err != nil {
+			return nil, fmt.Errorf("update commit SHA: %w", err)
+		}
+	}
+
+	result.Duration = time.Since(start)
+	p.logger.Info("Targeted indexing complete",
+		"reindexed", result.SuccessfulDocs,
+		"unchanged", result.SkippedDocs,
+		"deleted", len(result.DeletedDocs),
+		"failed", len(result.FailedDocs),
+		"chunks", result.TotalChunks,
+		"duration", result.Duration,
+	)
+
+	return result, nil
+}
+
+// matchesUpstream reports whether the index holds exactly the upstream documents at
+// their upstream blob SHAs, given the SHAs indexed before a run that re-indexed the
+// changed paths and deleted the deleted ones.
+func matchesUpstream(upstream, indexed map[string]string, changed, deleted []string) bool {
+	current := make(map[string]string, len(indexed))
+	for path, sha := range indexed {
+		current[path] = sha
+	}
+	for _, path := range changed {
+		current[path] = upstream[path]
+	}
+	for _, path := range deleted {
+		delete(current, path)
+	}
+
+	if len(current) != len(upstream) {
+		return false
+	}
+	for path, sha := range upstream {
+		if indexedSHA, ok := current[path]; !ok || indexedSHA == "" || indexedSHA != sha {
+			return false
+		}
+	}
+	return true
+}
+
+// reindexDocument replaces a new or changed document. The stale version is removed
+// first; a failure after that leaves the path unindexed, so the next incremental run
+// picks it up again.
+func (p *Pipeline) reindexDocument(ctx context.Context, path, commitSHA string, indexed map[string]string) (int, error) {
+	if _, exists := indexed[path]; exists {
+		if err := p.storage.DeleteDocumentByPath(ctx, path, p.config.Name); err != nil {
+			return 0, fmt.Errorf("remove stale document: %w", err)
+		}
+	}
+	return p.processDocument(ctx, path, commitSHA)
+}
+
+// forEach calls fn for every path using up to p.concurrency workers and waits for
+// them to finish. Once ctx is cancelled no further paths are handed out; calls in
+// progress see the cancelled context and fail fast. Returns ctx.Err().
+func (p *Pipeline) forEach(ctx context.Context, paths []string, fn func(path string)) error {
+	work := make(chan string)
+	var wg sync.WaitGroup
+	for range min(p.concurrency, len(paths)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for path := range work {
+				fn(path)
+			}
+		}()
+	}
+
+dispatch:
+	for _, path := range paths {
+		select {
+		case work <- path:
+		case <-ctx.Done():
+			break dispatch
+		}
+	}
+	close(work)
+	wg.Wait()
+
+	return ctx.Err()
+}
+
+// sortFailedDocs orders failures by source and path so reports are stable across runs.
+func sortFailedDocs(failed []FailedDoc) {
+	sort.Slice(failed, func(i, j int) bool {
+		if failed[i].Source != failed[j].Source {
+			return failed[i].Source < failed[j].Source
+		}
+		return failed[i].Path < failed[j].Path
+	})
+}
+
 // processDocument handles the full pipeline for a single document.
 // Returns the number of chunks created for the document.
 func (p *Pipeline) processDocument(ctx context.Context, path, commitSHA string) (int, error) {
 	// Fetch content
-	fetched, err := p.fetcher.FetchDoc(ctx, path)
+	fetched, err := p.source.Fetch(ctx, path)
 	if err != nil {
 		return 0, fmt.Errorf("fetch: %w", err)
 	}
 	p.logger.Debug("Fetched document", "path", path, "size", len(fetched.Content))
 
+	// Split off Hugo front matter; it becomes structured metadata instead of chunk text
+	frontMatter, body, err := markdown.ParseFrontMatter([]byte(fetched.Content))
+	if err != nil {
+		p.logger.Warn("Invalid front matter, ignoring its fields", "path", path, "error", err)
+	}
+
 	// Generate metadata (summary, entities)
-	meta, err := p.generator.GenerateMetadata(ctx, path, fetched.Content)
+	meta, err := p.generator.GenerateMetadata(ctx, path, string(body))
 	if err != nil {
 		p.logger.Warn("Metadata generation failed, using empty", "path", path, "error", err)
 		meta = &metadata.DocumentMetadata{Summary: "", Entities: []string{}}
 	}
 
 	// Chunk document
-	chunks, err := p.chunker.ChunkDocument([]byte(fetched.Content))
+	chunks, err := p.chunker.ChunkDocument(body)
 	if err != nil {
 		return 0, fmt.Errorf("chunk: %w", err)
 	}
@@ -146,29 +543,41 @@ func (p *Pipeline) processDocument(ctx context.Context, path, commitSHA string)
 		return 0, fmt.Errorf("embeddings: %w", err)
 	}
 
+	// Only the Eino docs have known page URLs
+	pageURL := ""
+	if p.config.PublishedOnSite() {
+		pageURL = markdown.PageURL(path, frontMatter)
+	}
+
 	// Create parent document
 	docID := uuid.New().String()
 	doc := &storage.Document{
 		ID:      docID,
 		Content: fetched.Content,
 		Metadata: storage.DocumentMetadata{
-			Path:       path,
-			URL:        fetched.URL,
-			Repository: "cloudwego/cloudwego.github.io",
-			CommitSHA:  commitSHA,
-			IndexedAt:  time.Now(),
-			Summary:    meta.Summary,
-			Entities:   meta.Entities,
+			Path:        path,
+			URL:         fetched.URL,
+			PageURL:     pageURL,
+			Repository:  p.config.Repository(),
+			Source:      p.config.Name,
+			CommitSHA:   commitSHA,
+			BlobSHA:     fetched.SHA,
+			IndexedAt:   time.Now(),
+			Summary:     meta.Summary,
+			Entities:    meta.Entities,
+			Title:       frontMatter.Title,
+			Description: frontMatter.Description,
+			Weight:      frontMatter.Weight,
+			Date:        frontMatter.Date,
+			Aliases:     frontMatter.Aliases,
+			Links:       markdown.Links(body, path),
 		},
 	}
 
-	if err := p.storage.UpsertDocument(ctx, doc); err != nil {
-		return 0, fmt.Errorf("store document: %w", err)
-	}
-
-	// Create chunks with embeddings
+	// Create chunks with dense embeddings and sparse BM25 vectors
 	storageChunks := make([]*storage.Chunk, len(chunks))
 	for i, chunk := range chunks {
+		sparse := bm25.EncodeDocument(chunk.Content) // Include header path terms
 		storageChunks[i] = &storage.Chunk{
 			ID:          uuid.New().String(),
 			ParentDocID: docID,
@@ -176,8 +585,14 @@ func (p *Pipeline) processDocument(ctx context.Context, path, commitSHA string)
 			HeaderPath:  chunk.HeaderPath,
 			Content:     chunk.RawContent, // Store without header prefix in payload
 			Path:        path,
-			Repository:  "cloudwego/cloudwego.github.io",
+			Repository:  p.config.Repository(),
+			Source:      p.config.Name,
+			Entities:    meta.Entities,
 			Embedding:   embeddings[i],
+			Sparse: storage.SparseVector{
+				Indices: sparse.Indices,
+				Values:  sparse.Values,
+			},
 		}
 	}
 
@@ -185,6 +600,16 @@ func (p *Pipeline) processDocument(ctx context.Context, path, commitSHA string)
 		return 0, fmt.Errorf("store chunks: %w", err)
 	}
 
+	// Store the parent last: its BlobSHA marks the document current, so it must not
+	// exist until the chunks do. Without it the chunks are orphans the next run would
+	// never replace, so take them out again.
+	if err := p.storage.UpsertDocument(ctx, doc); err != nil {
+		if delErr := p.storage.DeleteDocumentByPath(ctx, path, p.config.Name); delErr != nil {
+			p.logger.Warn("Failed to remove chunks of unstored document", "path", path, "error", delErr)
+		}
+		return 0, fmt.Errorf("store document: %w", err)
+	}
+
 	p.logger.Info("Indexed document", "path", path, "chunks", len(chunks))
 	return len(chunks), nil
 }
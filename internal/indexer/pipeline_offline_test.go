@@ -0,0 +1,378 @@
+package indexer
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mike-a-ellis/eino-docs-mcp/internal/embedding"
+	"github.com/mike-a-ellis/eino-docs-mcp/internal/github"
+	"github.com/mike-a-ellis/eino-docs-mcp/internal/github/githubtest"
+	"github.com/mike-a-ellis/eino-docs-mcp/internal/markdown"
+	"github.com/mike-a-ellis/eino-docs-mcp/internal/metadata"
+	"github.com/mike-a-ellis/eino-docs-mcp/internal/source"
+	"github.com/mike-a-ellis/eino-docs-mcp/internal/storage"
+)
+
+const testBasePath = "content/en/docs/eino"
+
+// newOfflinePipeline wires a pipeline to a fake GitHub, the hashing embedder, the
+// stub metadata generator and an embedded store, so it runs without any network.
+func newOfflinePipeline(t *testing.T) (*Pipeline, *githubtest.Server, storage.Store) {
+	t.Helper()
+
+	gh := githubtest.NewServer(t, source.DefaultConfig.Owner, source.DefaultConfig.Repo)
+	gh.SetFile(testBasePath+"/overview.md", "# Overview\n\nEino is a framework for LLM applications.\n\n## Components\n\nChatModel and Retriever are components.\n")
+	gh.SetFile(testBasePath+"/core/graph.md", "# Graph\n\nUse compose.NewGraph to build a graph of nodes.\n")
+	gh.SetFile(testBasePath+"/core/notes.txt", "not markdown")
+
+	embedder := embedding.NewHashEmbedder(64)
+	store, err := storage.NewEmbeddedStorage(filepath.Join(t.TempDir(), "index.idx"))
+	require.NoError(t, err)
+	require.NoError(t, store.EnsureCollection(context.Background(), storage.EmbeddingSpec{
+		Model:     embedder.Model(),
+		Dimension: embedder.Dimension(),
+	}))
+
+	generator := &metadata.StubGenerator{Responses: map[string]metadata.DocumentMetadata{
+		"core/graph.md": {Summary: "Building graphs", Entities: []string{"compose.NewGraph"}},
+	}}
+	fetcher := github.NewFetcher(gh.Client(), source.DefaultConfig)
+	pipeline := NewPipeline(fetcher, markdown.NewChunker(), embedder, generator, store, slog.Default())
+
+	return pipeline, gh, store
+}
+
+func TestPipeline_IndexAll_Offline(t *testing.T) {
+	pipeline, gh, store := newOfflinePipeline(t)
+	ctx := context.Background()
+
+	result, err := pipeline.IndexAll(ctx)
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, result.TotalDocs, "only markdown files are indexed")
+	assert.Equal(t, 2, result.SuccessfulDocs)
+	assert.Empty(t, result.FailedDocs)
+	assert.Equal(t, 3, result.TotalChunks)
+	assert.Equal(t, gh.HeadSHA(), result.CommitSHA)
+	assert.Equal(t, 3, gh.Requests(), "one request each for the commit, the tree and the archive")
+
+	paths, err := store.ListDocumentPaths(ctx, source.DefaultConfig.Name)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"core/graph.md", "overview.md"}, paths)
+
+	doc, err := store.GetDocumentByPath(ctx, "core/graph.md", source.DefaultConfig.Name)
+	require.NoError(t, err)
+	assert.Equal(t, "Building graphs", doc.Metadata.Summary)
+	assert.Equal(t, []string{"compose.NewGraph"}, doc.Metadata.Entities)
+	assert.Equal(t, githubtest.BlobSHA("# Graph\n\nUse compose.NewGraph to build a graph of nodes.\n"), doc.Metadata.BlobSHA)
+
+	// Hashed embeddings rank the document sharing the query terms first
+	embedder := embedding.NewHashEmbedder(64)
+	query, err := embedder.GenerateEmbeddings(ctx, []string{"compose.NewGraph"})
+	require.NoError(t, err)
+	chunks, err := store.SearchChunksWithScores(ctx, query[0], 1, storage.SearchFilter{Source: source.DefaultConfig.Name})
+	require.NoError(t, err)
+	require.Len(t, chunks, 1)
+	assert.Equal(t, "core/graph.md", chunks[0].Path)
+}
+
+func TestPipeline_IndexIncremental_Offline(t *testing.T) {
+	pipeline, gh, store := newOfflinePipeline(t)
+	ctx := context.Background()
+
+	_, err := pipeline.IndexAll(ctx)
+	require.NoError(t, err)
+
+	// Change one document, delete another, add a third
+	gh.SetFile(testBasePath+"/overview.md", "# Overview\n\nUpdated introduction.\n")
+	gh.DeleteFile(testBasePath + "/core/graph.md")
+	gh.SetFile(testBasePath+"/core/agent.md", "# Agent\n\nReAct agents call tools.\n")
+	gh.Commit("0000000000000000000000000000000000000002")
+
+	result, err := pipeline.IndexIncremental(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.SuccessfulDocs)
+	assert.Equal(t, 0, result.SkippedDocs)
+	assert.Equal(t, []string{"core/graph.md"}, result.DeletedDocs)
+
+	// Nothing changed since: everything is skipped
+	result, err = pipeline.IndexIncremental(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, result.SuccessfulDocs)
+	assert.Equal(t, 2, result.SkippedDocs)
+
+	paths, err := store.ListDocumentPaths(ctx, source.DefaultConfig.Name)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"core/agent.md", "overview.md"}, paths)
+
+	commitSHA, err := store.GetCommitSHA(ctx, source.DefaultConfig.Name)
+	require.NoError(t, err)
+	assert.Equal(t, "0000000000000000000000000000000000000002", commitSHA)
+}
+
+func TestPipeline_IndexPaths_Offline(t *testing.T) {
+	pipeline, gh, store := newOfflinePipeline(t)
+	ctx := context.Background()
+
+	_, err := pipeline.IndexIncremental(ctx)
+	require.NoError(t, err)
+
+	// A push touches two documents and removes a third; another change is not mentioned
+	gh.SetFile(testBasePath+"/overview.md", "# Overview\n\nUpdated introduction.\n")
+	gh.SetFile(testBasePath+"/core/agent.md", "# Agent\n\nReAct agents call tools.\n")
+	gh.DeleteFile(testBasePath + "/core/graph.md")
+	gh.SetFile(testBasePath+"/core/chain.md", "# Chain\n\nChains run nodes in order.\n")
+	gh.Commit("0000000000000000000000000000000000000002")
+
+	result, err := pipeline.IndexPaths(ctx, []string{"overview.md", "core/agent.md", "core/graph.md", "core/gone.md"})
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.SuccessfulDocs)
+	assert.Equal(t, 1, result.SkippedDocs, "a path removed before it was indexed")
+	assert.Equal(t, []string{"core/graph.md"}, result.DeletedDocs)
+	assert.Equal(t, "0000000000000000000000000000000000000002", result.CommitSHA)
+
+	paths, err := store.ListDocumentPaths(ctx, source.DefaultConfig.Name)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"core/agent.md", "overview.md"}, paths, "unmentioned documents are left alone")
+
+	// The index does not claim the new commit while a change is missing
+	commitSHA, err := store.GetCommitSHA(ctx, source.DefaultConfig.Name)
+	require.NoError(t, err)
+	assert.Equal(t, "0000000000000000000000000000000000000001", commitSHA)
+
+	// Once the last change arrives, every document matches the new commit
+	result, err = pipeline.IndexPaths(ctx, []string{"core/chain.md"})
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.SuccessfulDocs)
+	commitSHA, err = store.GetCommitSHA(ctx, source.DefaultConfig.Name)
+	require.NoError(t, err)
+	assert.Equal(t, "0000000000000000000000000000000000000002", commitSHA)
+
+	result, err = pipeline.IndexIncremental(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, result.SuccessfulDocs, "nothing is left to index")
+	assert.Equal(t, 3, result.SkippedDocs)
+}
+
+func TestPipeline_IndexAll_ArchiveUnavailable(t *testing.T) {
+	pipeline, gh, store := newOfflinePipeline(t)
+	gh.DisableArchive()
+	ctx := context.Background()
+
+	// Documents are fetched one by one, still at the pinned commit
+	result, err := pipeline.IndexAll(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.SuccessfulDocs)
+	assert.Empty(t, result.FailedDocs)
+	assert.Equal(t, 5, gh.Requests())
+
+	doc, err := store.GetDocumentByPath(ctx, "core/graph.md", source.DefaultConfig.Name)
+	require.NoError(t, err)
+	assert.Equal(t, githubtest.BlobSHA("# Graph\n\nUse compose.NewGraph to build a graph of nodes.\n"), doc.Metadata.BlobSHA)
+}
+
+func TestPipeline_LocalSource(t *testing.T) {
+	pipeline, _, store := newOfflinePipeline(t)
+	ctx := context.Background()
+
+	_, err := pipeline.IndexAll(ctx)
+	require.NoError(t, err)
+
+	// A checkout of the same docs: blob SHAs match GitHub's, so switching sources
+	// re-indexes only what differs
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "core"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "overview.md"), []byte("# Overview\n\nEino is a framework for LLM applications.\n\n## Components\n\nChatModel and Retriever are components.\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "core", "graph.md"), []byte("# Graph\n\nGraphs are compiled before use.\n"), 0o644))
+
+	local, err := source.NewLocal(dir)
+	require.NoError(t, err)
+	result, err := pipeline.WithSource(local).IndexIncremental(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.SkippedDocs)
+	assert.Equal(t, 1, result.SuccessfulDocs)
+	assert.Len(t, result.CommitSHA, 40)
+
+	doc, err := store.GetDocumentByPath(ctx, "core/graph.md", source.DefaultConfig.Name)
+	require.NoError(t, err)
+	assert.Contains(t, doc.Content, "compiled before use")
+	assert.Equal(t, "file://"+filepath.ToSlash(filepath.Join(dir, "core", "graph.md")), doc.Metadata.URL)
+}
+
+func TestPipeline_IndexAll_Concurrent(t *testing.T) {
+	pipeline, gh, store := newOfflinePipeline(t)
+	ctx := context.Background()
+
+	for i := range 20 {
+		gh.SetFile(fmt.Sprintf("%s/bulk/doc%02d.md", testBasePath, i), fmt.Sprintf("# Doc %d\n\nBulk document number %d.\n", i, i))
+	}
+
+	result, err := pipeline.WithConcurrency(8).IndexAll(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 22, result.TotalDocs)
+	assert.Equal(t, 22, result.SuccessfulDocs)
+	assert.Equal(t, 23, result.TotalChunks)
+	assert.Empty(t, result.FailedDocs)
+
+	paths, err := store.ListDocumentPaths(ctx, source.DefaultConfig.Name)
+	require.NoError(t, err)
+	assert.Len(t, paths, 22)
+}
+
+// cancellingGenerator cancels the sync after a number of documents have started.
+type cancellingGenerator struct {
+	metadata.StubGenerator
+	cancel context.CancelFunc
+	after  int32
+	calls  atomic.Int32
+}
+
+func (g *cancellingGenerator) GenerateMetadata(ctx context.Context, path, content string) (*metadata.DocumentMetadata, error) {
+	if g.calls.Add(1) >= g.after {
+		g.cancel()
+	}
+	return g.StubGenerator.GenerateMetadata(ctx, path, content)
+}
+
+func TestPipeline_IndexIncremental_Cancelled(t *testing.T) {
+	pipeline, gh, store := newOfflinePipeline(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	for i := range 20 {
+		gh.SetFile(fmt.Sprintf("%s/bulk/doc%02d.md", testBasePath, i), fmt.Sprintf("# Doc %d\n", i))
+	}
+
+	generator := &cancellingGenerator{cancel: cancel, after: 3}
+	pipeline.generator = generator
+
+	_, err := pipeline.WithConcurrency(2).IndexIncremental(ctx)
+	require.ErrorIs(t, err, context.Canceled)
+	assert.Less(t, generator.calls.Load(), int32(22), "no new documents are started after cancellation")
+
+	// The next run picks up the documents that were never indexed
+	pipeline.generator = &metadata.StubGenerator{}
+	result, err := pipeline.IndexIncremental(context.Background())
+	require.NoError(t, err)
+	assert.Positive(t, result.SuccessfulDocs)
+
+	paths, err := store.ListDocumentPaths(context.Background(), source.DefaultConfig.Name)
+	require.NoError(t, err)
+	assert.Len(t, paths, 22)
+}
+
+// failingChunkStore fails to store the chunks of one path.
+type failingChunkStore struct {
+	storage.Store
+	path string
+}
+
+func (s *failingChunkStore) UpsertChunks(ctx context.Context, chunks []*storage.Chunk) error {
+	for _, chunk := range chunks {
+		if chunk.Path == s.path {
+			return fmt.Errorf("upsert chunks: unavailable")
+		}
+	}
+	return s.Store.UpsertChunks(ctx, chunks)
+}
+
+// TestPipeline_IndexIncremental_FailedChunks verifies a document whose chunks could
+// not be stored is retried by the next run instead of looking current.
+func TestPipeline_IndexIncremental_FailedChunks(t *testing.T) {
+	pipeline, gh, store := newOfflinePipeline(t)
+	ctx := context.Background()
+
+	_, err := pipeline.IndexAll(ctx)
+	require.NoError(t, err)
+	indexedSHA := gh.HeadSHA()
+
+	gh.SetFile(testBasePath+"/core/graph.md", "# Graph\n\nGraphs now support branches.\n")
+	gh.Commit("0000000000000000000000000000000000000002")
+
+	pipeline.storage = &failingChunkStore{Store: store, path: "core/graph.md"}
+	result, err := pipeline.IndexIncremental(ctx)
+	require.NoError(t, err)
+	require.Len(t, result.FailedDocs, 1)
+	assert.Equal(t, "core/graph.md", result.FailedDocs[0].Path)
+
+	commitSHA, err := store.GetCommitSHA(ctx, source.DefaultConfig.Name)
+	require.NoError(t, err)
+	assert.Equal(t, indexedSHA, commitSHA, "the commit is not recorded while a document failed")
+	_, err = store.GetDocumentByPath(ctx, "core/graph.md", source.DefaultConfig.Name)
+	assert.Error(t, err, "no parent claims the new blob without its chunks")
+
+	pipeline.storage = store
+	result, err = pipeline.IndexIncremental(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, result.FailedDocs)
+	assert.Equal(t, 1, result.SuccessfulDocs)
+
+	doc, err := store.GetDocumentByPath(ctx, "core/graph.md", source.DefaultConfig.Name)
+	require.NoError(t, err)
+	assert.Equal(t, githubtest.BlobSHA("# Graph\n\nGraphs now support branches.\n"), doc.Metadata.BlobSHA)
+	chunks, err := store.GetDocumentChunks(ctx, "core/graph.md", source.DefaultConfig.Name)
+	require.NoError(t, err)
+	assert.NotEmpty(t, chunks)
+
+	commitSHA, err = store.GetCommitSHA(ctx, source.DefaultConfig.Name)
+	require.NoError(t, err)
+	assert.Equal(t, "0000000000000000000000000000000000000002", commitSHA)
+}
+
+// TestPipelines_MultipleSources verifies sources sharing a store are indexed, synced
+// and recorded separately.
+func TestPipelines_MultipleSources(t *testing.T) {
+	eino, gh, store := newOfflinePipeline(t)
+	ctx := context.Background()
+
+	extConfig := source.Config{Name: "eino-ext", Owner: "example", Repo: "eino-ext-docs", Ref: "main", BasePath: "docs"}
+	extGitHub := githubtest.NewServer(t, extConfig.Owner, extConfig.Repo)
+	extGitHub.SetFile("docs/overview.md", "# Extensions\n\nInternal components built on Eino.\n")
+	ext := eino.WithSource(github.NewFetcher(extGitHub.Client(), extConfig)).WithConfig(extConfig)
+
+	pipelines := NewPipelines(eino, ext)
+	result, err := pipelines.IndexAll(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.SuccessfulDocs)
+	assert.Equal(t, "eino@"+gh.HeadSHA()+",eino-ext@"+extGitHub.HeadSHA(), result.CommitSHA)
+
+	indexed, err := pipelines.IndexedRevision(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, result.CommitSHA, indexed, "an incremental sync records both sources")
+
+	paths, err := store.ListDocumentPaths(ctx, "eino-ext")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"overview.md"}, paths)
+	paths, err = store.ListDocumentPaths(ctx, "")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"core/graph.md", "overview.md", "overview.md"}, paths)
+
+	doc, err := store.GetDocumentByPath(ctx, "overview.md", "eino-ext")
+	require.NoError(t, err)
+	assert.Equal(t, "example/eino-ext-docs", doc.Metadata.Repository)
+	assert.Empty(t, doc.Metadata.PageURL, "only the Eino docs have known page URLs")
+
+	// A push to one source re-indexes it without touching the other
+	extGitHub.SetFile("docs/overview.md", "# Extensions\n\nUpdated.\n")
+	extGitHub.Commit("0000000000000000000000000000000000000002")
+	result, err = pipelines.IndexPaths(ctx, map[string][]string{"eino-ext": {"overview.md"}})
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.SuccessfulDocs)
+
+	upstream, err := pipelines.Revision(ctx)
+	require.NoError(t, err)
+	indexed, err = pipelines.IndexedRevision(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, upstream, indexed)
+
+	_, err = pipelines.IndexPaths(ctx, map[string][]string{"unknown": {"overview.md"}})
+	assert.Error(t, err)
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/mike-a-ellis/eino-docs-mcp/internal/github"
 	"github.com/mike-a-ellis/eino-docs-mcp/internal/markdown"
 	"github.com/mike-a-ellis/eino-docs-mcp/internal/metadata"
+	"github.com/mike-a-ellis/eino-docs-mcp/internal/source"
 	"github.com/mike-a-ellis/eino-docs-mcp/internal/storage"
 )
 
@@ -23,32 +24,36 @@ func TestPipeline_IndexAll_Integration(t *testing.T) {
 		t.Skip("OPENAI_API_KEY not set, skipping integration test")
 	}
 
+	ctx := context.Background()
+
+	// Create embedding components first; the collection records their model
+	openaiClient, err := embedding.NewClient(embedding.ConfigFromEnv())
+	require.NoError(t, err)
+	embedder := embedding.NewOpenAIEmbedder(openaiClient, "", 0, 500)
+	generator := metadata.NewGenerator(openaiClient.Client())
+
 	// Setup
-	storage, err := storage.NewQdrantStorage("localhost", 6334)
+	store, err := storage.NewQdrantStorage("localhost", 6334)
 	require.NoError(t, err)
-	defer storage.Close()
+	defer store.Close()
 
 	// Clear existing data for clean test
-	err = storage.ClearCollection(context.Background())
+	spec := storage.EmbeddingSpec{Model: embedder.Model(), Dimension: embedder.Dimension()}
+	err = store.EnsureCollection(ctx, spec)
 	require.NoError(t, err)
-	err = storage.EnsureCollection(context.Background())
+	err = store.ClearCollection(ctx)
 	require.NoError(t, err)
 
 	// Create components
-	ghClient := github.NewClient()
-	fetcher := github.NewFetcher(ghClient, "cloudwego", "cloudwego.github.io", "content/en/docs/eino")
-	chunker := markdown.NewChunker()
-
-	openaiClient, err := embedding.NewClient()
+	ghClient, err := github.NewClient(ctx)
 	require.NoError(t, err)
-	embedder := embedding.NewEmbedder(openaiClient, 500)
-	generator := metadata.NewGenerator(openaiClient.Client())
+	fetcher := github.NewFetcher(ghClient, source.DefaultConfig)
+	chunker := markdown.NewChunker()
 
-	pipeline := NewPipeline(fetcher, chunker, embedder, generator, storage, slog.Default())
+	pipeline := NewPipeline(fetcher, chunker, embedder, generator, store, slog.Default())
 
 	// Run indexing
 	// Note: Full indexing tested manually, this validates wiring
-	ctx := context.Background()
 	result, err := pipeline.IndexAll(ctx)
 	require.NoError(t, err)
 
@@ -67,8 +72,8 @@ func TestPipeline_IndexAll_Integration(t *testing.T) {
 	}
 
 	// Verify searchable
-	testQuery := make([]float32, 1536) // Zero vector for simple test
-	chunks, err := storage.SearchChunks(ctx, testQuery, 5, "cloudwego/cloudwego.github.io")
+	testQuery := make([]float32, embedder.Dimension()) // Zero vector for simple test
+	chunks, err := store.SearchChunks(ctx, testQuery, 5, source.DefaultConfig.Name)
 	require.NoError(t, err)
 	assert.Greater(t, len(chunks), 0, "Should find indexed chunks")
 
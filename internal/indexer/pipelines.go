@@ -0,0 +1,158 @@
+package indexer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mike-a-ellis/eino-docs-mcp/internal/source"
+)
+
+// Pipelines indexes several documentation sources into one store, one pipeline per
+// source (see Pipeline.WithConfig). It drives them as a single syncer: revisions
+// combine the sources' revisions and the results of a run are merged. A source that
+// fails does not stop the others; the run then returns the failures as its error.
+type Pipelines struct {
+	pipelines []*Pipeline
+}
+
+// NewPipelines combines pipelines whose sources have distinct names.
+func NewPipelines(pipelines ...*Pipeline) *Pipelines {
+	return &Pipelines{pipelines: pipelines}
+}
+
+// Sources returns the sources indexed, in order.
+func (m *Pipelines) Sources() []source.Config {
+	sources := make([]source.Config, len(m.pipelines))
+	for i, p := range m.pipelines {
+		sources[i] = p.config
+	}
+	return sources
+}
+
+// Revision returns the current revision of every source, combined (see combine).
+func (m *Pipelines) Revision(ctx context.Context) (string, error) {
+	return m.revisions(ctx, (*Pipeline).Revision)
+}
+
+// IndexedRevision returns the revision recorded for every source, combined (see
+// combine), or "" when no source has been synced.
+func (m *Pipelines) IndexedRevision(ctx context.Context) (string, error) {
+	return m.revisions(ctx, (*Pipeline).IndexedRevision)
+}
+
+// Flush writes buffered index changes of every source to disk (see Pipeline.Flush).
+func (m *Pipelines) Flush() error {
+	for _, p := range m.pipelines {
+		if err := p.Flush(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// IndexAll indexes every document of every source (see Pipeline.IndexAll).
+func (m *Pipelines) IndexAll(ctx context.Context) (*IndexResult, error) {
+	return m.run(ctx, (*Pipeline).IndexAll)
+}
+
+// IndexIncremental re-indexes the changed documents of every source (see
+// Pipeline.IndexIncremental).
+func (m *Pipelines) IndexIncremental(ctx context.Context) (*IndexResult, error) {
+	return m.run(ctx, (*Pipeline).IndexIncremental)
+}
+
+// IndexPaths re-indexes only the given documents, keyed by source name (see
+// Pipeline.IndexPaths). Sources without paths are left alone.
+func (m *Pipelines) IndexPaths(ctx context.Context, paths map[string][]string) (*IndexResult, error) {
+	for name := range paths {
+		if !m.has(name) {
+			return nil, fmt.Errorf("unknown source %q", name)
+		}
+	}
+	return m.run(ctx, func(p *Pipeline, ctx context.Context) (*IndexResult, error) {
+		if len(paths[p.config.Name]) == 0 {
+			return nil, nil
+		}
+		return p.IndexPaths(ctx, paths[p.config.Name])
+	})
+}
+
+// has reports whether a source is indexed under name.
+func (m *Pipelines) has(name string) bool {
+	for _, p := range m.pipelines {
+		if p.config.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// revisions reads one revision per source and combines them.
+func (m *Pipelines) revisions(ctx context.Context, revision func(*Pipeline, context.Context) (string, error)) (string, error) {
+	revisions := make([]string, len(m.pipelines))
+	for i, p := range m.pipelines {
+		rev, err := revision(p, ctx)
+		if err != nil {
+			return "", fmt.Errorf("source %s: %w", p.config.Name, err)
+		}
+		revisions[i] = rev
+	}
+	return m.combine(revisions), nil
+}
+
+// run calls index for every source in order and merges the results. A nil result
+// means the source had nothing to do.
+func (m *Pipelines) run(ctx context.Context, index func(*Pipeline, context.Context) (*IndexResult, error)) (*IndexResult, error) {
+	start := time.Now()
+	merged := &IndexResult{}
+	revisions := make([]string, len(m.pipelines))
+
+	var errs []error
+	for i, p := range m.pipelines {
+		result, err := index(p, ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, err
+			}
+			errs = append(errs, fmt.Errorf("source %s: %w", p.config.Name, err))
+			continue
+		}
+		if result == nil {
+			continue
+		}
+		merged.TotalDocs += result.TotalDocs
+		merged.TotalChunks += result.TotalChunks
+		merged.SuccessfulDocs += result.SuccessfulDocs
+		merged.SkippedDocs += result.SkippedDocs
+		merged.DeletedDocs = append(merged.DeletedDocs, result.DeletedDocs...)
+		merged.FailedDocs = append(merged.FailedDocs, result.FailedDocs...)
+		revisions[i] = result.CommitSHA
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	sortFailedDocs(merged.FailedDocs)
+	merged.CommitSHA = m.combine(revisions)
+	merged.Duration = time.Since(start)
+	return merged, nil
+}
+
+// combine joins per-source revisions into one that changes whenever any of them
+// does: a lone source's revision as is, otherwise "name@revision" pairs separated by
+// commas. Sources without a revision are left out.
+func (m *Pipelines) combine(revisions []string) string {
+	if len(m.pipelines) == 1 {
+		return revisions[0]
+	}
+	var parts []string
+	for i, rev := range revisions {
+		if rev != "" {
+			parts = append(parts, m.pipelines[i].config.Name+"@"+rev)
+		}
+	}
+	return strings.Join(parts, ",")
+}
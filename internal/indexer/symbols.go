@@ -0,0 +1,64 @@
+package indexer
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/mike-a-ellis/eino-docs-mcp/internal/bm25"
+	"github.com/mike-a-ellis/eino-docs-mcp/internal/embedding"
+	"github.com/mike-a-ellis/eino-docs-mcp/internal/goapi"
+	"github.com/mike-a-ellis/eino-docs-mcp/internal/storage"
+)
+
+// SymbolRepository identifies the Eino Go module in stored symbol payloads.
+const SymbolRepository = "cloudwego/eino"
+
+// IndexSymbols replaces the stored Go API symbols of repo with api, stamped with
+// commitSHA. Each symbol is embedded and BM25-encoded from its Text, so searches
+// return it alongside document chunks. Symbol IDs are derived from the repository and
+// qualified name, so re-indexing the same API overwrites rather than duplicates.
+func IndexSymbols(ctx context.Context, store storage.Store, embedder embedding.Embedder, repo, commitSHA string, api []goapi.Symbol) (int, error) {
+	symbols := make([]*storage.Symbol, len(api))
+	for i, s := range api {
+		symbols[i] = &storage.Symbol{
+			ID:          uuid.NewSHA1(uuid.NameSpaceURL, []byte(repo+"/"+s.Package+"."+s.Name)).String(),
+			Name:        s.Name,
+			Kind:        s.Kind,
+			Package:     s.Package,
+			PackageName: s.PackageName,
+			Signature:   s.Signature,
+			Doc:         s.Doc,
+			File:        s.File,
+			Line:        s.Line,
+			Repository:  repo,
+			CommitSHA:   commitSHA,
+		}
+	}
+
+	texts := make([]string, len(symbols))
+	for i, symbol := range symbols {
+		texts[i] = symbol.Text()
+	}
+	embeddings, err := embedder.GenerateEmbeddings(ctx, texts)
+	if err != nil {
+		return 0, fmt.Errorf("embed symbols: %w", err)
+	}
+	if len(embeddings) != len(symbols) {
+		return 0, fmt.Errorf("embed symbols: got %d embeddings for %d symbols", len(embeddings), len(symbols))
+	}
+	for i, symbol := range symbols {
+		sparse := bm25.EncodeDocument(texts[i])
+		symbol.Embedding = embeddings[i]
+		symbol.Sparse = storage.SparseVector{Indices: sparse.Indices, Values: sparse.Values}
+	}
+
+	if err := store.DeleteSymbols(ctx, repo); err != nil {
+		return 0, fmt.Errorf("delete symbols: %w", err)
+	}
+	if err := store.UpsertSymbols(ctx, symbols); err != nil {
+		return 0, fmt.Errorf("store symbols: %w", err)
+	}
+	return len(symbols), nil
+}
@@ -0,0 +1,79 @@
+package indexer
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mike-a-ellis/eino-docs-mcp/internal/bm25"
+	"github.com/mike-a-ellis/eino-docs-mcp/internal/embedding"
+	"github.com/mike-a-ellis/eino-docs-mcp/internal/goapi"
+	"github.com/mike-a-ellis/eino-docs-mcp/internal/storage"
+)
+
+func TestIndexSymbols_ReplacesPreviousAPI(t *testing.T) {
+	ctx := context.Background()
+	store, err := storage.NewEmbeddedStorage(filepath.Join(t.TempDir(), "index.idx"))
+	require.NoError(t, err)
+	embedder := embedding.NewHashEmbedder(64)
+	require.NoError(t, store.EnsureCollection(ctx, storage.EmbeddingSpec{Model: embedder.Model(), Dimension: embedder.Dimension()}))
+
+	api := []goapi.Symbol{
+		{Name: "NewGraph", Kind: goapi.KindFunc, Package: "github.com/cloudwego/eino/compose", PackageName: "compose", Signature: "func NewGraph()"},
+		{Name: "NewChain", Kind: goapi.KindFunc, Package: "github.com/cloudwego/eino/compose", PackageName: "compose"},
+	}
+	count, err := IndexSymbols(ctx, store, embedder, SymbolRepository, "sha-1", api)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	// Re-indexing drops symbols that disappeared and keeps IDs stable
+	before, err := store.FindSymbols(ctx, "compose.NewGraph", SymbolRepository)
+	require.NoError(t, err)
+	require.Len(t, before, 1)
+
+	_, err = IndexSymbols(ctx, store, embedder, SymbolRepository, "sha-2", api[:1])
+	require.NoError(t, err)
+
+	names, err := store.ListSymbolNames(ctx, SymbolRepository)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"compose.NewGraph"}, names)
+
+	after, err := store.FindSymbols(ctx, "NewGraph", SymbolRepository)
+	require.NoError(t, err)
+	require.Len(t, after, 1)
+	assert.Equal(t, before[0].ID, after[0].ID)
+	assert.Equal(t, "sha-2", after[0].CommitSHA)
+}
+
+func TestIndexSymbols_Searchable(t *testing.T) {
+	ctx := context.Background()
+	store, err := storage.NewEmbeddedStorage(filepath.Join(t.TempDir(), "index.idx"))
+	require.NoError(t, err)
+	embedder := embedding.NewHashEmbedder(64)
+	require.NoError(t, store.EnsureCollection(ctx, storage.EmbeddingSpec{Model: embedder.Model(), Dimension: embedder.Dimension()}))
+
+	api := []goapi.Symbol{
+		{Name: "NewGraph", Kind: goapi.KindFunc, Package: "github.com/cloudwego/eino/compose", PackageName: "compose",
+			Signature: "func NewGraph[I, O any](opts ...NewGraphOption) *Graph[I, O]", Doc: "NewGraph creates a new graph."},
+		{Name: "Message", Kind: goapi.KindType, Package: "github.com/cloudwego/eino/schema", PackageName: "schema",
+			Signature: "type Message struct", Doc: "Message is a chat message."},
+	}
+	_, err = IndexSymbols(ctx, store, embedder, SymbolRepository, "sha-1", api)
+	require.NoError(t, err)
+
+	query := bm25.EncodeQuery("compose.NewGraph")
+	results, err := store.SearchChunksSparse(ctx, storage.SparseVector{Indices: query.Indices, Values: query.Values}, 10, storage.SearchFilter{Symbols: true})
+	require.NoError(t, err)
+	require.NotEmpty(t, results)
+	require.NotNil(t, results[0].Symbol)
+	assert.Equal(t, "compose.NewGraph", results[0].HeaderPath)
+
+	vectors, err := embedder.GenerateEmbeddings(ctx, []string{"compose.NewGraph"})
+	require.NoError(t, err)
+	results, err = store.SearchChunksWithScores(ctx, vectors[0], 10, storage.SearchFilter{Symbols: true})
+	require.NoError(t, err)
+	assert.Len(t, results, 2, "every symbol has a dense vector")
+}
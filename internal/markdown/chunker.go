@@ -16,28 +16,62 @@ import (
 type Chunk struct {
 	Index      int    // Position in document (0, 1, 2...)
 	HeaderPath string // Hierarchy: "# Doc Title > ## Section Name"
-	Content    string // Chunk content WITH header path prepended
-	RawContent string // Original content without header prefix
+	Content    string // Chunk content WITH header path and overlap prepended, for embedding
+	RawContent string // Original content without header prefix or overlap
+}
+
+// DefaultMaxTokens is the default chunk size limit (in tokens, header path included).
+const DefaultMaxTokens = 512
+
+// DefaultOverlapTokens is the default amount of trailing context repeated at the
+// start of the next chunk when a section is split.
+const DefaultOverlapTokens = 64
+
+// Options configures chunk sizing. Zero values select the defaults.
+type Options struct {
+	MaxTokens     int // Maximum tokens per chunk (default DefaultMaxTokens)
+	OverlapTokens int // Tokens of overlap between split chunks (default DefaultOverlapTokens, negative disables)
 }
 
 // Chunker splits markdown documents at header boundaries while preserving context.
 type Chunker struct {
-	parser goldmark.Markdown
+	parser        goldmark.Markdown
+	maxTokens     int
+	overlapTokens int
 }
 
 // NewChunker creates a new markdown chunker configured with goldmark parser.
-func NewChunker() *Chunker {
+// Optional opts parameter sets the chunk size limits (defaults to DefaultMaxTokens
+// and DefaultOverlapTokens).
+func NewChunker(opts ...Options) *Chunker {
 	md := goldmark.New(
 		goldmark.WithParserOptions(
 			parser.WithAutoHeadingID(),
 		),
 	)
+
+	maxTokens := DefaultMaxTokens
+	overlapTokens := DefaultOverlapTokens
+	if len(opts) > 0 {
+		if opts[0].MaxTokens > 0 {
+			maxTokens = opts[0].MaxTokens
+		}
+		if opts[0].OverlapTokens != 0 {
+			overlapTokens = max(opts[0].OverlapTokens, 0)
+		}
+	}
+	// Overlap must leave room for new content in every chunk
+	overlapTokens = min(overlapTokens, maxTokens/4)
+
 	return &Chunker{
-		parser: md,
+		parser:        md,
+		maxTokens:     maxTokens,
+		overlapTokens: overlapTokens,
 	}
 }
 
 // ChunkDocument splits markdown at H1 and H2 boundaries with header hierarchy preservation.
+// Sections larger than the token limit are split further (see splitSection).
 // Each chunk includes prepended header path for context during retrieval.
 func (c *Chunker) ChunkDocument(source []byte) ([]Chunk, error) {
 	// Parse markdown to AST
@@ -56,14 +90,9 @@ func (c *Chunker) ChunkDocument(source []byte) ([]Chunk, error) {
 
 	// If no headers found, return entire content as single chunk
 	if len(tree.Items) == 0 {
-		return []Chunk{
-			{
-				Index:      0,
-				HeaderPath: "",
-				Content:    string(source),
-				RawContent: string(source),
-			},
-		}, nil
+		var chunks []Chunk
+		c.appendSection(nil, string(source), &chunks)
+		return chunks, nil
 	}
 
 	// Extract chunks with header context
@@ -77,8 +106,7 @@ func (c *Chunker) ChunkDocument(source []byte) ([]Chunk, error) {
 func (c *Chunker) extractChunks(doc ast.Node, source []byte, items toc.Items, ancestors []string, chunks *[]Chunk) {
 	for i, item := range items {
 		// Build header path for this item
-		currentPath := append(ancestors, string(item.Title))
-		headerPath := formatHeaderPath(currentPath)
+		currentPath := append(ancestors[:len(ancestors):len(ancestors)], string(item.Title))
 
 		// Find the header node in AST
 		headerNode := findHeaderByID(doc, string(item.ID))
@@ -105,14 +133,14 @@ func (c *Chunker) extractChunks(doc ast.Node, source []byte, items toc.Items, an
 		// Extract content
 		content := extractContent(source, startLine, endLine)
 
-		// Create chunk with prepended header path
-		chunk := Chunk{
-			Index:      len(*chunks),
-			HeaderPath: headerPath,
-			RawContent: content,
-			Content:    fmt.Sprintf("%s\n\n%s", headerPath, content),
+		// A parent section too large for one chunk stops where its children begin,
+		// as they get chunks of their own
+		if len(item.Items) > 0 && EstimateTokens(content) > c.budget(currentPath) {
+			if childHeader := findHeaderByID(doc, string(item.Items[0].ID)); childHeader != nil {
+				content = extractContent(source, startLine, childHeader.Lines().At(0))
+			}
 		}
-		*chunks = append(*chunks, chunk)
+		c.appendSection(currentPath, content, chunks)
 
 		// Process children (H2 under H1)
 		if len(item.Items) > 0 {
@@ -121,6 +149,39 @@ func (c *Chunker) extractChunks(doc ast.Node, source []byte, items toc.Items, an
 	}
 }
 
+// budget returns the tokens available for section content under path, reserving room
+// for the prepended header path and the overlap from the previous part.
+func (c *Chunker) budget(path []string) int {
+	budget := c.maxTokens - EstimateTokens(formatHeaderPath(path)) - c.overlapTokens
+	return max(budget, c.maxTokens/4)
+}
+
+// appendSection splits a section into chunks within the token limit and appends them
+// with prepended header paths. The overlap is only part of the embedded Content;
+// RawContent keeps the chunk's own span of the document.
+func (c *Chunker) appendSection(path []string, content string, chunks *[]Chunk) {
+	parts := splitSection(path, content, c.budget(path), 0)
+	addOverlap(parts, c.overlapTokens)
+
+	for _, p := range parts {
+		headerPath := formatHeaderPath(p.path)
+		text := p.text
+		if p.overlap != "" {
+			text = p.overlap + "\n\n" + text
+		}
+		chunk := Chunk{
+			Index:      len(*chunks),
+			HeaderPath: headerPath,
+			RawContent: p.text,
+			Content:    text,
+		}
+		if headerPath != "" {
+			chunk.Content = fmt.Sprintf("%s\n\n%s", headerPath, text)
+		}
+		*chunks = append(*chunks, chunk)
+	}
+}
+
 // formatHeaderPath builds a header hierarchy string.
 // Example: ["Installation", "Prerequisites"] -> "# Installation > ## Prerequisites"
 func formatHeaderPath(path []string) string {
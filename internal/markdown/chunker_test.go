@@ -1,6 +1,7 @@
 package markdown
 
 import (
+	"fmt"
 	"strings"
 	"testing"
 )
@@ -305,3 +306,136 @@ Some content here.
 		t.Error("Did not find 'Another Section' chunk")
 	}
 }
+
+// TestChunkDocument_ParentBoundaries verifies a parent chunk spans its subsections when
+// it fits the token limit, and stops where they begin when it does not.
+func TestChunkDocument_ParentBoundaries(t *testing.T) {
+	input := `# Title
+
+Intro text.
+
+## Section
+
+Section content.
+`
+
+	chunks, err := NewChunker().ChunkDocument([]byte(input))
+	if err != nil {
+		t.Fatalf("ChunkDocument failed: %v", err)
+	}
+	if !strings.Contains(chunks[0].RawContent, "Section content") {
+		t.Errorf("H1 chunk should include H2 content, got %q", chunks[0].RawContent)
+	}
+
+	long := strings.Replace(input, "Section content.", strings.Repeat("Section content. ", 30), 1)
+	chunks, err = NewChunker(Options{MaxTokens: 100, OverlapTokens: -1}).ChunkDocument([]byte(long))
+	if err != nil {
+		t.Fatalf("ChunkDocument failed: %v", err)
+	}
+	if strings.Contains(chunks[0].RawContent, "Section content") {
+		t.Errorf("Oversized H1 chunk should not include H2 content, got %q", chunks[0].RawContent)
+	}
+}
+
+// TestChunkDocument_SplitsOversizedSections verifies long sections are split at H3 and
+// paragraph boundaries and every chunk stays within the token limit.
+func TestChunkDocument_SplitsOversizedSections(t *testing.T) {
+	paragraph := strings.Repeat("Graph nodes run in order. ", 8) // ~50 tokens
+	input := "# Guide\n\n## Tutorial\n\n" + paragraph + "\n\n" + paragraph + "\n\n" +
+		"### Step One\n\n" + paragraph + "\n\n" + paragraph + "\n\n" +
+		"### Step Two\n\n" + paragraph + "\n"
+
+	chunker := NewChunker(Options{MaxTokens: 80, OverlapTokens: -1})
+	chunks, err := chunker.ChunkDocument([]byte(input))
+	if err != nil {
+		t.Fatalf("ChunkDocument failed: %v", err)
+	}
+
+	foundStep := false
+	for i, chunk := range chunks {
+		if chunk.Index != i {
+			t.Errorf("Chunk %d has index %d", i, chunk.Index)
+		}
+		if tokens := EstimateTokens(chunk.Content); tokens > 80 {
+			t.Errorf("Chunk %d has %d tokens, want <= 80", i, tokens)
+		}
+		if chunk.HeaderPath == "# Guide > ## Tutorial > ### Step Two" {
+			foundStep = true
+			if !strings.HasPrefix(chunk.RawContent, "### Step Two") {
+				t.Errorf("Step Two chunk should start at its heading, got %q", chunk.RawContent)
+			}
+		}
+	}
+	if !foundStep {
+		t.Error("Expected a chunk with H3 header path for Step Two")
+	}
+}
+
+// TestChunkDocument_KeepsCodeBlocksAndTables verifies fences and tables are never cut,
+// even when they exceed the token limit on their own.
+func TestChunkDocument_KeepsCodeBlocksAndTables(t *testing.T) {
+	code := "```go\n" + strings.Repeat("x := compose.NewGraph()\n\n", 20) + "```"
+	table := "| Name | Type |\n|------|------|\n" + strings.Repeat("| Field | string |\n", 30)
+	input := "# API\n\n## Reference\n\nIntro.\n\n" + code + "\n\n" + table + "\n\nOutro.\n"
+
+	chunker := NewChunker(Options{MaxTokens: 60})
+	chunks, err := chunker.ChunkDocument([]byte(input))
+	if err != nil {
+		t.Fatalf("ChunkDocument failed: %v", err)
+	}
+
+	codeChunks, tableChunks := 0, 0
+	for _, chunk := range chunks {
+		if strings.Count(chunk.RawContent, "```")%2 != 0 {
+			t.Errorf("Chunk %d cuts a code fence: %q", chunk.Index, chunk.RawContent)
+		}
+		if strings.Contains(chunk.RawContent, "```go") {
+			codeChunks++
+		}
+		if strings.Contains(chunk.RawContent, "| Name | Type |") {
+			tableChunks++
+			if strings.Count(chunk.RawContent, "| Field | string |") != 30 {
+				t.Errorf("Table was split across chunks")
+			}
+		}
+	}
+	if codeChunks != 1 || tableChunks != 1 {
+		t.Errorf("Expected code block and table in exactly one chunk each, got %d and %d", codeChunks, tableChunks)
+	}
+}
+
+// TestChunkDocument_Overlap verifies split chunks repeat trailing sentences of the previous chunk.
+func TestChunkDocument_Overlap(t *testing.T) {
+	var sentences []string
+	for i := 0; i < 40; i++ {
+		sentences = append(sentences, fmt.Sprintf("Sentence number %d is here.", i))
+	}
+	input := "# Doc\n\n" + strings.Join(sentences, " ") + "\n"
+
+	chunker := NewChunker(Options{MaxTokens: 100, OverlapTokens: 20})
+	chunks, err := chunker.ChunkDocument([]byte(input))
+	if err != nil {
+		t.Fatalf("ChunkDocument failed: %v", err)
+	}
+	if len(chunks) < 2 {
+		t.Fatalf("Expected section to be split, got %d chunks", len(chunks))
+	}
+
+	// The embedded text of the second chunk opens with trailing sentences of the first
+	body := strings.TrimPrefix(chunks[1].Content, chunks[1].HeaderPath+"\n\n")
+	overlap, rest, found := strings.Cut(body, "\n\n")
+	if !found || !strings.HasSuffix(chunks[0].RawContent, overlap) {
+		t.Errorf("Chunk 1 should start with the tail of chunk 0, got %q", chunks[1].Content)
+	}
+	if EstimateTokens(overlap) > 20 {
+		t.Errorf("Overlap %q exceeds 20 tokens", overlap)
+	}
+
+	// The stored text is the chunk's own span
+	if chunks[1].RawContent != rest {
+		t.Errorf("RawContent should not repeat the overlap, got %q", chunks[1].RawContent)
+	}
+	if strings.HasPrefix(chunks[1].RawContent, overlap) {
+		t.Errorf("RawContent starts with the overlap %q", overlap)
+	}
+}
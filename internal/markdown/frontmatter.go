@@ -0,0 +1,109 @@
+package markdown
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FrontMatter holds the Hugo page parameters the index uses.
+type FrontMatter struct {
+	Title       string
+	Description string
+	Weight      int
+	Date        time.Time // Zero if absent or unparseable
+	Aliases     []string
+	URL         string // Hugo permalink override, from the site root
+	Slug        string // Hugo override of the page's last URL segment
+}
+
+// rawFrontMatter mirrors the YAML keys. Dates are decoded as text because Hugo pages
+// use several formats, quoted and unquoted.
+type rawFrontMatter struct {
+	Title       string   `yaml:"title"`
+	Description string   `yaml:"description"`
+	Weight      int      `yaml:"weight"`
+	Date        string   `yaml:"date"`
+	Aliases     []string `yaml:"aliases"`
+	URL         string   `yaml:"url"`
+	Slug        string   `yaml:"slug"`
+}
+
+// dateLayouts are the date formats accepted in front matter, most specific first.
+var dateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+// ParseFrontMatter splits a leading YAML front matter block ("---" fences) from a
+// markdown document. It returns the parsed fields and the body without the block.
+// Documents without front matter are returned unchanged with empty fields. If the
+// block is malformed, the body is still stripped and the error reports why.
+func ParseFrontMatter(source []byte) (FrontMatter, []byte, error) {
+	var raw rawFrontMatter
+	body, err := DecodeFrontMatter(source, &raw)
+	if err != nil {
+		return FrontMatter{}, body, err
+	}
+
+	fm := FrontMatter{
+		Title:       raw.Title,
+		Description: raw.Description,
+		Weight:      raw.Weight,
+		Aliases:     raw.Aliases,
+		URL:         strings.TrimSpace(raw.URL),
+		Slug:        strings.TrimSpace(raw.Slug),
+	}
+	if raw.Date != "" {
+		for _, layout := range dateLayouts {
+			if date, err := time.Parse(layout, raw.Date); err == nil {
+				fm.Date = date
+				break
+			}
+		}
+	}
+
+	return fm, body, nil
+}
+
+// DecodeFrontMatter decodes a leading YAML front matter block into v and returns the
+// body without the block. Sources without front matter are returned unchanged and v
+// is left untouched. If the block is malformed, the body is still stripped.
+func DecodeFrontMatter(source []byte, v any) ([]byte, error) {
+	block, body, ok := splitFrontMatter(source)
+	if !ok {
+		return source, nil
+	}
+	if err := yaml.Unmarshal(block, v); err != nil {
+		return body, fmt.Errorf("parse front matter: %w", err)
+	}
+	return body, nil
+}
+
+// splitFrontMatter returns the YAML between the opening and closing "---" lines and
+// the remaining body. ok is false if source does not start with front matter.
+func splitFrontMatter(source []byte) (block, body []byte, ok bool) {
+	rest := bytes.TrimPrefix(source, []byte("\xef\xbb\xbf")) // Tolerate a UTF-8 BOM
+	firstLine, rest, found := bytes.Cut(rest, []byte("\n"))
+	if !found || strings.TrimSpace(string(firstLine)) != "---" {
+		return nil, source, false
+	}
+
+	offset := 0
+	for offset < len(rest) {
+		line, _, _ := bytes.Cut(rest[offset:], []byte("\n"))
+		if strings.TrimSpace(string(line)) == "---" {
+			block = rest[:offset]
+			body = rest[min(offset+len(line)+1, len(rest)):]
+			return block, bytes.TrimLeft(body, "\r\n"), true
+		}
+		offset += len(line) + 1
+	}
+
+	return nil, source, false // Unterminated: treat as regular content
+}
@@ -0,0 +1,77 @@
+package markdown
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestParseFrontMatter verifies Hugo front matter is parsed and stripped from the body.
+func TestParseFrontMatter(t *testing.T) {
+	input := `---
+title: "Chain & Graph Orchestration"
+description: Compose components into graphs
+weight: 3
+date: 2024-01-15
+aliases:
+  - /docs/eino/core_modules/chain_and_graph
+slug: orchestration
+---
+
+# Orchestration
+
+Body text.
+`
+
+	fm, body, err := ParseFrontMatter([]byte(input))
+	if err != nil {
+		t.Fatalf("ParseFrontMatter failed: %v", err)
+	}
+
+	if fm.Title != "Chain & Graph Orchestration" || fm.Description != "Compose components into graphs" || fm.Weight != 3 {
+		t.Errorf("Unexpected fields: %+v", fm)
+	}
+	if !fm.Date.Equal(time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected date 2024-01-15, got %v", fm.Date)
+	}
+	if len(fm.Aliases) != 1 || fm.Aliases[0] != "/docs/eino/core_modules/chain_and_graph" {
+		t.Errorf("Unexpected aliases: %v", fm.Aliases)
+	}
+	if fm.Slug != "orchestration" || fm.URL != "" {
+		t.Errorf("Unexpected slug %q and url %q", fm.Slug, fm.URL)
+	}
+	if !strings.HasPrefix(string(body), "# Orchestration") {
+		t.Errorf("Body should start at the first heading, got %q", body)
+	}
+}
+
+// TestParseFrontMatter_None verifies documents without front matter are unchanged.
+func TestParseFrontMatter_None(t *testing.T) {
+	inputs := []string{
+		"# Title\n\n---\n\nAfter a thematic break.\n",
+		"---\ntitle: unterminated\n",
+	}
+
+	for _, input := range inputs {
+		fm, body, err := ParseFrontMatter([]byte(input))
+		if err != nil {
+			t.Fatalf("ParseFrontMatter failed: %v", err)
+		}
+		if fm.Title != "" || string(body) != input {
+			t.Errorf("Expected unchanged input %q, got title %q body %q", input, fm.Title, body)
+		}
+	}
+}
+
+// TestParseFrontMatter_Malformed verifies a broken block is still stripped.
+func TestParseFrontMatter_Malformed(t *testing.T) {
+	input := "---\ntitle: [unclosed\n---\nBody.\n"
+
+	_, body, err := ParseFrontMatter([]byte(input))
+	if err == nil {
+		t.Error("Expected a parse error")
+	}
+	if string(body) != "Body.\n" {
+		t.Errorf("Expected stripped body, got %q", body)
+	}
+}
@@ -0,0 +1,115 @@
+package markdown
+
+import (
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/text"
+)
+
+// siteHosts are the hosts whose absolute links point into the documentation itself.
+var siteHosts = map[string]bool{
+	"cloudwego.io":     true,
+	"www.cloudwego.io": true,
+}
+
+// SiteOrigin is the scheme and host of the documentation site.
+const SiteOrigin = "https://www.cloudwego.io"
+
+// SiteURL is where the documentation root (content/en/docs/eino) is published.
+const SiteURL = SiteOrigin + "/docs/eino/"
+
+// PageURL returns the published URL of the document at docPath, following Hugo's
+// defaults: "core/graph.md" is served at "core/graph/", a section's "_index.md" and a
+// bundle's "index.md" at their directory, and paths are lowercased with spaces
+// replaced by hyphens. The front matter's url replaces the whole path, and its slug
+// the last segment of a page or bundle.
+func PageURL(docPath string, fm FrontMatter) string {
+	if fm.URL != "" {
+		return SiteOrigin + "/" + strings.TrimPrefix(fm.URL, "/")
+	}
+
+	page := strings.TrimSuffix(docPath, ".md")
+	base := path.Base(page)
+	if base == "_index" || base == "index" {
+		page = path.Dir(page)
+	}
+	if fm.Slug != "" && base != "_index" && page != "." {
+		page = path.Join(path.Dir(page), fm.Slug)
+	}
+	if page == "." {
+		return SiteURL
+	}
+	page = strings.ReplaceAll(strings.ToLower(page), " ", "-")
+	return SiteURL + page + "/"
+}
+
+// Links returns the internal link targets of the document at docPath, in document
+// order without duplicates. Relative links are resolved the way a browser resolves
+// them on the rendered Hugo page ("core/graph.md" is served at "core/graph/"), or
+// against the file's directory when they name a .md file. Site-absolute links and
+// links to the documentation site are kept by path; other external links, images
+// and same-page anchors are dropped.
+func Links(source []byte, docPath string) []string {
+	doc := goldmark.New().Parser().Parse(text.NewReader(source))
+
+	var links []string
+	seen := make(map[string]bool)
+	ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+
+		var destination string
+		switch node := n.(type) {
+		case *ast.Link:
+			destination = string(node.Destination)
+		case *ast.AutoLink:
+			destination = string(node.URL(source))
+		default:
+			return ast.WalkContinue, nil
+		}
+
+		if target, ok := resolveLink(docPath, destination); ok && !seen[target] {
+			seen[target] = true
+			links = append(links, target)
+		}
+		return ast.WalkContinue, nil
+	})
+	return links
+}
+
+// resolveLink resolves a link destination found in docPath to a path relative to the
+// documentation root, or reports false for external and same-page links.
+func resolveLink(docPath, destination string) (string, bool) {
+	u, err := url.Parse(strings.TrimSpace(destination))
+	if err != nil || u.Path == "" {
+		return "", false
+	}
+	if u.Scheme != "" || u.Host != "" {
+		if !siteHosts[strings.ToLower(u.Hostname())] {
+			return "", false
+		}
+	}
+
+	target := u.Path
+	if !strings.HasPrefix(target, "/") {
+		base := strings.TrimSuffix(docPath, ".md")
+		if path.Base(base) == "_index" {
+			base = path.Dir(base) // A section's _index.md is served at the section itself
+		}
+		if strings.HasSuffix(target, ".md") {
+			base = path.Dir(docPath)
+		}
+		target = path.Join(base, target)
+		if target == ".." || strings.HasPrefix(target, "../") {
+			return "", false
+		}
+	}
+
+	target = strings.Trim(path.Clean(target), "/")
+	return target, target != "" && target != "."
+}
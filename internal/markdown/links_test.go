@@ -0,0 +1,72 @@
+package markdown
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestPageURL verifies documents map to their published Hugo URLs.
+func TestPageURL(t *testing.T) {
+	tests := map[string]string{
+		"core/graph.md":              "https://www.cloudwego.io/docs/eino/core/graph/",
+		"core/_index.md":             "https://www.cloudwego.io/docs/eino/core/",
+		"quick_start/Agent/index.md": "https://www.cloudwego.io/docs/eino/quick_start/agent/",
+		"_index.md":                  "https://www.cloudwego.io/docs/eino/",
+		"FAQ/Common Questions.md":    "https://www.cloudwego.io/docs/eino/faq/common-questions/",
+	}
+	for docPath, want := range tests {
+		if got := PageURL(docPath, FrontMatter{}); got != want {
+			t.Errorf("PageURL(%q) = %q, want %q", docPath, got, want)
+		}
+	}
+
+	// Front matter overrides
+	overrides := []struct {
+		docPath string
+		fm      FrontMatter
+		want    string
+	}{
+		{"core/graph.md", FrontMatter{URL: "/docs/eino/graph-orchestration/"}, "https://www.cloudwego.io/docs/eino/graph-orchestration/"},
+		{"core/graph.md", FrontMatter{Slug: "Graph Basics"}, "https://www.cloudwego.io/docs/eino/core/graph-basics/"},
+		{"quick_start/Agent/index.md", FrontMatter{Slug: "agents"}, "https://www.cloudwego.io/docs/eino/quick_start/agents/"},
+		{"core/_index.md", FrontMatter{Slug: "ignored"}, "https://www.cloudwego.io/docs/eino/core/"},
+	}
+	for _, tt := range overrides {
+		if got := PageURL(tt.docPath, tt.fm); got != tt.want {
+			t.Errorf("PageURL(%q, %+v) = %q, want %q", tt.docPath, tt.fm, got, tt.want)
+		}
+	}
+}
+
+// TestLinks verifies link resolution relative to the rendered page and the source file.
+func TestLinks(t *testing.T) {
+	input := `# Graph
+
+See [chains](../chain/), [the overview](/docs/eino/overview/) and [options](options.md#compile).
+Also [the site](https://www.cloudwego.io/docs/eino/quick_start/), [GitHub](https://github.com/cloudwego/eino),
+[above](#graph), [chains again](../chain/#usage) and <https://cloudwego.io/docs/eino/faq/>.
+
+![diagram](diagram.png)
+`
+
+	got := Links([]byte(input), "core/graph.md")
+	want := []string{
+		"core/chain",
+		"docs/eino/overview",
+		"core/options.md",
+		"docs/eino/quick_start",
+		"docs/eino/faq",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Links() = %v, want %v", got, want)
+	}
+}
+
+// TestLinks_SectionIndex verifies that a section's _index.md resolves links from the section.
+func TestLinks_SectionIndex(t *testing.T) {
+	got := Links([]byte("[graph](graph/) and [up](../../outside/)"), "core/_index.md")
+	want := []string{"core/graph"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Links() = %v, want %v", got, want)
+	}
+}
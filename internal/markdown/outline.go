@@ -0,0 +1,75 @@
+package markdown
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+	"go.abhg.dev/goldmark/toc"
+)
+
+// Heading is a heading in a document outline.
+type Heading struct {
+	Level      int      // Markdown heading level (1-6)
+	Title      string   // Heading text
+	ID         string   // Anchor ID, generated the same way as the chunker's
+	Path       []string // Titles from the outermost enclosing heading down to this one
+	HeaderPath string   // Path formatted like Chunk.HeaderPath: "# Guide > ## Setup"
+	Start      int      // Byte offset of the heading line in the source
+	End        int      // Byte offset where the section, including its subsections, ends
+}
+
+// Outline returns every heading of a markdown document in document order.
+// Paths follow the same hierarchy the chunker uses for header paths, so a
+// heading's Path matches the header path of the chunk it starts.
+func Outline(source []byte) ([]Heading, error) {
+	md := goldmark.New(goldmark.WithParserOptions(parser.WithAutoHeadingID()))
+	doc := md.Parser().Parse(text.NewReader(source))
+
+	tree, err := toc.Inspect(doc, source,
+		toc.MinDepth(1),
+		toc.MaxDepth(6),
+		toc.Compact(true),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("inspect TOC: %w", err)
+	}
+
+	var headings []Heading
+	var depths []int // Tree depth of each heading, for finding section ends
+	var walk func(items toc.Items, ancestors []string)
+	walk = func(items toc.Items, ancestors []string) {
+		for _, item := range items {
+			path := append(ancestors[:len(ancestors):len(ancestors)], string(item.Title))
+			if node := findHeaderByID(doc, string(item.ID)); node != nil {
+				segment := node.Lines().At(0)
+				headings = append(headings, Heading{
+					Level:      node.(*ast.Heading).Level,
+					Title:      string(item.Title),
+					ID:         string(item.ID),
+					Path:       path,
+					HeaderPath: formatHeaderPath(path),
+					Start:      bytes.LastIndexByte(source[:segment.Start], '\n') + 1,
+				})
+				depths = append(depths, len(path))
+			}
+			walk(item.Items, path)
+		}
+	}
+	walk(tree.Items, nil)
+
+	// A section ends where the next heading at the same or a shallower depth begins
+	for i := range headings {
+		headings[i].End = len(source)
+		for j := i + 1; j < len(headings); j++ {
+			if depths[j] <= depths[i] {
+				headings[i].End = headings[j].Start
+				break
+			}
+		}
+	}
+	return headings, nil
+}
@@ -0,0 +1,57 @@
+package markdown
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestOutline verifies heading paths, anchors and section boundaries.
+func TestOutline(t *testing.T) {
+	input := `# Guide
+
+Intro.
+
+## Setup
+
+Install it.
+
+### Requirements
+
+Go 1.24.
+
+## Usage
+
+Run it.
+`
+
+	headings, err := Outline([]byte(input))
+	if err != nil {
+		t.Fatalf("Outline failed: %v", err)
+	}
+	if len(headings) != 4 {
+		t.Fatalf("Expected 4 headings, got %d", len(headings))
+	}
+
+	setup := headings[1]
+	if got := strings.Join(setup.Path, " > "); got != "Guide > Setup" {
+		t.Errorf("Expected path 'Guide > Setup', got %q", got)
+	}
+	if setup.Level != 2 || setup.ID != "setup" {
+		t.Errorf("Expected level 2 with ID 'setup', got level %d ID %q", setup.Level, setup.ID)
+	}
+
+	section := input[setup.Start:setup.End]
+	if !strings.HasPrefix(section, "## Setup") {
+		t.Errorf("Section should start at the heading line, got %q", section)
+	}
+	if !strings.Contains(section, "### Requirements") {
+		t.Error("Section should include its subsections")
+	}
+	if strings.Contains(section, "Usage") {
+		t.Error("Section should stop at the next sibling heading")
+	}
+
+	if guide := headings[0]; guide.End != len(input) {
+		t.Errorf("Top-level section should extend to the end, got end %d of %d", guide.End, len(input))
+	}
+}
@@ -0,0 +1,250 @@
+package markdown
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// part is a piece of a section together with the header path it belongs to.
+type part struct {
+	path    []string
+	text    string
+	overlap string // Trailing context of the previous part (see addOverlap)
+}
+
+// Split levels, tried in order until every part fits the budget.
+const (
+	levelH3 = iota
+	levelH4
+	levelParagraph
+	levelSentence
+	levelCount
+)
+
+// EstimateTokens approximates the token count of s.
+// Rough estimate: 1 token ≈ 4 characters
+func EstimateTokens(s string) int {
+	return (len(s) + 3) / 4
+}
+
+// splitSection breaks content into parts of at most budget tokens. Oversized content is
+// split at H3 headings, then H4 headings, then paragraphs, then sentences; small
+// neighbouring pieces are merged back together. Fenced code blocks and tables are
+// never cut, so a single block larger than the budget becomes its own oversized part.
+func splitSection(path []string, content string, budget int, level int) []part {
+	if EstimateTokens(content) <= budget || level >= levelCount {
+		return []part{{path: path, text: content}}
+	}
+
+	var pieces []part
+	sep := "\n\n"
+	switch level {
+	case levelH3, levelH4:
+		pieces = splitAtHeadings(path, content, level-levelH3+3)
+	case levelParagraph:
+		for _, block := range splitBlocks(content) {
+			pieces = append(pieces, part{path: path, text: block})
+		}
+	case levelSentence:
+		if !isAtomicBlock(content) {
+			for _, sentence := range splitSentences(content) {
+				pieces = append(pieces, part{path: path, text: sentence})
+			}
+		}
+		sep = " "
+	}
+
+	if len(pieces) <= 1 {
+		return splitSection(path, content, budget, level+1)
+	}
+
+	var parts []part
+	for _, piece := range pieces {
+		parts = append(parts, splitSection(piece.path, piece.text, budget, level+1)...)
+	}
+	return mergeParts(parts, budget, sep)
+}
+
+// splitAtHeadings splits content before every heading of the given level outside code
+// fences. Pieces starting with a heading extend the header path with its title.
+func splitAtHeadings(path []string, content string, level int) []part {
+	marker := strings.Repeat("#", level) + " "
+
+	var pieces []part
+	var current []string
+	currentPath := path
+	flush := func() {
+		if text := strings.TrimSpace(strings.Join(current, "\n")); text != "" {
+			pieces = append(pieces, part{path: currentPath, text: text})
+		}
+		current = nil
+	}
+
+	fence := ""
+	for _, line := range strings.Split(content, "\n") {
+		fence = updateFence(fence, line)
+		if fence == "" && strings.HasPrefix(line, marker) {
+			flush()
+			title := strings.TrimSpace(strings.TrimRight(strings.TrimPrefix(line, marker), "# "))
+			currentPath = append(path[:len(path):len(path)], title)
+		}
+		current = append(current, line)
+	}
+	flush()
+
+	return pieces
+}
+
+// splitBlocks splits content into blank-line separated blocks. Blank lines inside
+// fenced code blocks do not end a block.
+func splitBlocks(content string) []string {
+	var blocks []string
+	var current []string
+	flush := func() {
+		if text := strings.TrimSpace(strings.Join(current, "\n")); text != "" {
+			blocks = append(blocks, text)
+		}
+		current = nil
+	}
+
+	fence := ""
+	for _, line := range strings.Split(content, "\n") {
+		fence = updateFence(fence, line)
+		if fence == "" && strings.TrimSpace(line) == "" {
+			flush()
+			continue
+		}
+		current = append(current, line)
+	}
+	flush()
+
+	return blocks
+}
+
+// updateFence tracks fenced code blocks line by line. It returns the fence marker
+// that is open after line, or "" when outside a code block.
+func updateFence(open string, line string) string {
+	trimmed := strings.TrimSpace(line)
+	if open != "" {
+		if strings.HasPrefix(trimmed, open) && strings.TrimLeft(trimmed, open[:1]) == "" {
+			return "" // Closing fence
+		}
+		return open
+	}
+
+	for _, ch := range []string{"`", "~"} {
+		if strings.HasPrefix(trimmed, ch+ch+ch) {
+			return ch + ch + ch
+		}
+	}
+	return ""
+}
+
+// isAtomicBlock reports whether a block must not be split: code blocks, tables,
+// or any block containing a code fence.
+func isAtomicBlock(block string) bool {
+	trimmed := strings.TrimSpace(block)
+	return strings.HasPrefix(trimmed, "|") ||
+		strings.Contains(block, "```") ||
+		strings.Contains(block, "~~~")
+}
+
+// splitSentences splits prose after sentence-ending punctuation. Western punctuation
+// must be followed by whitespace; CJK full stops end a sentence immediately.
+func splitSentences(text string) []string {
+	var sentences []string
+	start := 0
+	for i, r := range text {
+		end := -1
+		switch r {
+		case '.', '!', '?':
+			next := i + 1
+			if next == len(text) || text[next] == ' ' || text[next] == '\n' {
+				end = next
+			}
+		case '。', '！', '？':
+			end = i + utf8.RuneLen(r)
+		}
+		if end < 0 {
+			continue
+		}
+		if sentence := strings.TrimSpace(text[start:end]); sentence != "" {
+			sentences = append(sentences, sentence)
+		}
+		start = end
+	}
+	if rest := strings.TrimSpace(text[start:]); rest != "" {
+		sentences = append(sentences, rest)
+	}
+	return sentences
+}
+
+// mergeParts greedily joins neighbouring parts while they fit the budget.
+// A merged part keeps the header path shared by all of its pieces.
+func mergeParts(parts []part, budget int, sep string) []part {
+	var merged []part
+	for _, p := range parts {
+		if n := len(merged); n > 0 {
+			last := &merged[n-1]
+			if EstimateTokens(last.text)+EstimateTokens(sep)+EstimateTokens(p.text) <= budget {
+				last.text += sep + p.text
+				last.path = commonPath(last.path, p.path)
+				continue
+			}
+		}
+		merged = append(merged, p)
+	}
+	return merged
+}
+
+// commonPath returns the longest shared prefix of two header paths.
+func commonPath(a, b []string) []string {
+	n := 0
+	for n < len(a) && n < len(b) && a[n] == b[n] {
+		n++
+	}
+	return a[:n:n]
+}
+
+// addOverlap sets each part's overlap to trailing sentences of the part before it, up
+// to overlapTokens. Parts that open a new subsection and parts following a code block
+// or table get no overlap.
+func addOverlap(parts []part, overlapTokens int) {
+	if overlapTokens <= 0 {
+		return
+	}
+
+	for i := len(parts) - 1; i > 0; i-- {
+		if strings.HasPrefix(parts[i].text, "#") {
+			continue
+		}
+		parts[i].overlap = overlapTail(parts[i-1].text, overlapTokens)
+	}
+}
+
+// overlapTail returns the longest run of trailing sentences of text's last block
+// that fits within overlapTokens.
+func overlapTail(text string, overlapTokens int) string {
+	blocks := splitBlocks(text)
+	if len(blocks) == 0 {
+		return ""
+	}
+	last := blocks[len(blocks)-1]
+	if isAtomicBlock(last) || strings.HasPrefix(last, "#") {
+		return ""
+	}
+
+	sentences := splitSentences(last)
+	tail := ""
+	for i := len(sentences) - 1; i >= 0; i-- {
+		candidate := sentences[i]
+		if tail != "" {
+			candidate += " " + tail
+		}
+		if EstimateTokens(candidate) > overlapTokens {
+			break
+		}
+		tail = candidate
+	}
+	return tail
+}
@@ -0,0 +1,237 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mike-a-ellis/eino-docs-mcp/internal/prompts"
+	"github.com/mike-a-ellis/eino-docs-mcp/internal/storage"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// maxCompletions is the most values a completion/complete response may carry.
+const maxCompletions = 100
+
+// completionCacheTTL bounds how stale completion candidates can be after a sync.
+const completionCacheTTL = time.Minute
+
+// completeSource completes against the configured source names; only the document
+// resource template's source argument uses it.
+const completeSource = "source"
+
+// completer answers completion/complete requests for document paths, entity names and
+// source names.
+//
+// MCP can only complete prompt and resource-template arguments, so paths complete on
+// the document resource template (the same path fetch_doc takes, narrowed to the
+// source already chosen) and on prompt arguments marked "complete: path"; entity
+// names complete on arguments marked "complete: entity". Candidates are cached for
+// completionCacheTTL.
+type completer struct {
+	store   storage.Store
+	sources sourceSet
+	prompts map[string]*prompts.Prompt
+
+	mu       sync.Mutex
+	loadedAt time.Time
+	paths    map[string][]string // Source name -> document paths
+	entities []string
+}
+
+func newCompleter(store storage.Store, sources sourceSet, library []*prompts.Prompt) *completer {
+	byName := make(map[string]*prompts.Prompt, len(library))
+	for _, p := range library {
+		byName[p.Name] = p
+	}
+	return &completer{store: store, sources: sources, prompts: byName}
+}
+
+// complete implements the completion/complete handler.
+func (c *completer) complete(ctx context.Context, req *mcp.CompleteRequest) (*mcp.CompleteResult, error) {
+	values := []string{} // Non-nil for JSON marshaling
+
+	if kind := c.argumentKind(req.Params.Ref, req.Params.Argument.Name); kind != "" {
+		var source string
+		if req.Params.Context != nil {
+			source = req.Params.Context.Arguments["source"]
+		}
+		candidates, err := c.candidates(ctx, kind, source)
+		if err != nil {
+			return nil, err
+		}
+		values = matchCompletions(candidates, req.Params.Argument.Value)
+	}
+
+	total := len(values)
+	return &mcp.CompleteResult{
+		Completion: mcp.CompletionResultDetails{
+			Values:  values[:min(total, maxCompletions)],
+			Total:   total,
+			HasMore: total > maxCompletions,
+		},
+	}, nil
+}
+
+// argumentKind returns prompts.CompletePath, prompts.CompleteEntity, completeSource
+// or "" for an argument that does not complete.
+func (c *completer) argumentKind(ref *mcp.CompleteReference, argument string) string {
+	if ref == nil {
+		return ""
+	}
+
+	switch ref.Type {
+	case "ref/resource":
+		if !strings.HasPrefix(ref.URI, resourceURIScheme) {
+			return ""
+		}
+		switch argument {
+		case "path":
+			return prompts.CompletePath
+		case "source":
+			return completeSource
+		}
+	case "ref/prompt":
+		p, ok := c.prompts[ref.Name]
+		if !ok {
+			return ""
+		}
+		for _, arg := range p.Arguments {
+			if arg.Name == argument {
+				return arg.Complete
+			}
+		}
+	}
+	return ""
+}
+
+// candidates returns the source names, or the cached paths or entities, reloading
+// them when stale. Paths are those of source, or of every source when it is empty
+// or unknown.
+func (c *completer) candidates(ctx context.Context, kind, source string) ([]string, error) {
+	if kind == completeSource {
+		return c.sources.names(), nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if time.Since(c.loadedAt) > completionCacheTTL {
+		paths := make(map[string][]string, len(c.sources))
+		var entities []string
+		for _, cfg := range c.sources {
+			sourcePaths, err := c.store.ListDocumentPaths(ctx, cfg.Name)
+			if err != nil {
+				return nil, fmt.Errorf("failed to list documents: %w", err)
+			}
+			sourceEntities, err := c.store.ListEntities(ctx, cfg.Name)
+			if err != nil {
+				return nil, fmt.Errorf("failed to list entities: %w", err)
+			}
+			paths[cfg.Name] = sourcePaths
+			entities = append(entities, sourceEntities...)
+		}
+		sort.Strings(entities)
+		c.paths, c.entities, c.loadedAt = paths, slices.Compact(entities), time.Now()
+	}
+
+	if kind == prompts.CompleteEntity {
+		return c.entities, nil
+	}
+	if paths, ok := c.paths[source]; ok {
+		return paths, nil
+	}
+	var all []string
+	for _, paths := range c.paths {
+		all = append(all, paths...)
+	}
+	return distinctPaths(all), nil
+}
+
+// Match quality, best first.
+const (
+	matchPrefix      = iota // Candidate starts with the value
+	matchSegment            // A path segment or dotted name part starts with the value
+	matchSubstring          // Value appears anywhere
+	matchSubsequence        // Value's characters appear in order (fuzzy)
+)
+
+// matchCompletions returns the candidates matching value case-insensitively, ordered
+// by match quality, then length, then alphabetically. An empty value matches all.
+func matchCompletions(candidates []string, value string) []string {
+	needle := strings.ToLower(value)
+
+	type match struct {
+		value   string
+		quality int
+	}
+	var matches []match
+	for _, candidate := range candidates {
+		if quality, ok := matchQuality(strings.ToLower(candidate), needle); ok {
+			matches = append(matches, match{candidate, quality})
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		a, b := matches[i], matches[j]
+		if a.quality != b.quality {
+			return a.quality < b.quality
+		}
+		if len(a.value) != len(b.value) {
+			return len(a.value) < len(b.value)
+		}
+		return a.value < b.value
+	})
+
+	values := make([]string, len(matches))
+	for i, m := range matches {
+		values[i] = m.value
+	}
+	return values
+}
+
+// matchQuality classifies how candidate matches needle (both lowercase).
+func matchQuality(candidate, needle string) (int, bool) {
+	switch {
+	case strings.HasPrefix(candidate, needle):
+		return matchPrefix, true
+	case hasSegmentPrefix(candidate, needle):
+		return matchSegment, true
+	case strings.Contains(candidate, needle):
+		return matchSubstring, true
+	case isSubsequence(candidate, needle):
+		return matchSubsequence, true
+	}
+	return 0, false
+}
+
+// hasSegmentPrefix reports whether a part of candidate following "/", ".", "-" or "_"
+// starts with needle, e.g. "graph" in "core/graph.md" or "newgraph" in "compose.newgraph".
+func hasSegmentPrefix(candidate, needle string) bool {
+	for i := 0; i < len(candidate); i++ {
+		switch candidate[i] {
+		case '/', '.', '-', '_':
+			if strings.HasPrefix(candidate[i+1:], needle) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isSubsequence reports whether needle's characters appear in candidate in order.
+func isSubsequence(candidate, needle string) bool {
+	rest := candidate
+	for _, r := range needle {
+		i := strings.IndexRune(rest, r)
+		if i < 0 {
+			return false
+		}
+		rest = rest[i+len(string(r)):]
+	}
+	return true
+}
@@ -0,0 +1,68 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mike-a-ellis/eino-docs-mcp/internal/prompts"
+)
+
+func TestMatchCompletions(t *testing.T) {
+	candidates := []string{
+		"core/graph.md",
+		"core/chain.md",
+		"graph-basics.md",
+		"overview.md",
+		"components/chat_model.md",
+	}
+
+	assert.Equal(t, []string{"graph-basics.md", "core/graph.md"}, matchCompletions(candidates, "graph"),
+		"prefix matches rank before path segment matches")
+	assert.Equal(t, []string{"core/chain.md", "components/chat_model.md", "core/graph.md"}, matchCompletions(candidates, "ch"),
+		"segment matches are ordered by length, fuzzy matches come last")
+	assert.Equal(t, []string{"overview.md"}, matchCompletions(candidates, "VIEW"),
+		"matching is case-insensitive")
+	assert.Equal(t, []string{"components/chat_model.md"}, matchCompletions(candidates, "cmpchmdl"),
+		"subsequences match fuzzily")
+	assert.Len(t, matchCompletions(candidates, ""), len(candidates))
+	assert.Empty(t, matchCompletions(candidates, "zzz"))
+}
+
+func TestCompletion_PathsAndEntities(t *testing.T) {
+	env := newTestEnv(t)
+	library, err := prompts.Load("")
+	require.NoError(t, err)
+
+	server := newServer(t, &Config{Storage: env.store, Embedder: env.embedder, Prompts: library})
+	session := connectClient(t, server, nil)
+	ctx := context.Background()
+
+	// Resource template path argument
+	result, err := session.Complete(ctx, &mcp.CompleteParams{
+		Ref:      &mcp.CompleteReference{Type: "ref/resource", URI: resourceURITemplate},
+		Argument: mcp.CompleteParamsArgument{Name: "path", Value: "grap"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"core/graph.md"}, result.Completion.Values)
+	assert.Equal(t, 1, result.Completion.Total)
+
+	// Prompt argument marked "complete: entity"
+	result, err = session.Complete(ctx, &mcp.CompleteParams{
+		Ref:      &mcp.CompleteReference{Type: "ref/prompt", Name: "migrate-callback-api"},
+		Argument: mcp.CompleteParamsArgument{Name: "api", Value: "newgr"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"compose.NewGraph"}, result.Completion.Values)
+
+	// Free-text arguments do not complete
+	result, err = session.Complete(ctx, &mcp.CompleteParams{
+		Ref:      &mcp.CompleteReference{Type: "ref/prompt", Name: "migrate-callback-api"},
+		Argument: mcp.CompleteParamsArgument{Name: "code", Value: "c"},
+	})
+	require.NoError(t, err)
+	assert.Empty(t, result.Completion.Values)
+}
@@ -4,24 +4,31 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
 
-	"github.com/mike-a-ellis/eino-docs-mcp/internal/embedding"
 	ghclient "github.com/mike-a-ellis/eino-docs-mcp/internal/github"
+	"github.com/mike-a-ellis/eino-docs-mcp/internal/markdown"
+	"github.com/mike-a-ellis/eino-docs-mcp/internal/scheduler"
+	"github.com/mike-a-ellis/eino-docs-mcp/internal/search"
+	"github.com/mike-a-ellis/eino-docs-mcp/internal/source"
 	"github.com/mike-a-ellis/eino-docs-mcp/internal/storage"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 )
 
-const defaultRepository = "cloudwego/cloudwego.github.io"
-
 // makeSearchHandler creates the search_docs tool handler.
 // Search flow:
-// 1. Generate embedding for query text
-// 2. Search chunks with vector similarity (limit * 3 to get enough parents)
-// 3. Filter by minimum score threshold
-// 4. Deduplicate by parent document (keep highest-scoring chunk per doc)
-// 5. Fetch parent document metadata for each unique doc
-// 6. Return up to MaxResults documents with metadata (not content)
-func makeSearchHandler(store *storage.QdrantStorage, embedder *embedding.Embedder) func(
+// 1. Retrieve chunks via dense, sparse (BM25) or hybrid search (limit * 3 to get enough parents)
+// 2. Dense candidates below the minimum score threshold are dropped
+// 3. Optionally rerank a candidate pool with the configured reranker
+// 4. Re-rank with MMR so near-duplicate chunks do not crowd out other documents
+// 5. Deduplicate by parent document (keep highest-scoring chunk per doc), over-fetching until enough docs
+// 6. Fetch parent document metadata for each unique doc
+// 7. Return up to MaxResults documents with metadata (not content), symbols ranked among them
+func makeSearchHandler(store storage.Store, searcher *search.Searcher, sources sourceSet) func(
 	context.Context, *mcp.CallToolRequest, SearchDocsInput,
 ) (*mcp.CallToolResult, SearchDocsOutput, error) {
 	return func(ctx context.Context, req *mcp.CallToolRequest, input SearchDocsInput) (
@@ -36,57 +43,85 @@ func makeSearchHandler(store *storage.QdrantStorage, embedder *embedding.Embedde
 		if minScore <= 0 {
 			minScore = 0.3
 		}
-
-		// Generate embedding for query
-		embeddings, err := embedder.GenerateEmbeddings(ctx, []string{input.Query})
+		mode, err := search.ParseMode(input.Mode)
 		if err != nil {
-			return nil, SearchDocsOutput{}, fmt.Errorf("failed to embed query: %w", err)
+			return nil, SearchDocsOutput{}, err
+		}
+		if _, err := sources.selected(input.Source); err != nil {
+			return nil, SearchDocsOutput{}, err
 		}
-		queryEmbedding := embeddings[0]
 
-		// Search chunks (request 3x to ensure enough unique documents after dedup)
-		chunks, err := store.SearchChunksWithScores(ctx, queryEmbedding, maxResults*3, defaultRepository)
-		if err != nil {
-			return nil, SearchDocsOutput{}, fmt.Errorf("search failed: %w", err)
+		lambda := input.MMRLambda
+		if lambda <= 0 {
+			lambda = search.DefaultMMRLambda
 		}
 
-		// Deduplicate by parent document, keeping highest score per doc
-		docScores := make(map[string]float64) // docID -> highest score
-		docIDs := make([]string, 0)           // preserve order
-		for _, chunk := range chunks {
-			if chunk.Score < minScore {
-				continue // Below threshold
-			}
-			if existing, seen := docScores[chunk.ParentDocID]; !seen || chunk.Score > existing {
-				if !seen {
-					docIDs = append(docIDs, chunk.ParentDocID)
+		opts := search.Options{
+			Mode:      mode,
+			MinScore:  minScore,
+			MMRLambda: lambda,
+			Filter: storage.SearchFilter{
+				Source:     input.Source,
+				PathPrefix: input.PathPrefix,
+				Categories: input.Categories,
+				Entities:   input.Entities,
+				Symbols:    !input.ExcludeSymbols,
+			},
+		}
+		var message string
+		if input.Rerank {
+			if searcher.CanRerank() {
+				opts.Rerank = true
+				opts.Limit = input.RerankCandidates
+				if opts.Limit <= 0 {
+					opts.Limit = 30
+				}
+				if opts.Limit > 100 {
+					opts.Limit = 100
 				}
-				docScores[chunk.ParentDocID] = chunk.Score
+			} else {
+				message = "Reranking is not configured on this server; results are ranked by retrieval score."
 			}
 		}
 
-		// Limit to maxResults
-		if len(docIDs) > maxResults {
-			docIDs = docIDs[:maxResults]
+		ranked, err := searchDocuments(ctx, store, searcher, input.Query, maxResults, opts)
+		if err != nil {
+			return nil, SearchDocsOutput{}, err
 		}
 
-		// Fetch document metadata for each unique document
-		results := make([]SearchResult, 0, len(docIDs))
-		for _, docID := range docIDs {
-			doc, err := store.GetDocument(ctx, docID)
-			if err != nil {
-				continue // Skip documents that fail to load
+		results := make([]SearchResult, 0, len(ranked))
+		for _, r := range ranked {
+			if r.symbol != nil {
+				symbol := symbolResult(r.symbol)
+				results = append(results, SearchResult{
+					Type:          "symbol",
+					Score:         r.score,
+					OriginalScore: r.originalScore,
+					Summary:       r.symbol.Doc,
+					Entities:      []string{},
+					Title:         symbol.Name,
+					Symbol:        &symbol,
+				})
+				continue
 			}
+			doc := r.doc
 			entities := doc.Metadata.Entities
 			if entities == nil {
 				entities = []string{} // Ensure non-nil for JSON marshaling
 			}
 			results = append(results, SearchResult{
-				Path:      doc.Metadata.Path,
-				Score:     docScores[docID],
-				Summary:   doc.Metadata.Summary,
-				Entities:  entities,
-				UpdatedAt: doc.Metadata.IndexedAt,
+				Type:          "document",
+				Path:          doc.Metadata.Path,
+				Source:        doc.Metadata.Source,
+				Score:         r.score,
+				OriginalScore: r.originalScore,
+				Summary:       doc.Metadata.Summary,
+				Entities:      entities,
+				Title:         doc.Metadata.Title,
+				Description:   doc.Metadata.Description,
+				Weight:        doc.Metadata.Weight,
+				Date:          optionalTime(doc.Metadata.Date),
+				UpdatedAt:     doc.Metadata.IndexedAt,
 			})
 		}
 
@@ -97,26 +132,229 @@ func makeSearchHandler(store *storage.QdrantStorage, embedder *embedding.Embedde
 			}, nil
 		}
 
-		return nil, SearchDocsOutput{Results: results}, nil
+		return nil, SearchDocsOutput{Results: results, Message: message}, nil
+	}
+}
+
+// makeSearchChunksHandler creates the search_chunks tool handler.
+// Unlike search_docs, it returns the matching passages themselves so agents can answer
+// from snippets without fetching whole documents. Passages are added best-first until
+// the token budget is spent; a first passage larger than the budget is shortened.
+func makeSearchChunksHandler(searcher *search.Searcher, sources sourceSet) func(
+	context.Context, *mcp.CallToolRequest, SearchChunksInput,
+) (*mcp.CallToolResult, SearchChunksOutput, error) {
+	return func(ctx context.Context, req *mcp.CallToolRequest, input SearchChunksInput) (
+		*mcp.CallToolResult, SearchChunksOutput, error,
+	) {
+		// Apply defaults
+		maxResults := input.MaxResults
+		if maxResults <= 0 {
+			maxResults = 10
+		}
+		if maxResults > 50 {
+			maxResults = 50
+		}
+		maxTokens := input.MaxTokens
+		if maxTokens <= 0 {
+			maxTokens = 2000
+		}
+		minScore := input.MinScore
+		if minScore <= 0 {
+			minScore = 0.3
+		}
+		mode, err := search.ParseMode(input.Mode)
+		if err != nil {
+			return nil, SearchChunksOutput{}, err
+		}
+		if _, err := sources.selected(input.Source); err != nil {
+			return nil, SearchChunksOutput{}, err
+		}
+
+		lambda := input.MMRLambda
+		if lambda <= 0 {
+			lambda = search.DefaultMMRLambda
+		}
+
+		chunks, err := searcher.Search(ctx, input.Query, search.Options{
+			Mode:      mode,
+			Limit:     maxResults,
+			MinScore:  minScore,
+			Filter:    storage.SearchFilter{Source: input.Source, Symbols: !input.ExcludeSymbols},
+			MMRLambda: lambda,
+		})
+		if err != nil {
+			return nil, SearchChunksOutput{}, fmt.Errorf("search failed: %w", err)
+		}
+
+		// Rough estimate: 1 token ≈ 4 characters
+		budget := maxTokens * 4
+
+		output := SearchChunksOutput{Chunks: []ChunkResult{}}
+		for _, chunk := range chunks {
+			content := chunk.Content
+			if output.TotalChars+len(content) > budget {
+				output.Truncated = true
+				if len(output.Chunks) > 0 {
+					break
+				}
+				content = truncateUTF8(content, budget) // Always return at least part of the best match
+			}
+
+			result := ChunkResult{
+				Path:       chunk.Path,
+				Source:     chunk.Source,
+				HeaderPath: chunk.HeaderPath,
+				ChunkIndex: chunk.ChunkIndex,
+				Score:      chunk.Score,
+				Content:    content,
+			}
+			if chunk.Symbol != nil {
+				symbol := symbolResult(chunk.Symbol)
+				result.Symbol = &symbol
+			}
+			output.Chunks = append(output.Chunks, result)
+			output.TotalChars += len(content)
+		}
+
+		if len(output.Chunks) == 0 {
+			output.Message = "No matching passages found. Try broader search terms."
+		}
+
+		return nil, output, nil
+	}
+}
+
+// truncateUTF8 shortens s to at most n bytes without splitting a UTF-8 sequence.
+func truncateUTF8(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
+
+// rankedDocument is a parent document with the score of its best-matching chunk, or
+// a Go API symbol when the search filter lets symbols through.
+type rankedDocument struct {
+	doc           *storage.Document // Nil for symbols
+	symbol        *storage.Symbol
+	score         float64
+	originalScore *float64 // Retrieval score of that chunk when reranked
+}
+
+// maxSearchCandidates caps how many chunks searchDocuments fetches while looking for
+// enough distinct documents.
+const maxSearchCandidates = 200
+
+// searchDocuments is the document retrieval behind search_docs and the prompts.
+// Chunks are searched with opts (3x maxResults to start, or opts.Limit if larger),
+// deduplicated by parent document keeping the highest score, and up to maxResults
+// parents loaded in order. When one page's chunks crowd out the rest, the search is
+// repeated with twice the chunks until maxResults distinct documents are found, the
+// results run out or maxSearchCandidates is reached; the query is embedded once for
+// all of them. Reranked searches keep their candidate pool, since every candidate
+// costs a reranker call. An empty opts.Filter.Source searches every source. Symbols
+// are ranked alongside the documents, without a parent, when opts.Filter.Symbols is set.
+func searchDocuments(
+	ctx context.Context, store storage.Store, searcher *search.Searcher,
+	query string, maxResults int, opts search.Options,
+) ([]rankedDocument, error) {
+	opts.Limit = max(opts.Limit, maxResults*3)
+	if opts.Mode != search.ModeSparse && opts.QueryVector == nil {
+		vector, err := searcher.EmbedQuery(ctx, query)
+		if err != nil {
+			return nil, fmt.Errorf("search failed: %w", err)
+		}
+		opts.QueryVector = vector
+	}
+
+	var best map[string]*storage.ScoredChunk // docID -> highest-scoring chunk
+	var docIDs []string                      // preserve order
+	for {
+		chunks, err := searcher.Search(ctx, query, opts)
+		if err != nil {
+			return nil, fmt.Errorf("search failed: %w", err)
+		}
+
+		// Deduplicate by parent document, keeping highest score per doc; each symbol
+		// stands alone
+		best = make(map[string]*storage.ScoredChunk)
+		docIDs = docIDs[:0]
+		for _, chunk := range chunks {
+			key := chunk.ParentDocID
+			if chunk.Symbol != nil {
+				key = chunk.ID
+			}
+			if existing, seen := best[key]; !seen || chunk.Score > existing.Score {
+				if !seen {
+					docIDs = append(docIDs, key)
+				}
+				best[key] = chunk
+			}
+		}
+
+		if len(docIDs) >= maxResults || len(chunks) < opts.Limit || opts.Limit >= maxSearchCandidates || opts.Rerank {
+			break
+		}
+		opts.Limit = min(opts.Limit*2, maxSearchCandidates)
+	}
+
+	// Limit to maxResults
+	if len(docIDs) > maxResults {
+		docIDs = docIDs[:maxResults]
+	}
+
+	// Fetch each unique parent document
+	ranked := make([]rankedDocument, 0, len(docIDs))
+	for _, docID := range docIDs {
+		chunk := best[docID]
+		if chunk.Symbol != nil {
+			ranked = append(ranked, rankedDocument{symbol: chunk.Symbol, score: chunk.Score, originalScore: chunk.OriginalScore})
+			continue
+		}
+		doc, err := store.GetDocument(ctx, docID)
+		if err != nil {
+			continue // Skip documents that fail to load
+		}
+		ranked = append(ranked, rankedDocument{doc: doc, score: chunk.Score, originalScore: chunk.OriginalScore})
 	}
+	return ranked, nil
 }
 
 // makeFetchHandler creates the fetch_doc tool handler.
-// Retrieves full document content by path.
+// Retrieves full document content by path or alias.
 // Prepends source header: <!-- Source: path/to/doc.md -->
-func makeFetchHandler(store *storage.QdrantStorage) func(
+// When nothing matches, the closest existing paths are suggested (see suggestPaths).
+func makeFetchHandler(store storage.Store, searcher *search.Searcher, sources sourceSet) func(
 	context.Context, *mcp.CallToolRequest, FetchDocInput,
 ) (*mcp.CallToolResult, FetchDocOutput, error) {
 	return func(ctx context.Context, req *mcp.CallToolRequest, input FetchDocInput) (
 		*mcp.CallToolResult, FetchDocOutput, error,
 	) {
-		doc, err := store.GetDocumentByPath(ctx, input.Path, defaultRepository)
+		selected, err := sources.selected(input.Source)
+		if err != nil {
+			return nil, FetchDocOutput{}, err
+		}
+
+		doc, err := findDocument(ctx, store, selected, input.Path)
 		if err != nil {
 			// Return helpful response for not found
 			if errors.Is(err, storage.ErrDocumentNotFound) {
+				suggestions, err := suggestPaths(ctx, store, searcher, selected, input.Path)
+				if err != nil {
+					return nil, FetchDocOutput{}, fmt.Errorf("failed to suggest paths: %w", err)
+				}
+				message := "Document not found. Use list_docs or search_docs to find available paths."
+				if len(suggestions) > 0 {
+					message = fmt.Sprintf("Document not found. Did you mean %s?", suggestions[0].Path)
+				}
 				return nil, FetchDocOutput{
-					Found: false,
-					Path:  input.Path,
+					Found:       false,
+					Path:        input.Path,
+					Suggestions: suggestions,
+					Message:     message,
 				}, nil
 			}
 			return nil, FetchDocOutput{}, fmt.Errorf("failed to fetch document: %w", err)
@@ -125,114 +363,275 @@ func makeFetchHandler(store *storage.QdrantStorage) func(
 		// Prepend source header
 		content := fmt.Sprintf("<!-- Source: %s -->\n\n%s", doc.Metadata.Path, doc.Content)
 
+		// Indexes built before page URLs were stored derive them from the document;
+		// only the Eino User Manual is published on cloudwego.io
+		pageURL := doc.Metadata.PageURL
+		if cfg, ok := sources.lookup(doc.Metadata.Source); pageURL == "" && ok && cfg.PublishedOnSite() {
+			frontMatter, _, _ := markdown.ParseFrontMatter([]byte(doc.Content))
+			pageURL = markdown.PageURL(doc.Metadata.Path, frontMatter)
+		}
+
 		return nil, FetchDocOutput{
-			Content:   content,
-			Path:      doc.Metadata.Path,
-			Summary:   doc.Metadata.Summary,
-			UpdatedAt: doc.Metadata.IndexedAt,
-			Found:     true,
+			Content:     content,
+			Path:        doc.Metadata.Path,
+			Source:      doc.Metadata.Source,
+			Summary:     doc.Metadata.Summary,
+			Title:       doc.Metadata.Title,
+			Description: doc.Metadata.Description,
+			Weight:      doc.Metadata.Weight,
+			Date:        optionalTime(doc.Metadata.Date),
+			Aliases:     doc.Metadata.Aliases,
+			SourceURL:   doc.Metadata.URL,
+			PageURL:     pageURL,
+			CommitSHA:   doc.Metadata.CommitSHA,
+			UpdatedAt:   doc.Metadata.IndexedAt,
+			Found:       true,
 		}, nil
 	}
 }
 
+// findDocument retrieves a document by path from the first of sources that has it,
+// falling back to Hugo aliases so old URLs keep resolving.
+func findDocument(ctx context.Context, store storage.Store, sources sourceSet, path string) (*storage.Document, error) {
+	for _, lookup := range []func(context.Context, string, string) (*storage.Document, error){
+		store.GetDocumentByPath,
+		store.GetDocumentByAlias,
+	} {
+		for _, cfg := range sources {
+			doc, err := lookup(ctx, path, cfg.Name)
+			if !errors.Is(err, storage.ErrDocumentNotFound) {
+				return doc, err
+			}
+		}
+	}
+	return nil, storage.ErrDocumentNotFound
+}
+
+// optionalTime returns nil for the zero time so unset dates are omitted from JSON.
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
 // makeListHandler creates the list_docs tool handler.
-// Returns all available document paths.
-func makeListHandler(store *storage.QdrantStorage) func(
+// Returns all available document paths of the selected sources, and the sources.
+func makeListHandler(store storage.Store, sources sourceSet) func(
 	context.Context, *mcp.CallToolRequest, ListDocsInput,
 ) (*mcp.CallToolResult, ListDocsOutput, error) {
 	return func(ctx context.Context, req *mcp.CallToolRequest, input ListDocsInput) (
 		*mcp.CallToolResult, ListDocsOutput, error,
 	) {
-		paths, err := store.ListDocumentPaths(ctx, defaultRepository)
+		selected, err := sources.selected(input.Source)
 		if err != nil {
-			return nil, ListDocsOutput{}, fmt.Errorf("failed to list documents: %w", err)
+			return nil, ListDocsOutput{}, err
 		}
 
+		var all []string
+		summaries := make([]SourceSummary, 0, len(selected))
+		for _, cfg := range selected {
+			paths, err := store.ListDocumentPaths(ctx, cfg.Name)
+			if err != nil {
+				return nil, ListDocsOutput{}, fmt.Errorf("failed to list documents: %w", err)
+			}
+			all = append(all, paths...)
+			summaries = append(summaries, SourceSummary{Name: cfg.Name, Repository: cfg.Repository(), Count: len(paths)})
+		}
+		paths := distinctPaths(all)
+
 		return nil, ListDocsOutput{
-			Paths: paths,
-			Count: len(paths),
+			Paths:      paths,
+			Count:      len(paths),
+			Categories: categories(paths),
+			Sources:    summaries,
 		}, nil
 	}
 }
 
+// distinctPaths returns paths sorted, without duplicates, and non-nil for JSON marshaling.
+func distinctPaths(paths []string) []string {
+	result := make([]string, 0, len(paths))
+	result = append(result, paths...)
+	sort.Strings(result)
+	return slices.Compact(result)
+}
+
+// categories returns the distinct, sorted categories (top-level directories) of paths.
+func categories(paths []string) []string {
+	seen := make(map[string]bool)
+	for _, path := range paths {
+		if category := storage.Category(path); category != "" {
+			seen[category] = true
+		}
+	}
+	result := make([]string, 0, len(seen))
+	for category := range seen {
+		result = append(result, category)
+	}
+	sort.Strings(result)
+	return result
+}
+
 // makeStatusHandler creates the get_index_status tool handler.
 // Returns comprehensive index status including document counts, paths, last sync time,
-// source commit SHA, and staleness information (commits behind GitHub HEAD).
+// and for each source its commit SHA and staleness (commits behind its branch HEAD on
+// GitHub), plus the auto-sync scheduler's state when one is running.
 func makeStatusHandler(
-	store *storage.QdrantStorage,
+	store storage.Store,
 	ghClient *ghclient.Client,
+	sched *scheduler.Scheduler,
+	sources sourceSet,
 ) func(context.Context, *mcp.CallToolRequest, StatusInput) (*mcp.CallToolResult, StatusOutput, error) {
 	return func(ctx context.Context, req *mcp.CallToolRequest, input StatusInput) (
 		*mcp.CallToolResult, StatusOutput, error,
 	) {
-		// Get document paths
-		paths, err := store.ListDocumentPaths(ctx, defaultRepository)
+		selected, err := sources.selected(input.Source)
 		if err != nil {
-			return nil, StatusOutput{}, fmt.Errorf("qdrant_error: failed to list documents: %w", err)
+			return nil, StatusOutput{}, err
 		}
 
-		totalDocs := len(paths)
-
-		// Get commit SHA
-		commitSHA, err := store.GetCommitSHA(ctx, defaultRepository)
-		if err != nil {
-			return nil, StatusOutput{}, fmt.Errorf("qdrant_error: failed to get commit SHA: %w", err)
+		var allPaths []string
+		var warnings []string
+		var lastSync time.Time
+		statuses := make([]SourceStatus, 0, len(selected))
+		for _, cfg := range selected {
+			paths, status, indexedAt, err := sourceStatus(ctx, store, ghClient, cfg)
+			if err != nil {
+				return nil, StatusOutput{}, err
+			}
+			allPaths = append(allPaths, paths...)
+			if status.StaleWarning != "" {
+				warnings = append(warnings, status.StaleWarning)
+			}
+			if indexedAt.After(lastSync) {
+				lastSync = indexedAt
+			}
+			statuses = append(statuses, status)
 		}
+		paths := distinctPaths(allPaths)
 
-		// Get last sync time from any document (they all have same IndexedAt for a sync)
 		var lastSyncTime string
-		if totalDocs > 0 {
-			doc, err := store.GetDocumentByPath(ctx, paths[0], defaultRepository)
-			if err != nil {
-				return nil, StatusOutput{}, fmt.Errorf("qdrant_error: failed to get document for timestamp: %w", err)
-			}
-			lastSyncTime = doc.Metadata.IndexedAt.Format("2006-01-02T15:04:05Z07:00")
+		if !lastSync.IsZero() {
+			lastSyncTime = lastSync.Format("2006-01-02T15:04:05Z07:00")
 		}
 
-		// Get total points count from collection to calculate chunk count
+		// Get chunk and symbol counts from the collection
 		collectionInfo, err := store.GetCollectionInfo(ctx)
 		if err != nil {
 			return nil, StatusOutput{}, fmt.Errorf("qdrant_error: failed to get collection info: %w", err)
 		}
+		totalChunks := int(collectionInfo.ChunksCount)
+		totalSymbols := int(collectionInfo.SymbolsCount)
 
-		// Total chunks = total points - parent documents
-		// Each document creates 1 parent point + N chunk points
-		totalChunks := int(collectionInfo.PointsCount) - totalDocs
-
-		// Check staleness against GitHub HEAD
-		var commitsBehind *int
-		var staleWarning string
-
-		if commitSHA != "" && ghClient != nil {
-			// Compare indexed commit (base) with main branch (head)
-			comparison, _, err := ghClient.Repositories.CompareCommits(
-				ctx,
-				"cloudwego",
-				"cloudwego.github.io",
-				commitSHA,
-				"main",
-				nil,
-			)
-			if err == nil && comparison != nil {
-				behind := comparison.GetAheadBy()
-				commitsBehind = &behind
-
-				// Set warning if >20 commits behind
-				if behind > 20 {
-					staleWarning = fmt.Sprintf("Index is %d commits behind GitHub HEAD. Consider resyncing.", behind)
-				}
-			}
-			// If GitHub API fails, leave commitsBehind as nil (not an error for the tool)
+		totalDocs := 0
+		for _, status := range statuses {
+			totalDocs += status.TotalDocs
 		}
 
 		return nil, StatusOutput{
 			TotalDocs:     totalDocs,
 			TotalChunks:   totalChunks,
+			TotalSymbols:  totalSymbols,
 			IndexedPaths:  paths,
 			LastSyncTime:  lastSyncTime,
-			SourceCommit:  commitSHA,
-			CommitsBehind: commitsBehind,
-			StaleWarning:  staleWarning,
+			SourceCommit:  statuses[0].SourceCommit,
+			CommitsBehind: statuses[0].CommitsBehind,
+			StaleWarning:  strings.Join(warnings, " "),
+			Sources:       statuses,
+			AutoSync:      autoSyncStatus(sched),
 		}, nil
 	}
 }
+
+// sourceStatus reports one source for get_index_status: its indexed paths and
+// status, and when it was last synced (zero if never).
+func sourceStatus(
+	ctx context.Context, store storage.Store, ghClient *ghclient.Client, cfg source.Config,
+) ([]string, SourceStatus, time.Time, error) {
+	status := SourceStatus{
+		Name:       cfg.Name,
+		Repository: cfg.Repository(),
+		Ref:        cfg.Ref,
+		BasePath:   cfg.BasePath,
+	}
+
+	// Get document paths
+	paths, err := store.ListDocumentPaths(ctx, cfg.Name)
+	if err != nil {
+		return nil, status, time.Time{}, fmt.Errorf("qdrant_error: failed to list documents: %w", err)
+	}
+	status.TotalDocs = len(paths)
+
+	// Get commit SHA
+	commitSHA, err := store.GetCommitSHA(ctx, cfg.Name)
+	if err != nil {
+		return nil, status, time.Time{}, fmt.Errorf("qdrant_error: failed to get commit SHA: %w", err)
+	}
+	status.SourceCommit = commitSHA
+
+	// Get last sync time: incremental and webhook syncs re-index only some documents,
+	// so take the latest of them
+	indexedAt, err := store.LastIndexedAt(ctx, cfg.Name)
+	if err != nil {
+		return nil, status, time.Time{}, fmt.Errorf("qdrant_error: failed to get last index time: %w", err)
+	}
+	if !indexedAt.IsZero() {
+		status.LastSyncTime = indexedAt.Format("2006-01-02T15:04:05Z07:00")
+	}
+
+	// Check staleness against GitHub HEAD
+	if commitSHA != "" && ghClient != nil {
+		// Compare indexed commit (base) with the source's branch (head)
+		comparison, _, err := ghClient.Repositories.CompareCommits(
+			ctx,
+			cfg.Owner,
+			cfg.Repo,
+			commitSHA,
+			cfg.Ref,
+			nil,
+		)
+		if err == nil && comparison != nil {
+			behind := comparison.GetAheadBy()
+			status.CommitsBehind = &behind
+
+			// Set warning if >20 commits behind
+			if behind > 20 {
+				status.StaleWarning = fmt.Sprintf("Index of %s is %d commits behind GitHub HEAD. Consider resyncing.", cfg.Name, behind)
+			}
+		}
+		// If GitHub API fails, leave CommitsBehind as nil (not an error for the tool)
+	}
+
+	return paths, status, indexedAt, nil
+}
+
+// autoSyncStatus converts the scheduler's state for get_index_status, or returns nil
+// when neither polling nor webhooks are enabled.
+func autoSyncStatus(sched *scheduler.Scheduler) *AutoSyncStatus {
+	if sched == nil {
+		return nil
+	}
+	status := sched.Status()
+	output := &AutoSyncStatus{
+		Running:   status.Running,
+		LastCheck: optionalTime(status.LastCheck),
+		NextCheck: optionalTime(status.NextCheck),
+	}
+	if status.Interval > 0 {
+		output.Interval = status.Interval.String()
+	}
+	if run := status.LastRun; run != nil {
+		output.LastRun = &SyncRun{
+			StartedAt:  run.StartedAt,
+			FinishedAt: run.FinishedAt,
+			Outcome:    run.Outcome,
+			Commit:     run.Commit,
+			Error:      run.Error,
+			Indexed:    run.Indexed,
+			Deleted:    run.Deleted,
+			Failed:     run.Failed,
+		}
+	}
+	return output
+}
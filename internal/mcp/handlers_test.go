@@ -0,0 +1,434 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mike-a-ellis/eino-docs-mcp/internal/embedding"
+	ghclient "github.com/mike-a-ellis/eino-docs-mcp/internal/github"
+	"github.com/mike-a-ellis/eino-docs-mcp/internal/github/githubtest"
+	"github.com/mike-a-ellis/eino-docs-mcp/internal/indexer"
+	"github.com/mike-a-ellis/eino-docs-mcp/internal/markdown"
+	"github.com/mike-a-ellis/eino-docs-mcp/internal/metadata"
+	"github.com/mike-a-ellis/eino-docs-mcp/internal/rerank"
+	"github.com/mike-a-ellis/eino-docs-mcp/internal/scheduler"
+	"github.com/mike-a-ellis/eino-docs-mcp/internal/search"
+	"github.com/mike-a-ellis/eino-docs-mcp/internal/source"
+	"github.com/mike-a-ellis/eino-docs-mcp/internal/storage"
+)
+
+// testEnv is an index built offline from a fake GitHub repository.
+type testEnv struct {
+	gh       *githubtest.Server
+	store    storage.Store
+	embedder embedding.Embedder
+	searcher *search.Searcher
+	sources  sourceSet
+}
+
+// newTestEnv indexes a small docs tree with the hashing embedder and stub metadata,
+// then returns the pieces the handlers need. No network access is required.
+func newTestEnv(t *testing.T) *testEnv {
+	t.Helper()
+	ctx := context.Background()
+
+	gh := githubtest.NewServer(t, source.DefaultConfig.Owner, source.DefaultConfig.Repo)
+	gh.SetFile(source.DefaultConfig.BasePath+"/overview.md", "# Overview\n\nEino is a framework for building LLM applications in Go.\n")
+	gh.SetFile(source.DefaultConfig.BasePath+"/core/graph.md", "---\ntitle: Graph Orchestration\nweight: 2\naliases:\n  - /docs/eino/old-graph/\n---\n\n"+
+		"# Graph\n\nUse compose.NewGraph to orchestrate nodes. See the [overview](/docs/eino/overview/).\n\n## Compile\n\nCall Compile before Invoke.\n\n### Options\n\nPass compose.WithGraphName to name the graph.\n")
+
+	embedder := embedding.NewHashEmbedder(64)
+	store, err := storage.NewEmbeddedStorage(filepath.Join(t.TempDir(), "index.idx"))
+	require.NoError(t, err)
+	require.NoError(t, store.EnsureCollection(ctx, storage.EmbeddingSpec{
+		Model:     embedder.Model(),
+		Dimension: embedder.Dimension(),
+	}))
+
+	generator := &metadata.StubGenerator{Responses: map[string]metadata.DocumentMetadata{
+		"core/graph.md": {Summary: "Graph orchestration", Entities: []string{"compose.NewGraph"}},
+	}}
+	fetcher := ghclient.NewFetcher(gh.Client(), source.DefaultConfig)
+	pipeline := indexer.NewPipeline(fetcher, markdown.NewChunker(), embedder, generator, store, slog.Default())
+
+	result, err := pipeline.IndexAll(ctx)
+	require.NoError(t, err)
+	require.Empty(t, result.FailedDocs)
+
+	return &testEnv{
+		gh:       gh,
+		store:    store,
+		embedder: embedder,
+		searcher: search.NewSearcher(store, embedder),
+		sources:  newSourceSet(nil),
+	}
+}
+
+func TestSearchHandler(t *testing.T) {
+	env := newTestEnv(t)
+	handler := makeSearchHandler(env.store, env.searcher, env.sources)
+
+	_, output, err := handler(context.Background(), nil, SearchDocsInput{Query: "compose.NewGraph"})
+	require.NoError(t, err)
+	require.NotEmpty(t, output.Results)
+	assert.Equal(t, "core/graph.md", output.Results[0].Path)
+	assert.Equal(t, "Graph orchestration", output.Results[0].Summary)
+	assert.Equal(t, []string{"compose.NewGraph"}, output.Results[0].Entities)
+	assert.Equal(t, "Graph Orchestration", output.Results[0].Title)
+
+	_, _, err = handler(context.Background(), nil, SearchDocsInput{Query: "graph", Mode: "fuzzy"})
+	assert.Error(t, err, "unknown modes are rejected")
+}
+
+func TestSearchHandler_Filters(t *testing.T) {
+	env := newTestEnv(t)
+	handler := makeSearchHandler(env.store, env.searcher, env.sources)
+	ctx := context.Background()
+
+	paths := func(input SearchDocsInput) []string {
+		input.Query = "Eino graph framework"
+		_, output, err := handler(ctx, nil, input)
+		require.NoError(t, err)
+		var paths []string
+		for _, r := range output.Results {
+			paths = append(paths, r.Path)
+		}
+		return paths
+	}
+
+	assert.Equal(t, []string{"core/graph.md"}, paths(SearchDocsInput{Categories: []string{"core"}}))
+	assert.Equal(t, []string{"core/graph.md"}, paths(SearchDocsInput{PathPrefix: "core/"}))
+	assert.Equal(t, []string{"core/graph.md"}, paths(SearchDocsInput{Entities: []string{"compose.NewGraph"}}))
+	assert.Empty(t, paths(SearchDocsInput{Categories: []string{"quick_start"}}))
+}
+
+func TestSearchHandler_Rerank(t *testing.T) {
+	env := newTestEnv(t)
+
+	// A local rerank endpoint that prefers the overview page
+	var candidates int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Documents []string `json:"documents"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		candidates = len(req.Documents)
+
+		type result struct {
+			Index          int     `json:"index"`
+			RelevanceScore float64 `json:"relevance_score"`
+		}
+		results := make([]result, len(req.Documents))
+		for i, document := range req.Documents {
+			results[i] = result{Index: i, RelevanceScore: 0.1}
+			if strings.Contains(document, "framework for building LLM applications") {
+				results[i].RelevanceScore = 0.95
+			}
+		}
+		assert.NoError(t, json.NewEncoder(w).Encode(map[string]any{"results": results}))
+	}))
+	defer server.Close()
+
+	searcher := env.searcher.WithReranker(rerank.NewHTTPReranker(server.URL, "", "", ""))
+	handler := makeSearchHandler(env.store, searcher, env.sources)
+
+	_, output, err := handler(context.Background(), nil, SearchDocsInput{
+		Query: "compose.NewGraph framework", Mode: "sparse", MMRLambda: 1, Rerank: true, RerankCandidates: 10,
+	})
+	require.NoError(t, err)
+	require.Len(t, output.Results, 2)
+	assert.Equal(t, "overview.md", output.Results[0].Path)
+	assert.Equal(t, 0.95, output.Results[0].Score)
+	require.NotNil(t, output.Results[0].OriginalScore)
+	assert.Less(t, *output.Results[0].OriginalScore, 1.0)
+	assert.GreaterOrEqual(t, candidates, 2, "every retrieved chunk is a candidate")
+	assert.Empty(t, output.Message)
+
+	// Without a reranker the flag is reported and ignored
+	_, output, err = makeSearchHandler(env.store, env.searcher, env.sources)(context.Background(), nil, SearchDocsInput{
+		Query: "compose.NewGraph", Rerank: true,
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, output.Results)
+	assert.Nil(t, output.Results[0].OriginalScore)
+	assert.Contains(t, output.Message, "not configured")
+}
+
+// countingEmbedder counts the texts it embeds.
+type countingEmbedder struct {
+	embedding.Embedder
+	texts int
+}
+
+func (e *countingEmbedder) GenerateEmbeddings(ctx context.Context, texts []string) ([][]float32, error) {
+	e.texts += len(texts)
+	return e.Embedder.GenerateEmbeddings(ctx, texts)
+}
+
+func TestSearchDocuments_OverFetchesForDistinctDocuments(t *testing.T) {
+	ctx := context.Background()
+	embedder := &countingEmbedder{Embedder: embedding.NewHashEmbedder(64)}
+	store, err := storage.NewEmbeddedStorage(filepath.Join(t.TempDir(), "index.idx"))
+	require.NoError(t, err)
+	require.NoError(t, store.EnsureCollection(ctx, storage.EmbeddingSpec{Model: embedder.Model(), Dimension: embedder.Dimension()}))
+
+	vectors, err := embedder.GenerateEmbeddings(ctx, []string{"callbacks"})
+	require.NoError(t, err)
+
+	// Ten identical chunks of one page outrank (by ID on ties) the only chunk of another
+	var chunks []*storage.Chunk
+	for _, doc := range []struct {
+		id, path string
+		chunks   int
+	}{{"doc-a", "callbacks.md", 10}, {"doc-b", "handlers.md", 1}} {
+		require.NoError(t, store.UpsertDocument(ctx, &storage.Document{
+			ID:       doc.id,
+			Metadata: storage.DocumentMetadata{Path: doc.path, Repository: source.DefaultConfig.Repository(), Source: source.DefaultConfig.Name},
+		}))
+		for i := range doc.chunks {
+			chunks = append(chunks, &storage.Chunk{
+				ID:          fmt.Sprintf("%s-%02d", doc.id, i),
+				ParentDocID: doc.id,
+				ChunkIndex:  i,
+				Path:        doc.path,
+				Repository:  source.DefaultConfig.Repository(),
+				Source:      source.DefaultConfig.Name,
+				Embedding:   vectors[0],
+			})
+		}
+	}
+	require.NoError(t, store.UpsertChunks(ctx, chunks))
+
+	// Without MMR the first 6 chunks all belong to callbacks.md
+	embedder.texts = 0
+	ranked, err := searchDocuments(ctx, store, search.NewSearcher(store, embedder), "callbacks", 2, search.Options{
+		Mode: search.ModeDense,
+	})
+	require.NoError(t, err)
+	require.Len(t, ranked, 2)
+	assert.Equal(t, "callbacks.md", ranked[0].doc.Metadata.Path)
+	assert.Equal(t, "handlers.md", ranked[1].doc.Metadata.Path)
+	assert.Equal(t, 1, embedder.texts, "the query is embedded once across over-fetches")
+}
+
+func TestSearchChunksHandler(t *testing.T) {
+	env := newTestEnv(t)
+	handler := makeSearchChunksHandler(env.searcher, env.sources)
+
+	_, output, err := handler(context.Background(), nil, SearchChunksInput{Query: "Compile Invoke", Mode: "sparse"})
+	require.NoError(t, err)
+	require.NotEmpty(t, output.Chunks)
+	assert.Equal(t, "core/graph.md", output.Chunks[0].Path)
+	assert.Equal(t, "# Graph > ## Compile", output.Chunks[0].HeaderPath)
+	assert.Contains(t, output.Chunks[0].Content, "Call Compile before Invoke.")
+
+	// A tiny budget shortens the single best passage
+	_, output, err = handler(context.Background(), nil, SearchChunksInput{Query: "Compile Invoke", Mode: "sparse", MaxTokens: 2})
+	require.NoError(t, err)
+	require.Len(t, output.Chunks, 1)
+	assert.True(t, output.Truncated)
+	assert.Equal(t, 8, output.TotalChars)
+}
+
+func TestFetchHandler(t *testing.T) {
+	env := newTestEnv(t)
+	handler := makeFetchHandler(env.store, env.searcher, env.sources)
+
+	_, output, err := handler(context.Background(), nil, FetchDocInput{Path: "core/graph.md"})
+	require.NoError(t, err)
+	assert.True(t, output.Found)
+	assert.Equal(t, "Graph orchestration", output.Summary)
+	assert.Equal(t, "Graph Orchestration", output.Title)
+	assert.Equal(t, 2, output.Weight)
+	assert.Nil(t, output.Date)
+	assert.Contains(t, output.Content, "<!-- Source: core/graph.md -->")
+	assert.Contains(t, output.Content, "compose.NewGraph")
+	assert.Equal(t, env.gh.HeadSHA(), output.CommitSHA)
+	assert.Equal(t, "https://github.com/cloudwego/cloudwego.github.io/blob/"+env.gh.HeadSHA()+"/content/en/docs/eino/core/graph.md", output.SourceURL)
+	assert.Equal(t, "https://www.cloudwego.io/docs/eino/core/graph/", output.PageURL)
+
+	// Hugo aliases resolve to the same document
+	_, output, err = handler(context.Background(), nil, FetchDocInput{Path: "/docs/eino/old-graph/"})
+	require.NoError(t, err)
+	assert.True(t, output.Found)
+	assert.Equal(t, "core/graph.md", output.Path)
+	assert.Equal(t, []string{"docs/eino/old-graph"}, output.Aliases)
+
+	_, output, err = handler(context.Background(), nil, FetchDocInput{Path: "missing.md"})
+	require.NoError(t, err)
+	assert.False(t, output.Found)
+
+	// Near misses suggest the closest paths
+	_, output, err = handler(context.Background(), nil, FetchDocInput{Path: "/docs/eino/core/graphs/"})
+	require.NoError(t, err)
+	assert.False(t, output.Found)
+	require.NotEmpty(t, output.Suggestions)
+	assert.Equal(t, "core/graph.md", output.Suggestions[0].Path)
+	assert.Equal(t, "similar_path", output.Suggestions[0].Reason)
+	assert.Contains(t, output.Message, "Did you mean core/graph.md?")
+
+	_, output, err = handler(context.Background(), nil, FetchDocInput{Path: "old-graph.md"})
+	require.NoError(t, err)
+	require.NotEmpty(t, output.Suggestions)
+	assert.Equal(t, "core/graph.md", output.Suggestions[0].Path)
+	assert.Equal(t, "alias", output.Suggestions[0].Reason)
+}
+
+func TestListHandler(t *testing.T) {
+	env := newTestEnv(t)
+	handler := makeListHandler(env.store, env.sources)
+
+	_, output, err := handler(context.Background(), nil, ListDocsInput{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"core/graph.md", "overview.md"}, output.Paths)
+	assert.Equal(t, 2, output.Count)
+	assert.Equal(t, []string{"core"}, output.Categories)
+}
+
+func TestStatusHandler(t *testing.T) {
+	env := newTestEnv(t)
+	indexedSHA := env.gh.HeadSHA()
+	env.gh.Commit("0000000000000000000000000000000000000002")
+	env.gh.Commit("0000000000000000000000000000000000000003")
+
+	handler := makeStatusHandler(env.store, env.gh.Client(), nil, env.sources)
+	_, output, err := handler(context.Background(), nil, StatusInput{})
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, output.TotalDocs)
+	assert.Equal(t, 3, output.TotalChunks)
+	assert.Equal(t, indexedSHA, output.SourceCommit)
+	assert.NotEmpty(t, output.LastSyncTime)
+	require.NotNil(t, output.CommitsBehind)
+	assert.Equal(t, 2, *output.CommitsBehind)
+	assert.Empty(t, output.StaleWarning)
+	assert.Nil(t, output.AutoSync, "omitted without a scheduler")
+}
+
+func TestStatusHandler_AutoSync(t *testing.T) {
+	env := newTestEnv(t)
+	ctx := context.Background()
+	env.gh.SetFile(source.DefaultConfig.BasePath+"/core/agent.md", "# Agent\n\nReAct agents call tools.\n")
+	env.gh.Commit("0000000000000000000000000000000000000002")
+
+	fetcher := ghclient.NewFetcher(env.gh.Client(), source.DefaultConfig)
+	pipeline := indexer.NewPipeline(fetcher, markdown.NewChunker(), env.embedder, &metadata.StubGenerator{}, env.store, slog.Default())
+	sched := scheduler.New(indexer.NewPipelines(pipeline), time.Hour, slog.Default())
+	handler := makeStatusHandler(env.store, env.gh.Client(), sched, env.sources)
+
+	_, output, err := handler(ctx, nil, StatusInput{})
+	require.NoError(t, err)
+	require.NotNil(t, output.AutoSync)
+	assert.Equal(t, "1h0m0s", output.AutoSync.Interval)
+	assert.Nil(t, output.AutoSync.LastRun)
+
+	_, err = sched.SyncNow(ctx)
+	require.NoError(t, err)
+
+	_, output, err = handler(ctx, nil, StatusInput{})
+	require.NoError(t, err)
+	assert.Equal(t, 3, output.TotalDocs)
+	assert.Equal(t, "0000000000000000000000000000000000000002", output.SourceCommit)
+	require.NotNil(t, output.AutoSync.LastRun)
+	assert.Equal(t, "success", output.AutoSync.LastRun.Outcome)
+	assert.Equal(t, 1, output.AutoSync.LastRun.Indexed)
+	assert.False(t, output.AutoSync.Running)
+}
+
+// addSource indexes a second documentation source into the test index and serves it.
+func (env *testEnv) addSource(t *testing.T, cfg source.Config, files map[string]string) *githubtest.Server {
+	t.Helper()
+	gh := githubtest.NewServer(t, cfg.Owner, cfg.Repo)
+	for path, content := range files {
+		gh.SetFile(cfg.BasePath+"/"+path, content)
+	}
+	pipeline := indexer.NewPipeline(ghclient.NewFetcher(gh.Client(), cfg), markdown.NewChunker(), env.embedder,
+		&metadata.StubGenerator{}, env.store, slog.Default()).WithConfig(cfg)
+	result, err := pipeline.IndexAll(context.Background())
+	require.NoError(t, err)
+	require.Empty(t, result.FailedDocs)
+
+	env.sources = append(env.sources, cfg)
+	return gh
+}
+
+func TestHandlers_Sources(t *testing.T) {
+	env := newTestEnv(t)
+	ext := source.Config{Name: "eino-ext", Owner: "example", Repo: "eino-ext-docs", Ref: "main", BasePath: "docs"}
+	extGitHub := env.addSource(t, ext, map[string]string{
+		"overview.md":           "# Extensions\n\nInternal Eino extensions for retrieval.\n",
+		"retriever/vikingdb.md": "# VikingDB Retriever\n\nRetrieve documents from VikingDB with the extension retriever.\n",
+	})
+	ctx := context.Background()
+
+	_, list, err := makeListHandler(env.store, env.sources)(ctx, nil, ListDocsInput{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"core/graph.md", "overview.md", "retriever/vikingdb.md"}, list.Paths)
+	assert.Equal(t, []SourceSummary{
+		{Name: "eino", Repository: "cloudwego/cloudwego.github.io", Count: 2},
+		{Name: "eino-ext", Repository: "example/eino-ext-docs", Count: 2},
+	}, list.Sources)
+
+	_, list, err = makeListHandler(env.store, env.sources)(ctx, nil, ListDocsInput{Source: "eino-ext"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"overview.md", "retriever/vikingdb.md"}, list.Paths)
+
+	// Searches span every source unless one is selected
+	search := makeSearchHandler(env.store, env.searcher, env.sources)
+	_, found, err := search(ctx, nil, SearchDocsInput{Query: "Eino", Mode: "sparse", ExcludeSymbols: true})
+	require.NoError(t, err)
+	sources := map[string]bool{}
+	for _, r := range found.Results {
+		sources[r.Source] = true
+	}
+	assert.Equal(t, map[string]bool{"eino": true, "eino-ext": true}, sources)
+
+	_, found, err = search(ctx, nil, SearchDocsInput{Query: "Eino", Mode: "sparse", Source: "eino-ext"})
+	require.NoError(t, err)
+	require.NotEmpty(t, found.Results)
+	for _, r := range found.Results {
+		assert.Equal(t, "eino-ext", r.Source)
+	}
+
+	_, _, err = search(ctx, nil, SearchDocsInput{Query: "Eino", Source: "missing"})
+	assert.ErrorContains(t, err, "available: eino, eino-ext")
+
+	// A path in several sources resolves to the first unless one is selected
+	fetch := makeFetchHandler(env.store, env.searcher, env.sources)
+	_, doc, err := fetch(ctx, nil, FetchDocInput{Path: "overview.md"})
+	require.NoError(t, err)
+	assert.Equal(t, "eino", doc.Source)
+	assert.Contains(t, doc.Content, "framework for building LLM applications")
+
+	_, doc, err = fetch(ctx, nil, FetchDocInput{Path: "overview.md", Source: "eino-ext"})
+	require.NoError(t, err)
+	assert.Equal(t, "eino-ext", doc.Source)
+	assert.Contains(t, doc.Content, "Internal Eino extensions")
+	assert.Empty(t, doc.PageURL, "only the Eino User Manual is published on cloudwego.io")
+
+	_, doc, err = fetch(ctx, nil, FetchDocInput{Path: "core/graph.md", Source: "eino-ext"})
+	require.NoError(t, err)
+	assert.False(t, doc.Found)
+
+	// Each source is compared with its own repository
+	extGitHub.Commit("0000000000000000000000000000000000000002")
+	_, status, err := makeStatusHandler(env.store, env.gh.Client(), nil, env.sources)(ctx, nil, StatusInput{})
+	require.NoError(t, err)
+	assert.Equal(t, 4, status.TotalDocs)
+	require.Len(t, status.Sources, 2)
+	assert.Equal(t, env.gh.HeadSHA(), status.SourceCommit)
+	assert.Equal(t, "eino-ext", status.Sources[1].Name)
+	assert.Equal(t, 2, status.Sources[1].TotalDocs)
+	assert.NotEmpty(t, status.Sources[1].SourceCommit)
+}
@@ -0,0 +1,87 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mike-a-ellis/eino-docs-mcp/internal/markdown"
+	"github.com/mike-a-ellis/eino-docs-mcp/internal/prompts"
+	"github.com/mike-a-ellis/eino-docs-mcp/internal/search"
+	"github.com/mike-a-ellis/eino-docs-mcp/internal/storage"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// promptMinScore is the dense similarity threshold for documents pulled into prompts,
+// matching the search_docs default.
+const promptMinScore = 0.3
+
+// addPrompts registers each prompt template with the server.
+func addPrompts(server *mcp.Server, store storage.Store, searcher *search.Searcher, library []*prompts.Prompt) {
+	for _, p := range library {
+		arguments := make([]*mcp.PromptArgument, len(p.Arguments))
+		for i, arg := range p.Arguments {
+			arguments[i] = &mcp.PromptArgument{
+				Name:        arg.Name,
+				Description: arg.Description,
+				Required:    arg.Required,
+			}
+		}
+		server.AddPrompt(&mcp.Prompt{
+			Name:        p.Name,
+			Title:       p.Title,
+			Description: p.Description,
+			Arguments:   arguments,
+		}, makePromptHandler(store, searcher, p))
+	}
+}
+
+// makePromptHandler creates the prompts/get handler for a template.
+// The template's search query is run through the same retrieval as search_docs
+// (hybrid mode), and the matching documents are rendered into the prompt.
+func makePromptHandler(store storage.Store, searcher *search.Searcher, p *prompts.Prompt) mcp.PromptHandler {
+	return func(ctx context.Context, req *mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		args, err := p.Args(req.Params.Arguments)
+		if err != nil {
+			return nil, err
+		}
+
+		query, err := p.Query(args)
+		if err != nil {
+			return nil, err
+		}
+
+		var docs []prompts.Doc
+		if query != "" {
+			ranked, err := searchDocuments(ctx, store, searcher, query, p.MaxDocs, search.Options{
+				Mode:     search.DefaultMode,
+				MinScore: promptMinScore,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to retrieve documents: %w", err)
+			}
+			for _, r := range ranked {
+				_, body, _ := markdown.ParseFrontMatter([]byte(r.doc.Content))
+				docs = append(docs, prompts.Doc{
+					Path:     r.doc.Metadata.Path,
+					Title:    r.doc.Metadata.Title,
+					Summary:  r.doc.Metadata.Summary,
+					Entities: r.doc.Metadata.Entities,
+					Content:  string(body),
+				})
+			}
+		}
+
+		text, err := p.Render(args, docs)
+		if err != nil {
+			return nil, err
+		}
+
+		return &mcp.GetPromptResult{
+			Description: p.Description,
+			Messages: []*mcp.PromptMessage{{
+				Role:    "user",
+				Content: &mcp.TextContent{Text: text},
+			}},
+		}, nil
+	}
+}
@@ -0,0 +1,42 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mike-a-ellis/eino-docs-mcp/internal/prompts"
+)
+
+func TestPrompts_GetRetrievesDocs(t *testing.T) {
+	env := newTestEnv(t)
+	library, err := prompts.Load("")
+	require.NoError(t, err)
+
+	server := newServer(t, &Config{Storage: env.store, Embedder: env.embedder, Prompts: library})
+	session := connectClient(t, server, nil)
+	ctx := context.Background()
+
+	list, err := session.ListPrompts(ctx, nil)
+	require.NoError(t, err)
+	require.Len(t, list.Prompts, len(library))
+
+	result, err := session.GetPrompt(ctx, &mcp.GetPromptParams{
+		Name:      "explain-graph-error",
+		Arguments: map[string]string{"error": "graph must be compiled before Invoke"},
+	})
+	require.NoError(t, err)
+	require.Len(t, result.Messages, 1)
+
+	text := result.Messages[0].Content.(*mcp.TextContent).Text
+	assert.Contains(t, text, "graph must be compiled before Invoke")
+	assert.Contains(t, text, "Source: core/graph.md")
+	assert.Contains(t, text, "Call Compile before Invoke.")
+	assert.NotContains(t, text, "aliases:", "front matter is stripped from documents")
+
+	_, err = session.GetPrompt(ctx, &mcp.GetPromptParams{Name: "explain-graph-error"})
+	assert.Error(t, err, "required arguments are enforced")
+}
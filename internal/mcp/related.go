@@ -0,0 +1,215 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/mike-a-ellis/eino-docs-mcp/internal/search"
+	"github.com/mike-a-ellis/eino-docs-mcp/internal/storage"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// Related document reasons reported in RelatedDoc.Reason.
+const (
+	reasonLinksTo    = "links-to"
+	reasonLinkedFrom = "linked-from"
+)
+
+const (
+	// relatedMinScore drops semantic neighbours that are only loosely similar.
+	relatedMinScore = 0.3
+	// linkBoost is added to a neighbour's score for each direction it is linked in;
+	// an explicit link is stronger evidence of relatedness than vector similarity.
+	linkBoost = 0.25
+)
+
+// makeRelatedHandler creates the related_docs tool handler.
+// Related flow:
+// 1. Average the document's chunk vectors into a centroid
+// 2. Search chunks nearest the centroid, keeping the best score per other document
+// 3. Resolve the document's outgoing links and the links pointing at it, within its source
+// 4. Boost linked documents, rank, and return the top MaxResults with a reason
+func makeRelatedHandler(store storage.Store, searcher *search.Searcher, sources sourceSet) func(
+	context.Context, *mcp.CallToolRequest, RelatedDocsInput,
+) (*mcp.CallToolResult, RelatedDocsOutput, error) {
+	return func(ctx context.Context, req *mcp.CallToolRequest, input RelatedDocsInput) (
+		*mcp.CallToolResult, RelatedDocsOutput, error,
+	) {
+		// Apply defaults
+		maxResults := input.MaxResults
+		if maxResults <= 0 {
+			maxResults = 5
+		}
+		if maxResults > 20 {
+			maxResults = 20
+		}
+
+		selected, err := sources.selected(input.Source)
+		if err != nil {
+			return nil, RelatedDocsOutput{}, err
+		}
+
+		doc, err := findDocument(ctx, store, selected, input.Path)
+		if errors.Is(err, storage.ErrDocumentNotFound) {
+			suggestions, err := suggestPaths(ctx, store, searcher, selected, input.Path)
+			if err != nil {
+				return nil, RelatedDocsOutput{}, fmt.Errorf("failed to suggest paths: %w", err)
+			}
+			return nil, RelatedDocsOutput{
+				Path:        input.Path,
+				Related:     []RelatedDoc{},
+				Suggestions: suggestions,
+				Message:     "Document not found. Use list_docs or search_docs to find available paths.",
+			}, nil
+		}
+		if err != nil {
+			return nil, RelatedDocsOutput{}, fmt.Errorf("failed to fetch document: %w", err)
+		}
+		origin := documentKey{source: doc.Metadata.Source, path: doc.Metadata.Path}
+
+		type neighbour struct {
+			semantic   float64
+			linksTo    bool
+			linkedFrom bool
+		}
+		neighbours := make(map[documentKey]*neighbour)
+		get := func(key documentKey) *neighbour {
+			if neighbours[key] == nil {
+				neighbours[key] = &neighbour{}
+			}
+			return neighbours[key]
+		}
+
+		// Semantic neighbours of the centroid, from any selected source
+		vectors, err := store.GetDocumentEmbeddings(ctx, origin.path, origin.source)
+		if err != nil {
+			return nil, RelatedDocsOutput{}, fmt.Errorf("failed to load document vectors: %w", err)
+		}
+		if centroid := centroidOf(vectors); centroid != nil {
+			// The document's own chunks are the nearest, so request past them
+			chunks, err := store.SearchChunksWithScores(ctx, centroid, maxResults*3+len(vectors), storage.SearchFilter{
+				Source: input.Source,
+			})
+			if err != nil {
+				return nil, RelatedDocsOutput{}, fmt.Errorf("search failed: %w", err)
+			}
+			for _, chunk := range chunks {
+				key := documentKey{source: chunk.Source, path: chunk.Path}
+				if key == origin || chunk.Score < relatedMinScore {
+					continue
+				}
+				if n := get(key); chunk.Score > n.semantic {
+					n.semantic = chunk.Score
+				}
+			}
+		}
+
+		// Explicit links in both directions; links stay within a source
+		resolve, err := linkResolver(ctx, store, origin.source)
+		if err != nil {
+			return nil, RelatedDocsOutput{}, err
+		}
+		for _, link := range doc.Metadata.Links {
+			if target, ok := resolve[normalizePath(link)]; ok && target != origin.path {
+				get(documentKey{source: origin.source, path: target}).linksTo = true
+			}
+		}
+		links, err := store.ListLinks(ctx, origin.source)
+		if err != nil {
+			return nil, RelatedDocsOutput{}, fmt.Errorf("failed to list links: %w", err)
+		}
+		for from, targets := range links {
+			if from == origin.path {
+				continue
+			}
+			for _, link := range targets {
+				if resolve[normalizePath(link)] == origin.path {
+					get(documentKey{source: origin.source, path: from}).linkedFrom = true
+					break
+				}
+			}
+		}
+
+		related := make([]RelatedDoc, 0, len(neighbours))
+		for key, n := range neighbours {
+			score := n.semantic
+			reason := reasonSemantic
+			if n.linkedFrom {
+				score += linkBoost
+				reason = reasonLinkedFrom
+			}
+			if n.linksTo {
+				score += linkBoost
+				reason = reasonLinksTo
+			}
+			related = append(related, RelatedDoc{Path: key.path, Source: key.source, Score: min(score, 1), Reason: reason})
+		}
+		sort.Slice(related, func(i, j int) bool {
+			if related[i].Score != related[j].Score {
+				return related[i].Score > related[j].Score
+			}
+			if related[i].Path != related[j].Path {
+				return related[i].Path < related[j].Path
+			}
+			return related[i].Source < related[j].Source
+		})
+		if len(related) > maxResults {
+			related = related[:maxResults]
+		}
+
+		// Fill in titles and summaries for the returned documents
+		for i := range related {
+			if neighbourDoc, err := store.GetDocumentByPath(ctx, related[i].Path, related[i].Source); err == nil {
+				related[i].Title = neighbourDoc.Metadata.Title
+				related[i].Summary = neighbourDoc.Metadata.Summary
+			}
+		}
+
+		output := RelatedDocsOutput{Path: origin.path, Source: origin.source, Found: true, Related: related}
+		if len(related) == 0 {
+			output.Message = "No related documents found."
+		}
+		return nil, output, nil
+	}
+}
+
+// linkResolver maps normalized paths and aliases to the indexed document path of a
+// source, so link targets in either form ("docs/eino/core/graph", "core/graph.md") resolve.
+func linkResolver(ctx context.Context, store storage.Store, source string) (map[string]string, error) {
+	paths, err := store.ListDocumentPaths(ctx, source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list documents: %w", err)
+	}
+	aliases, err := store.ListAliases(ctx, source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list aliases: %w", err)
+	}
+
+	resolve := make(map[string]string, len(paths)+len(aliases))
+	for alias, path := range aliases {
+		resolve[normalizePath(alias)] = path
+	}
+	for _, path := range paths {
+		resolve[normalizePath(path)] = path // Real paths win over aliases
+	}
+	return resolve, nil
+}
+
+// centroidOf returns the mean of vectors, or nil if there are none.
+func centroidOf(vectors [][]float32) []float32 {
+	if len(vectors) == 0 {
+		return nil
+	}
+	centroid := make([]float32, len(vectors[0]))
+	for _, vector := range vectors {
+		for i := range min(len(vector), len(centroid)) {
+			centroid[i] += vector[i]
+		}
+	}
+	for i := range centroid {
+		centroid[i] /= float32(len(vectors))
+	}
+	return centroid
+}
@@ -0,0 +1,45 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRelatedHandler(t *testing.T) {
+	env := newTestEnv(t)
+	handler := makeRelatedHandler(env.store, env.searcher, env.sources)
+	ctx := context.Background()
+
+	// graph.md links to the overview
+	_, output, err := handler(ctx, nil, RelatedDocsInput{Path: "core/graph.md"})
+	require.NoError(t, err)
+	assert.True(t, output.Found)
+	require.NotEmpty(t, output.Related)
+	assert.Equal(t, "overview.md", output.Related[0].Path)
+	assert.Equal(t, "links-to", output.Related[0].Reason)
+	assert.GreaterOrEqual(t, output.Related[0].Score, linkBoost)
+
+	// and the overview is linked from graph.md
+	_, output, err = handler(ctx, nil, RelatedDocsInput{Path: "overview.md"})
+	require.NoError(t, err)
+	require.NotEmpty(t, output.Related)
+	assert.Equal(t, "core/graph.md", output.Related[0].Path)
+	assert.Equal(t, "linked-from", output.Related[0].Reason)
+	assert.Equal(t, "Graph Orchestration", output.Related[0].Title)
+	for _, related := range output.Related {
+		assert.NotEqual(t, "overview.md", related.Path, "the source document is excluded")
+	}
+
+	_, output, err = handler(ctx, nil, RelatedDocsInput{Path: "core/graphs.md"})
+	require.NoError(t, err)
+	assert.False(t, output.Found)
+	assert.NotEmpty(t, output.Suggestions)
+}
+
+func TestCentroidOf(t *testing.T) {
+	assert.Nil(t, centroidOf(nil))
+	assert.Equal(t, []float32{0.5, 0.5, 1}, centroidOf([][]float32{{1, 0, 1}, {0, 1, 1}}))
+}
@@ -0,0 +1,209 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mike-a-ellis/eino-docs-mcp/internal/source"
+	"github.com/mike-a-ellis/eino-docs-mcp/internal/storage"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// resourceURIScheme starts every resource URI; the source name and document path
+// follow it (see DocumentURI).
+const resourceURIScheme = "eino-docs://"
+
+// resourceURITemplate is the URI template of the document resources.
+const resourceURITemplate = resourceURIScheme + "{source}/{+path}"
+
+// legacyResourceURIPrefix starts the document URIs from before sources were named,
+// which held the Eino docs' repository path. They still resolve, to the default source.
+const legacyResourceURIPrefix = resourceURIScheme + "content/en/docs/eino/"
+
+// resourceMIMEType is the MIME type of every document resource.
+const resourceMIMEType = "text/markdown"
+
+// resourcePageSize is the number of resources returned per resources/list page.
+const resourcePageSize = 100
+
+// DefaultResourcePollInterval is how often the index is checked for documents that a
+// sync added, changed or removed.
+const DefaultResourcePollInterval = time.Minute
+
+// DocumentURI returns the resource URI of a document path in a source, e.g.
+// "eino-docs://eino/core/graph.md".
+func DocumentURI(source, path string) string {
+	return resourceURIScheme + source + "/" + path
+}
+
+// documentPath extracts the source and document path from a resource URI.
+func documentPath(uri string) (documentKey, bool) {
+	if path, ok := strings.CutPrefix(uri, legacyResourceURIPrefix); ok {
+		return documentKey{source: source.DefaultConfig.Name, path: path}, path != ""
+	}
+	rest, ok := strings.CutPrefix(uri, resourceURIScheme)
+	name, path, found := strings.Cut(rest, "/")
+	return documentKey{source: name, path: path}, ok && found && name != "" && path != ""
+}
+
+// makeResourceHandler creates the resources/read handler shared by the per-document
+// resources and the path template.
+func makeResourceHandler(store storage.Store) mcp.ResourceHandler {
+	return func(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+		uri := req.Params.URI
+		key, ok := documentPath(uri)
+		if !ok {
+			return nil, mcp.ResourceNotFoundError(uri)
+		}
+
+		doc, err := store.GetDocumentByPath(ctx, key.path, key.source)
+		if err != nil {
+			if errors.Is(err, storage.ErrDocumentNotFound) {
+				return nil, mcp.ResourceNotFoundError(uri)
+			}
+			return nil, fmt.Errorf("failed to read document: %w", err)
+		}
+
+		return &mcp.ReadResourceResult{
+			Contents: []*mcp.ResourceContents{{
+				URI:      uri,
+				MIMEType: resourceMIMEType,
+				Text:     doc.Content,
+			}},
+		}, nil
+	}
+}
+
+// resourceSet keeps the server's registered resources in step with the index.
+// Each refresh compares blob SHAs with the previous one: new documents are
+// registered, removed ones unregistered (both announce list_changed), and changed
+// ones trigger resources/updated for subscribed sessions.
+type resourceSet struct {
+	server  *mcp.Server
+	store   storage.Store
+	sources sourceSet
+	handler mcp.ResourceHandler
+
+	mu   sync.Mutex
+	shas map[documentKey]string // Document -> blob SHA at the last refresh; nil before the first
+}
+
+func newResourceSet(server *mcp.Server, store storage.Store, sources sourceSet) *resourceSet {
+	return &resourceSet{
+		server:  server,
+		store:   store,
+		sources: sources,
+		handler: makeResourceHandler(store),
+	}
+}
+
+// refresh registers, unregisters and announces changed documents of every source.
+func (r *resourceSet) refresh(ctx context.Context) error {
+	shas := make(map[documentKey]string)
+	for _, cfg := range r.sources {
+		sourceSHAs, err := r.store.ListDocumentSHAs(ctx, cfg.Name)
+		if err != nil {
+			return fmt.Errorf("failed to list documents: %w", err)
+		}
+		for path, sha := range sourceSHAs {
+			shas[documentKey{source: cfg.Name, path: path}] = sha
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var removed []string
+	for key := range r.shas {
+		if _, ok := shas[key]; !ok {
+			removed = append(removed, DocumentURI(key.source, key.path))
+		}
+	}
+	if len(removed) > 0 {
+		r.server.RemoveResources(removed...)
+	}
+
+	for key, sha := range shas {
+		uri := DocumentURI(key.source, key.path)
+		oldSHA, known := r.shas[key]
+		switch {
+		case !known:
+			r.server.AddResource(&mcp.Resource{
+				URI:      uri,
+				Name:     key.path,
+				MIMEType: resourceMIMEType,
+			}, r.handler)
+		case oldSHA != sha:
+			// Subscribers may still hold a default-source document's legacy URI
+			uris := []string{uri}
+			if key.source == source.DefaultConfig.Name {
+				uris = append(uris, legacyResourceURIPrefix+key.path)
+			}
+			for _, uri := range uris {
+				// A failed notification must not stall the refresh: the registrations
+				// above are already made, and returning would re-announce them next time
+				if err := r.server.ResourceUpdated(ctx, &mcp.ResourceUpdatedNotificationParams{URI: uri}); err != nil {
+					slog.Warn("Failed to notify resource subscribers", "uri", uri, "error", err)
+				}
+			}
+		}
+	}
+
+	r.shas = shas
+	return nil
+}
+
+// subscribe accepts subscriptions to documents that exist in the index.
+func (r *resourceSet) subscribe(ctx context.Context, req *mcp.SubscribeRequest) error {
+	key, ok := documentPath(req.Params.URI)
+	if !ok {
+		return mcp.ResourceNotFoundError(req.Params.URI)
+	}
+	if _, err := r.store.GetDocumentByPath(ctx, key.path, key.source); err != nil {
+		if errors.Is(err, storage.ErrDocumentNotFound) {
+			return mcp.ResourceNotFoundError(req.Params.URI)
+		}
+		return fmt.Errorf("failed to look up document: %w", err)
+	}
+	return nil
+}
+
+// unsubscribe always succeeds; the SDK tracks the subscriptions themselves.
+func (r *resourceSet) unsubscribe(context.Context, *mcp.UnsubscribeRequest) error {
+	return nil
+}
+
+// RefreshResources syncs the published resources with the index once.
+func (s *Server) RefreshResources(ctx context.Context) error {
+	return s.resources.refresh(ctx)
+}
+
+// WatchResources publishes every indexed document as a resource, then polls the index
+// every interval so a sync's changes reach clients: resources/list_changed when
+// documents appear or disappear, resources/updated for subscribers of changed ones.
+// Blocks until ctx is cancelled.
+func (s *Server) WatchResources(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultResourcePollInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.resources.refresh(ctx); err != nil && ctx.Err() == nil {
+			slog.Warn("Failed to refresh document resources", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mike-a-ellis/eino-docs-mcp/internal/source"
+)
+
+// newServer builds a server on the test index.
+func newServer(t *testing.T, cfg *Config) *Server {
+	t.Helper()
+	server := NewServer(cfg)
+	require.NoError(t, server.RefreshResources(context.Background()))
+	return server
+}
+
+// connectClient connects a client to server over in-memory transports.
+func connectClient(t *testing.T, server *Server, opts *mcp.ClientOptions) *mcp.ClientSession {
+	t.Helper()
+	ctx := context.Background()
+
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "v0.0.1"}, opts)
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	serverSession, err := server.MCPServer().Connect(ctx, serverTransport, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { serverSession.Close() })
+
+	session, err := client.Connect(ctx, clientTransport, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { session.Close() })
+
+	return session
+}
+
+// connectTestClient serves the test index and returns a connected client session
+// plus a channel receiving resources/updated URIs.
+func connectTestClient(t *testing.T, env *testEnv) (*Server, *mcp.ClientSession, <-chan string) {
+	t.Helper()
+
+	server := newServer(t, &Config{Storage: env.store, Embedder: env.embedder})
+	updated := make(chan string, 10)
+	session := connectClient(t, server, &mcp.ClientOptions{
+		ResourceUpdatedHandler: func(_ context.Context, req *mcp.ResourceUpdatedNotificationRequest) {
+			updated <- req.Params.URI
+		},
+	})
+	return server, session, updated
+}
+
+func TestResources_ListAndRead(t *testing.T) {
+	env := newTestEnv(t)
+	_, session, _ := connectTestClient(t, env)
+	ctx := context.Background()
+
+	list, err := session.ListResources(ctx, nil)
+	require.NoError(t, err)
+	var uris []string
+	for _, r := range list.Resources {
+		uris = append(uris, r.URI)
+	}
+	assert.ElementsMatch(t, []string{DocumentURI(source.DefaultConfig.Name, "overview.md"), DocumentURI(source.DefaultConfig.Name, "core/graph.md")}, uris)
+
+	templates, err := session.ListResourceTemplates(ctx, nil)
+	require.NoError(t, err)
+	require.Len(t, templates.ResourceTemplates, 1)
+	assert.Equal(t, resourceURITemplate, templates.ResourceTemplates[0].URITemplate)
+
+	read, err := session.ReadResource(ctx, &mcp.ReadResourceParams{URI: DocumentURI(source.DefaultConfig.Name, "core/graph.md")})
+	require.NoError(t, err)
+	require.Len(t, read.Contents, 1)
+	assert.Equal(t, "text/markdown", read.Contents[0].MIMEType)
+	assert.Contains(t, read.Contents[0].Text, "compose.NewGraph")
+
+	_, err = session.ReadResource(ctx, &mcp.ReadResourceParams{URI: DocumentURI(source.DefaultConfig.Name, "missing.md")})
+	assert.Error(t, err)
+}
+
+func TestResources_SubscribeNotifiesOnChange(t *testing.T) {
+	env := newTestEnv(t)
+	server, session, updated := connectTestClient(t, env)
+	ctx := context.Background()
+
+	uri := DocumentURI(source.DefaultConfig.Name, "core/graph.md")
+	require.NoError(t, session.Subscribe(ctx, &mcp.SubscribeParams{URI: uri}))
+	assert.Error(t, session.Subscribe(ctx, &mcp.SubscribeParams{URI: DocumentURI(source.DefaultConfig.Name, "missing.md")}))
+
+	// An unchanged index sends nothing
+	require.NoError(t, server.RefreshResources(ctx))
+
+	// Simulate a sync that changed the document
+	doc, err := env.store.GetDocumentByPath(ctx, "core/graph.md", source.DefaultConfig.Name)
+	require.NoError(t, err)
+	doc.Metadata.BlobSHA = "changed"
+	require.NoError(t, env.store.UpsertDocument(ctx, doc))
+	require.NoError(t, server.RefreshResources(ctx))
+
+	select {
+	case got := <-updated:
+		assert.Equal(t, uri, got)
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected a resources/updated notification")
+	}
+	assert.Empty(t, updated, "only the changed document is announced")
+}
+
+// TestResources_LegacyURI verifies URIs from before sources were named still read,
+// subscribe and receive updates for the default source.
+func TestResources_LegacyURI(t *testing.T) {
+	env := newTestEnv(t)
+	server, session, updated := connectTestClient(t, env)
+	ctx := context.Background()
+
+	uri := "eino-docs://content/en/docs/eino/core/graph.md"
+	read, err := session.ReadResource(ctx, &mcp.ReadResourceParams{URI: uri})
+	require.NoError(t, err)
+	require.Len(t, read.Contents, 1)
+	assert.Equal(t, uri, read.Contents[0].URI)
+	assert.Contains(t, read.Contents[0].Text, "compose.NewGraph")
+
+	_, err = session.ReadResource(ctx, &mcp.ReadResourceParams{URI: "eino-docs://content/en/docs/eino/missing.md"})
+	assert.Error(t, err)
+
+	require.NoError(t, session.Subscribe(ctx, &mcp.SubscribeParams{URI: uri}))
+
+	doc, err := env.store.GetDocumentByPath(ctx, "core/graph.md", source.DefaultConfig.Name)
+	require.NoError(t, err)
+	doc.Metadata.BlobSHA = "changed"
+	require.NoError(t, env.store.UpsertDocument(ctx, doc))
+	require.NoError(t, server.RefreshResources(ctx))
+
+	select {
+	case got := <-updated:
+		assert.Equal(t, uri, got)
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected a resources/updated notification")
+	}
+}
@@ -0,0 +1,243 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/mike-a-ellis/eino-docs-mcp/internal/markdown"
+	"github.com/mike-a-ellis/eino-docs-mcp/internal/search"
+	"github.com/mike-a-ellis/eino-docs-mcp/internal/storage"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// maxSectionContext caps the neighbouring chunks fetch_section returns on each side.
+const maxSectionContext = 5
+
+// makeFetchSectionHandler creates the fetch_section tool handler.
+// Headings are read from the stored markdown (see markdown.Outline) and mapped onto
+// the stored chunks by header path:
+// 1. Resolve the document by path or alias (suggesting paths when missing)
+// 2. Without a section, or with TOC set, return the heading tree
+// 3. Match the section by anchor ID or header path suffix
+// 4. Return the section's markdown, including subsections, plus neighbouring chunks
+func makeFetchSectionHandler(store storage.Store, searcher *search.Searcher, sources sourceSet) func(
+	context.Context, *mcp.CallToolRequest, FetchSectionInput,
+) (*mcp.CallToolResult, FetchSectionOutput, error) {
+	return func(ctx context.Context, req *mcp.CallToolRequest, input FetchSectionInput) (
+		*mcp.CallToolResult, FetchSectionOutput, error,
+	) {
+		selected, err := sources.selected(input.Source)
+		if err != nil {
+			return nil, FetchSectionOutput{}, err
+		}
+
+		doc, err := findDocument(ctx, store, selected, input.Path)
+		if errors.Is(err, storage.ErrDocumentNotFound) {
+			suggestions, err := suggestPaths(ctx, store, searcher, selected, input.Path)
+			if err != nil {
+				return nil, FetchSectionOutput{}, fmt.Errorf("failed to suggest paths: %w", err)
+			}
+			return nil, FetchSectionOutput{
+				Path:        input.Path,
+				Suggestions: suggestions,
+				Message:     "Document not found. Use list_docs or search_docs to find available paths.",
+			}, nil
+		}
+		if err != nil {
+			return nil, FetchSectionOutput{}, fmt.Errorf("failed to fetch document: %w", err)
+		}
+
+		// Headings are located in the body the chunker saw, without front matter
+		_, body, _ := markdown.ParseFrontMatter([]byte(doc.Content))
+		headings, err := markdown.Outline(body)
+		if err != nil {
+			return nil, FetchSectionOutput{}, fmt.Errorf("failed to parse headings: %w", err)
+		}
+		chunks, err := store.GetDocumentChunks(ctx, doc.Metadata.Path, doc.Metadata.Source)
+		if err != nil {
+			return nil, FetchSectionOutput{}, fmt.Errorf("failed to fetch chunks: %w", err)
+		}
+		starts := headingChunks(headings, chunks)
+
+		output := FetchSectionOutput{Path: doc.Metadata.Path, Source: doc.Metadata.Source, ChunkStart: -1, ChunkEnd: -1}
+		if input.TOC || strings.TrimSpace(input.Section) == "" {
+			output.TOC = tableOfContents(headings, starts)
+			return nil, output, nil
+		}
+
+		i, ok := findHeading(headings, input.Section)
+		if !ok {
+			output.TOC = tableOfContents(headings, starts)
+			output.Message = fmt.Sprintf("Section %q not found. Use a header path or anchor from toc.", input.Section)
+			return nil, output, nil
+		}
+
+		heading := headings[i]
+		output.Found = true
+		output.HeaderPath = heading.HeaderPath
+		output.Anchor = heading.ID
+		output.Content = fmt.Sprintf("<!-- Source: %s#%s -->\n\n%s",
+			doc.Metadata.Path, heading.ID, strings.TrimSpace(string(body[heading.Start:heading.End])))
+
+		first, last := sectionChunks(headings, starts, chunks, i)
+		output.ChunkStart, output.ChunkEnd = first, last
+		if n := min(max(input.Context, 0), maxSectionContext); n > 0 && first >= 0 {
+			for _, chunk := range chunks {
+				switch {
+				case chunk.ChunkIndex >= first-n && chunk.ChunkIndex < first:
+					output.Before = append(output.Before, neighbourChunk(chunk))
+				case chunk.ChunkIndex > last && chunk.ChunkIndex <= last+n:
+					output.After = append(output.After, neighbourChunk(chunk))
+				}
+			}
+		}
+
+		return nil, output, nil
+	}
+}
+
+// chunkStart is the chunk a heading falls in; exact is set when the chunk begins at it.
+type chunkStart struct {
+	index int // -1 when the document has no chunk for the heading
+	exact bool
+}
+
+// headingChunks maps each heading to the first chunk containing it. A heading that
+// starts a chunk has that chunk's header path (or an extension of it after a split);
+// a smaller heading inside a section is found in its deepest ancestor's chunks.
+func headingChunks(headings []markdown.Heading, chunks []*storage.Chunk) []chunkStart {
+	paths := make([][]string, len(chunks))
+	for i, chunk := range chunks {
+		paths[i] = headerTitles(chunk.HeaderPath)
+	}
+
+	starts := make([]chunkStart, len(headings))
+	for h, heading := range headings {
+		starts[h] = chunkStart{index: -1}
+		for i, path := range paths {
+			if hasTitlePrefix(path, heading.Path) {
+				starts[h] = chunkStart{index: chunks[i].ChunkIndex, exact: true}
+				break
+			}
+		}
+		for depth := len(heading.Path) - 1; depth > 0 && starts[h].index < 0; depth-- {
+			ancestor := heading.Path[:depth]
+			for i, path := range paths {
+				if hasTitlePrefix(path, ancestor) && len(path) == depth {
+					if starts[h].index < 0 {
+						starts[h].index = chunks[i].ChunkIndex
+					}
+					if strings.Contains(chunks[i].Content, heading.Title) {
+						starts[h].index = chunks[i].ChunkIndex
+						break
+					}
+				}
+			}
+		}
+	}
+	return starts
+}
+
+// sectionChunks returns the first and last chunk index covering section i (including
+// its subsections), or -1, -1 when the document has no chunks for it.
+func sectionChunks(headings []markdown.Heading, starts []chunkStart, chunks []*storage.Chunk, i int) (int, int) {
+	first := starts[i].index
+	if first < 0 {
+		return -1, -1
+	}
+
+	last := chunks[len(chunks)-1].ChunkIndex
+	for j := i + 1; j < len(headings); j++ {
+		if headings[j].Start >= headings[i].End && starts[j].index >= 0 {
+			// The next section shares its chunk with this one unless it starts a new chunk
+			last = starts[j].index
+			if starts[j].exact {
+				last--
+			}
+			break
+		}
+	}
+	return first, max(first, last)
+}
+
+// tableOfContents lists the headings in document order with their chunk indexes.
+func tableOfContents(headings []markdown.Heading, starts []chunkStart) []TOCEntry {
+	entries := make([]TOCEntry, len(headings))
+	for i, heading := range headings {
+		entries[i] = TOCEntry{
+			Level:      heading.Level,
+			Depth:      len(heading.Path),
+			Title:      heading.Title,
+			Anchor:     heading.ID,
+			HeaderPath: heading.HeaderPath,
+			ChunkIndex: starts[i].index,
+		}
+	}
+	return entries
+}
+
+// findHeading returns the index of the heading matching section: an anchor ID
+// ("#setup" or "setup"), or a header path whose trailing titles match
+// ("Setup", "Guide > Setup" or "# Guide > ## Setup"). Matching is case-insensitive
+// and the first match in document order wins.
+func findHeading(headings []markdown.Heading, section string) (int, bool) {
+	section = strings.TrimSpace(section)
+	anchor := section
+	if rest, ok := strings.CutPrefix(section, "#"); ok && rest != "" && rest[0] != '#' && rest[0] != ' ' {
+		anchor = rest
+	}
+	for i, heading := range headings {
+		if strings.EqualFold(heading.ID, anchor) {
+			return i, true
+		}
+	}
+
+	titles := headerTitles(section)
+	for i, heading := range headings {
+		if len(titles) > len(heading.Path) {
+			continue
+		}
+		if hasTitlePrefix(titles, heading.Path[len(heading.Path)-len(titles):]) {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// headerTitles splits a header path ("# Guide > ## Setup") into its titles.
+func headerTitles(headerPath string) []string {
+	if strings.TrimSpace(headerPath) == "" {
+		return nil
+	}
+	parts := strings.Split(headerPath, ">")
+	for i, part := range parts {
+		parts[i] = strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(part), "#"))
+	}
+	return parts
+}
+
+// hasTitlePrefix reports whether path starts with prefix, comparing titles case-insensitively.
+func hasTitlePrefix(path, prefix []string) bool {
+	if len(prefix) == 0 || len(prefix) > len(path) {
+		return false
+	}
+	for i, title := range prefix {
+		if !strings.EqualFold(path[i], title) {
+			return false
+		}
+	}
+	return true
+}
+
+// neighbourChunk converts a stored chunk into a context passage.
+func neighbourChunk(chunk *storage.Chunk) ChunkResult {
+	return ChunkResult{
+		Path:       chunk.Path,
+		Source:     chunk.Source,
+		HeaderPath: chunk.HeaderPath,
+		ChunkIndex: chunk.ChunkIndex,
+		Content:    chunk.Content,
+	}
+}
@@ -0,0 +1,85 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mike-a-ellis/eino-docs-mcp/internal/markdown"
+	"github.com/mike-a-ellis/eino-docs-mcp/internal/storage"
+)
+
+func TestFetchSectionHandler(t *testing.T) {
+	env := newTestEnv(t)
+	handler := makeFetchSectionHandler(env.store, env.searcher, env.sources)
+	ctx := context.Background()
+
+	// Table of contents
+	_, output, err := handler(ctx, nil, FetchSectionInput{Path: "core/graph.md", TOC: true})
+	require.NoError(t, err)
+	require.Len(t, output.TOC, 3)
+	assert.Equal(t, TOCEntry{Level: 2, Depth: 2, Title: "Compile", Anchor: "compile", HeaderPath: "# Graph > ## Compile", ChunkIndex: 1}, output.TOC[1])
+	assert.Equal(t, 1, output.TOC[2].ChunkIndex, "a heading inside a chunk maps to that chunk")
+	assert.Empty(t, output.Content)
+
+	// Section by header path includes subsections and neighbouring chunks
+	_, output, err = handler(ctx, nil, FetchSectionInput{Path: "core/graph.md", Section: "graph > compile", Context: 1})
+	require.NoError(t, err)
+	assert.True(t, output.Found)
+	assert.Equal(t, "# Graph > ## Compile", output.HeaderPath)
+	assert.Contains(t, output.Content, "<!-- Source: core/graph.md#compile -->")
+	assert.Contains(t, output.Content, "## Compile\n\nCall Compile before Invoke.")
+	assert.Contains(t, output.Content, "compose.WithGraphName")
+	assert.NotContains(t, output.Content, "orchestrate nodes")
+	assert.Equal(t, 1, output.ChunkStart)
+	assert.Equal(t, 1, output.ChunkEnd)
+	require.Len(t, output.Before, 1)
+	assert.Equal(t, 0, output.Before[0].ChunkIndex)
+	assert.Empty(t, output.After)
+
+	// Section by anchor, through an alias
+	_, output, err = handler(ctx, nil, FetchSectionInput{Path: "/docs/eino/old-graph/", Section: "#options"})
+	require.NoError(t, err)
+	assert.True(t, output.Found)
+	assert.Equal(t, "core/graph.md", output.Path)
+	assert.Equal(t, "# Graph > ## Compile > ### Options", output.HeaderPath)
+	assert.NotContains(t, output.Content, "Call Compile")
+
+	// Unknown sections return the table of contents
+	_, output, err = handler(ctx, nil, FetchSectionInput{Path: "core/graph.md", Section: "Streaming"})
+	require.NoError(t, err)
+	assert.False(t, output.Found)
+	assert.Len(t, output.TOC, 3)
+	assert.Contains(t, output.Message, "not found")
+
+	_, output, err = handler(ctx, nil, FetchSectionInput{Path: "core/graphs.md", Section: "Compile"})
+	require.NoError(t, err)
+	assert.False(t, output.Found)
+	require.NotEmpty(t, output.Suggestions)
+	assert.Equal(t, "core/graph.md", output.Suggestions[0].Path)
+}
+
+func TestSectionChunks_SplitSection(t *testing.T) {
+	source := []byte("# Guide\n\nIntro.\n\n## Setup\n\nFirst part.\n\nSecond part.\n\n## Usage\n\nRun it.\n")
+	headings, err := markdown.Outline(source)
+	require.NoError(t, err)
+
+	// Setup was split into two chunks
+	chunks := []*storage.Chunk{
+		{ChunkIndex: 0, HeaderPath: "# Guide", Content: "Guide\n\nIntro."},
+		{ChunkIndex: 1, HeaderPath: "# Guide > ## Setup", Content: "Setup\n\nFirst part."},
+		{ChunkIndex: 2, HeaderPath: "# Guide > ## Setup", Content: "Second part."},
+		{ChunkIndex: 3, HeaderPath: "# Guide > ## Usage", Content: "Usage\n\nRun it."},
+	}
+	starts := headingChunks(headings, chunks)
+
+	first, last := sectionChunks(headings, starts, chunks, 1)
+	assert.Equal(t, 1, first)
+	assert.Equal(t, 2, last)
+
+	first, last = sectionChunks(headings, starts, chunks, 0)
+	assert.Equal(t, 0, first)
+	assert.Equal(t, 3, last, "a top-level section spans its subsections")
+}
@@ -5,60 +5,125 @@ import (
 
 	"github.com/mike-a-ellis/eino-docs-mcp/internal/embedding"
 	ghclient "github.com/mike-a-ellis/eino-docs-mcp/internal/github"
+	"github.com/mike-a-ellis/eino-docs-mcp/internal/prompts"
+	"github.com/mike-a-ellis/eino-docs-mcp/internal/rerank"
+	"github.com/mike-a-ellis/eino-docs-mcp/internal/scheduler"
+	"github.com/mike-a-ellis/eino-docs-mcp/internal/search"
+	"github.com/mike-a-ellis/eino-docs-mcp/internal/source"
 	"github.com/mike-a-ellis/eino-docs-mcp/internal/storage"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 )
 
 // Server wraps the MCP server with dependencies.
 type Server struct {
-	server   *mcp.Server
-	storage  *storage.QdrantStorage
-	embedder *embedding.Embedder
-	github   *ghclient.Client
+	server    *mcp.Server
+	storage   storage.Store
+	embedder  embedding.Embedder
+	github    *ghclient.Client
+	resources *resourceSet
 }
 
 // Config holds server dependencies.
 type Config struct {
-	Storage  *storage.QdrantStorage
-	Embedder *embedding.Embedder
+	Storage  storage.Store
+	Embedder embedding.Embedder
 	GitHub   *ghclient.Client
+	Prompts  []*prompts.Prompt // Prompt library (see prompts.Load); nil registers none
+	Reranker rerank.Reranker   // Optional reranker for search_docs; nil disables rerank
+	// Scheduler is the optional auto-sync scheduler reported by get_index_status.
+	Scheduler *scheduler.Scheduler
+	// Sources are the documentation sources indexed (see source.LoadConfig), selectable
+	// with every tool's source argument; nil serves source.DefaultConfig alone.
+	Sources []source.Config
 }
 
-// NewServer creates a configured MCP server with tools registered.
+// NewServer creates a configured MCP server with tools, prompts and the document
+// resource template registered.
 func NewServer(cfg *Config) *Server {
 	impl := &mcp.Implementation{
 		Name:    "eino-user-manual-server",
 		Version: "v0.1.0",
 	}
 
-	server := mcp.NewServer(impl, nil)
+	// Subscription handlers are created before the server they belong to, so they
+	// forward to the resource set assigned below.
+	var resources *resourceSet
+	sources := newSourceSet(cfg.Sources)
+	completions := newCompleter(cfg.Storage, sources, cfg.Prompts)
+	server := mcp.NewServer(impl, &mcp.ServerOptions{
+		PageSize:          resourcePageSize,
+		CompletionHandler: completions.complete,
+		SubscribeHandler: func(ctx context.Context, req *mcp.SubscribeRequest) error {
+			return resources.subscribe(ctx, req)
+		},
+		UnsubscribeHandler: func(ctx context.Context, req *mcp.UnsubscribeRequest) error {
+			return resources.unsubscribe(ctx, req)
+		},
+	})
+	resources = newResourceSet(server, cfg.Storage, sources)
+	searcher := search.NewSearcher(cfg.Storage, cfg.Embedder)
+	if cfg.Reranker != nil {
+		searcher = searcher.WithReranker(cfg.Reranker)
+	}
 
 	// Register tools with real handlers
 	mcp.AddTool(server, &mcp.Tool{
 		Name:        "search_docs",
-		Description: "Search Eino User Manual documentation semantically. Returns metadata for matching documents. Use fetch_doc to get full content.",
-	}, makeSearchHandler(cfg.Storage, cfg.Embedder))
+		Description: "Search Eino User Manual documentation. Hybrid mode (default) combines semantic similarity with exact keyword matching, so identifiers like compose.NewGraph rank well. Returns metadata for matching documents, and Go API symbols (type symbol, with signature) indexed with --go-source unless exclude_symbols is set. Use fetch_doc to get full content. Results are diversified with maximal marginal relevance by default (mmr_lambda 0.7); set mmr_lambda to 1 for pure relevance order. Set source to search one documentation source; list_docs lists them.",
+	}, makeSearchHandler(cfg.Storage, searcher, sources))
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "search_chunks",
+		Description: "Search Eino User Manual documentation and return the best-matching passages (section path, content, score) within a token budget. Use this to answer from snippets without fetching whole documents. Matching Go API symbols are returned as passages with their signature and doc comment unless exclude_symbols is set. Passages are diversified with maximal marginal relevance by default (mmr_lambda 0.7); set mmr_lambda to 1 for pure relevance order. Set source to search one documentation source; list_docs lists them.",
+	}, makeSearchChunksHandler(searcher, sources))
 
 	mcp.AddTool(server, &mcp.Tool{
 		Name:        "fetch_doc",
-		Description: "Retrieve a specific Eino User Manual document by path. Returns full markdown content.",
-	}, makeFetchHandler(cfg.Storage))
+		Description: "Retrieve a specific Eino User Manual document by path. Returns full markdown content. If the path does not exist, suggests the closest existing paths. Without source, the first documentation source that has the path is used.",
+	}, makeFetchHandler(cfg.Storage, searcher, sources))
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "fetch_section",
+		Description: "Retrieve one section of an Eino User Manual document by header path or anchor, including its subsections and optionally neighbouring chunks. Without a section, returns the document's table of contents with the chunk index of each heading.",
+	}, makeFetchSectionHandler(cfg.Storage, searcher, sources))
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "related_docs",
+		Description: "Find Eino User Manual documents related to a given document, by similarity of their content and by explicit links between them. Each result says why it is related: links-to, linked-from or semantic.",
+	}, makeRelatedHandler(cfg.Storage, searcher, sources))
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "lookup_symbol",
+		Description: "Look up a Go symbol of the Eino framework (type, interface, function or method, e.g. compose.NewGraph or Graph.Compile). Returns its exact signature, doc comment, source position and pkg.go.dev link, plus the documentation pages that mention it.",
+	}, makeLookupSymbolHandler(cfg.Storage, searcher, sources))
 
 	mcp.AddTool(server, &mcp.Tool{
 		Name:        "list_docs",
-		Description: "List all available Eino User Manual documentation paths.",
-	}, makeListHandler(cfg.Storage))
+		Description: "List all available Eino User Manual documentation paths and the documentation sources they come from.",
+	}, makeListHandler(cfg.Storage, sources))
 
 	mcp.AddTool(server, &mcp.Tool{
 		Name:        "get_index_status",
-		Description: "Get the current status of the Eino User Manual documentation index including document counts, last sync time, and staleness indicator.",
-	}, makeStatusHandler(cfg.Storage, cfg.GitHub))
+		Description: "Get the current status of the Eino User Manual documentation index including document counts, last sync time, and staleness indicator, for each documentation source.",
+	}, makeStatusHandler(cfg.Storage, cfg.GitHub, cfg.Scheduler, sources))
+
+	addPrompts(server, cfg.Storage, searcher, cfg.Prompts)
+
+	// Individual documents are registered by WatchResources; the template lets
+	// clients read any path directly.
+	server.AddResourceTemplate(&mcp.ResourceTemplate{
+		URITemplate: resourceURITemplate,
+		Name:        "eino-doc",
+		Description: "An Eino User Manual document by source and path, e.g. " + DocumentURI(sources[0].Name, "overview/_index.md"),
+		MIMEType:    resourceMIMEType,
+	}, resources.handler)
 
 	return &Server{
-		server:   server,
-		storage:  cfg.Storage,
-		embedder: cfg.Embedder,
-		github:   cfg.GitHub,
+		server:    server,
+		storage:   cfg.Storage,
+		embedder:  cfg.Embedder,
+		github:    cfg.GitHub,
+		resources: resources,
 	}
 }
 
@@ -0,0 +1,67 @@
+package mcp
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mike-a-ellis/eino-docs-mcp/internal/source"
+)
+
+// sourceSet is the documentation sources the server answers from, in configured order.
+// Lookups without a source try them in that order.
+type sourceSet []source.Config
+
+// newSourceSet returns the configured sources, or the Eino docs alone when none are.
+func newSourceSet(configs []source.Config) sourceSet {
+	if len(configs) == 0 {
+		return sourceSet{source.DefaultConfig}
+	}
+	return configs
+}
+
+// names returns the source names in order.
+func (s sourceSet) names() []string {
+	names := make([]string, len(s))
+	for i, cfg := range s {
+		names[i] = cfg.Name
+	}
+	return names
+}
+
+// lookup returns the source called name.
+func (s sourceSet) lookup(name string) (source.Config, bool) {
+	for _, cfg := range s {
+		if cfg.Name == name {
+			return cfg, true
+		}
+	}
+	return source.Config{}, false
+}
+
+// selected returns the sources a tool's source argument selects: the named one, or
+// all of them when name is empty.
+func (s sourceSet) selected(name string) (sourceSet, error) {
+	if name == "" {
+		return s, nil
+	}
+	cfg, ok := s.lookup(name)
+	if !ok {
+		return nil, fmt.Errorf("unknown source %q (available: %s)", name, strings.Join(s.names(), ", "))
+	}
+	return sourceSet{cfg}, nil
+}
+
+// filter returns the storage.SearchFilter source matching the set: the lone source's
+// name, or "" to search them all.
+func (s sourceSet) filter() string {
+	if len(s) == 1 {
+		return s[0].Name
+	}
+	return ""
+}
+
+// documentKey identifies a document across sources; a path is unique only within one.
+type documentKey struct {
+	source string
+	path   string
+}
@@ -0,0 +1,183 @@
+package mcp
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/mike-a-ellis/eino-docs-mcp/internal/search"
+	"github.com/mike-a-ellis/eino-docs-mcp/internal/storage"
+)
+
+// Suggestion reasons reported in PathSuggestion.Reason.
+const (
+	reasonSimilarPath = "similar_path"
+	reasonAlias       = "alias"
+	reasonSemantic    = "semantic"
+)
+
+const (
+	// maxSuggestions is the number of paths suggested when fetch_doc misses.
+	maxSuggestions = 5
+	// minSuggestionScore drops lexical candidates that share too little with the request.
+	minSuggestionScore = 0.35
+	// semanticSuggestionWeight scales cosine scores so a close lexical match wins ties.
+	semanticSuggestionWeight = 0.8
+)
+
+// pathNoise are path words that carry no meaning for matching.
+var pathNoise = map[string]bool{
+	"md": true, "index": true, "content": true, "en": true, "docs": true, "eino": true,
+}
+
+// suggestPaths returns the paths of sources closest to a path that was not found.
+// Candidates are scored by edit distance and shared path words against every path
+// and alias; when searcher is non-nil, a dense search over the path's words adds
+// semantic matches. Each path keeps its best score and the reason for it.
+func suggestPaths(ctx context.Context, store storage.Store, searcher *search.Searcher, sources sourceSet, requested string) ([]PathSuggestion, error) {
+	best := make(map[documentKey]PathSuggestion)
+	consider := func(key documentKey, score float64, reason string) {
+		if current, ok := best[key]; !ok || score > current.Score {
+			best[key] = PathSuggestion{Path: key.path, Source: key.source, Score: score, Reason: reason}
+		}
+	}
+
+	query := normalizePath(requested)
+	for _, cfg := range sources {
+		paths, err := store.ListDocumentPaths(ctx, cfg.Name)
+		if err != nil {
+			return nil, err
+		}
+		aliases, err := store.ListAliases(ctx, cfg.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, path := range paths {
+			if score := pathSimilarity(query, normalizePath(path)); score >= minSuggestionScore {
+				consider(documentKey{source: cfg.Name, path: path}, score, reasonSimilarPath)
+			}
+		}
+		for alias, path := range aliases {
+			if score := pathSimilarity(query, normalizePath(alias)); score >= minSuggestionScore {
+				consider(documentKey{source: cfg.Name, path: path}, score, reasonAlias)
+			}
+		}
+	}
+
+	// Semantic matches are best effort: a failing embedder only loses this signal
+	if words := strings.Join(pathWords(query), " "); searcher != nil && words != "" {
+		if ranked, err := searchDocuments(ctx, store, searcher, words, maxSuggestions, search.Options{
+			Mode:     search.ModeDense,
+			MinScore: minSuggestionScore,
+			Filter:   storage.SearchFilter{Source: sources.filter()},
+		}); err == nil {
+			for _, r := range ranked {
+				consider(documentKey{source: r.doc.Metadata.Source, path: r.doc.Metadata.Path}, r.score*semanticSuggestionWeight, reasonSemantic)
+			}
+		}
+	}
+
+	suggestions := make([]PathSuggestion, 0, len(best))
+	for _, s := range best {
+		suggestions = append(suggestions, s)
+	}
+	sort.Slice(suggestions, func(i, j int) bool {
+		if suggestions[i].Score != suggestions[j].Score {
+			return suggestions[i].Score > suggestions[j].Score
+		}
+		if suggestions[i].Path != suggestions[j].Path {
+			return suggestions[i].Path < suggestions[j].Path
+		}
+		return suggestions[i].Source < suggestions[j].Source
+	})
+	if len(suggestions) > maxSuggestions {
+		suggestions = suggestions[:maxSuggestions]
+	}
+	return suggestions, nil
+}
+
+// normalizePath lowercases a path or URL path and strips the repository prefix,
+// slashes and the .md extension, so "/docs/eino/Core/Graph/" matches "core/graph.md".
+func normalizePath(path string) string {
+	path = storage.NormalizeAlias(path)
+	for _, prefix := range []string{"content/en/docs/eino/", "docs/eino/"} {
+		path = strings.TrimPrefix(path, prefix)
+	}
+	path = strings.TrimSuffix(path, ".md")
+	path = strings.TrimSuffix(path, "/_index")
+	return path
+}
+
+// pathWords splits a normalized path into meaningful words.
+func pathWords(path string) []string {
+	var words []string
+	for _, word := range strings.FieldsFunc(path, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if !pathNoise[word] {
+			words = append(words, word)
+		}
+	}
+	return words
+}
+
+// pathSimilarity scores two normalized paths in [0, 1] as the mean of their
+// edit-distance similarity and the overlap (Jaccard) of their path words.
+func pathSimilarity(a, b string) float64 {
+	if a == "" || b == "" {
+		return 0
+	}
+	longest := max(len([]rune(a)), len([]rune(b)))
+	edit := 1 - float64(levenshtein(a, b))/float64(longest)
+	return (edit + wordOverlap(pathWords(a), pathWords(b))) / 2
+}
+
+// wordOverlap returns the Jaccard similarity of two word lists.
+func wordOverlap(a, b []string) float64 {
+	set := make(map[string]bool, len(a))
+	for _, w := range a {
+		set[w] = true
+	}
+	union := len(set)
+	shared := 0
+	seen := make(map[string]bool, len(b))
+	for _, w := range b {
+		if seen[w] {
+			continue
+		}
+		seen[w] = true
+		if set[w] {
+			shared++
+		} else {
+			union++
+		}
+	}
+	if union == 0 {
+		return 0
+	}
+	return float64(shared) / float64(union)
+}
+
+// levenshtein returns the edit distance between two strings, counted in runes.
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
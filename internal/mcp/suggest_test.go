@@ -0,0 +1,32 @@
+package mcp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLevenshtein(t *testing.T) {
+	assert.Equal(t, 0, levenshtein("graph", "graph"))
+	assert.Equal(t, 1, levenshtein("graph", "graphs"))
+	assert.Equal(t, 3, levenshtein("kitten", "sitting"))
+	assert.Equal(t, 2, levenshtein("快速开始", "开始"), "distance is counted in runes")
+}
+
+func TestNormalizePath(t *testing.T) {
+	assert.Equal(t, "core/graph", normalizePath("/docs/eino/Core/Graph/"))
+	assert.Equal(t, "core/graph", normalizePath("content/en/docs/eino/core/graph.md"))
+	assert.Equal(t, "overview", normalizePath("overview/_index.md"))
+}
+
+func TestPathSimilarity(t *testing.T) {
+	exact := pathSimilarity("core/graph", "core/graph")
+	near := pathSimilarity("core/graphs", "core/graph")
+	moved := pathSimilarity("graph", "core/graph")
+	unrelated := pathSimilarity("core/graph", "quick_start")
+
+	assert.InDelta(t, 1.0, exact, 1e-9)
+	assert.Greater(t, near, minSuggestionScore)
+	assert.Greater(t, moved, minSuggestionScore, "shared words count even when the directory moved")
+	assert.Less(t, unrelated, minSuggestionScore)
+}
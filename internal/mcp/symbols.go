@@ -0,0 +1,211 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/mike-a-ellis/eino-docs-mcp/internal/search"
+	"github.com/mike-a-ellis/eino-docs-mcp/internal/storage"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	// mentionCandidates is how many chunks are searched for mentions of a symbol.
+	mentionCandidates = 50
+	// minSymbolSimilarity drops suggested names that share too little with the request.
+	minSymbolSimilarity = 0.7
+)
+
+// makeLookupSymbolHandler creates the lookup_symbol tool handler.
+// Lookup flow:
+// 1. Find declarations matching the name (bare, qualified, or method)
+// 2. If none match, suggest similar symbol names
+// 3. Keyword-search the docs for the symbol names
+// 4. Keep chunks that mention a name as a whole word, one per document
+func makeLookupSymbolHandler(store storage.Store, searcher *search.Searcher, sources sourceSet) func(
+	context.Context, *mcp.CallToolRequest, LookupSymbolInput,
+) (*mcp.CallToolResult, LookupSymbolOutput, error) {
+	return func(ctx context.Context, req *mcp.CallToolRequest, input LookupSymbolInput) (
+		*mcp.CallToolResult, LookupSymbolOutput, error,
+	) {
+		// Apply defaults
+		maxMentions := input.MaxMentions
+		if maxMentions <= 0 {
+			maxMentions = 10
+		}
+		if maxMentions > 50 {
+			maxMentions = 50
+		}
+		if _, err := sources.selected(input.Source); err != nil {
+			return nil, LookupSymbolOutput{}, err
+		}
+
+		symbols, err := store.FindSymbols(ctx, input.Name, "")
+		if err != nil {
+			return nil, LookupSymbolOutput{}, fmt.Errorf("failed to find symbol: %w", err)
+		}
+
+		output := LookupSymbolOutput{
+			Name:        input.Name,
+			Found:       len(symbols) > 0,
+			Symbols:     []SymbolResult{}, // Non-nil for JSON marshaling
+			MentionedIn: []SymbolMention{},
+		}
+
+		if len(symbols) == 0 {
+			names, err := store.ListSymbolNames(ctx, "")
+			if err != nil {
+				return nil, LookupSymbolOutput{}, fmt.Errorf("failed to list symbols: %w", err)
+			}
+			if len(names) == 0 {
+				output.Message = "No Go symbols are indexed. Run eino-sync sync --go-source to index the Eino API."
+				return nil, output, nil
+			}
+			output.Suggestions = suggestSymbols(names, input.Name)
+			output.Message = "Symbol not found."
+			return nil, output, nil
+		}
+
+		for _, s := range symbols {
+			output.Symbols = append(output.Symbols, symbolResult(s))
+		}
+
+		mentions, err := findMentions(ctx, store, searcher, symbols, input.Source, maxMentions)
+		if err != nil {
+			return nil, LookupSymbolOutput{}, err
+		}
+		output.MentionedIn = mentions
+		if len(mentions) == 0 {
+			output.Message = "No documentation pages mention this symbol."
+		}
+
+		return nil, output, nil
+	}
+}
+
+// symbolResult describes a symbol, linking its pkg.go.dev reference page.
+func symbolResult(s *storage.Symbol) SymbolResult {
+	return SymbolResult{
+		Name:      s.QualifiedName(),
+		Kind:      s.Kind,
+		Package:   s.Package,
+		Signature: s.Signature,
+		Doc:       s.Doc,
+		File:      s.File,
+		Line:      s.Line,
+		URL:       "https://pkg.go.dev/" + s.Package + "#" + s.Name,
+	}
+}
+
+// suggestSymbols returns up to maxSuggestions indexed names resembling name: names it
+// completes first (prefix, dotted part, substring), then near misses by edit distance
+// on the full name or its last dotted part, so "compose.NewGraf" finds "compose.NewGraph".
+func suggestSymbols(names []string, name string) []string {
+	suggestions := matchCompletions(names, name)
+
+	type candidate struct {
+		name  string
+		score float64
+	}
+	var similar []candidate
+	query := storage.SymbolKey(name)
+	for _, n := range names {
+		if slices.Contains(suggestions, n) {
+			continue
+		}
+		key := storage.SymbolKey(n)
+		score := max(editSimilarity(query, key), editSimilarity(lastPart(query), lastPart(key)))
+		if score >= minSymbolSimilarity {
+			similar = append(similar, candidate{n, score})
+		}
+	}
+	sort.SliceStable(similar, func(i, j int) bool { return similar[i].score > similar[j].score })
+	for _, c := range similar {
+		suggestions = append(suggestions, c.name)
+	}
+
+	return suggestions[:min(len(suggestions), maxSuggestions)]
+}
+
+// editSimilarity scores two strings in [0, 1] by edit distance.
+func editSimilarity(a, b string) float64 {
+	if a == "" || b == "" {
+		return 0
+	}
+	return 1 - float64(levenshtein(a, b))/float64(max(len([]rune(a)), len([]rune(b))))
+}
+
+// lastPart returns the part of a dotted name after the last dot.
+func lastPart(name string) string {
+	return name[strings.LastIndex(name, ".")+1:]
+}
+
+// findMentions returns the documents of a source ("" for all) whose chunks mention any
+// of the symbols, in search order, with the section of the best mention.
+func findMentions(ctx context.Context, store storage.Store, searcher *search.Searcher, symbols []*storage.Symbol, source string, limit int) ([]SymbolMention, error) {
+	mentions := []SymbolMention{}
+	seen := make(map[string]bool)
+
+	for _, s := range symbols {
+		query := s.QualifiedName() + " " + s.Name
+		chunks, err := searcher.Search(ctx, query, search.Options{
+			Mode:   search.ModeSparse,
+			Limit:  mentionCandidates,
+			Filter: storage.SearchFilter{Source: source},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to search for mentions: %w", err)
+		}
+
+		pattern := mentionPattern(s)
+		for _, chunk := range chunks {
+			if len(mentions) >= limit {
+				return mentions, nil
+			}
+			if seen[chunk.ParentDocID] || !mentionsSymbol(chunk.Chunk, s, pattern) {
+				continue
+			}
+			seen[chunk.ParentDocID] = true
+
+			mention := SymbolMention{
+				Path:       chunk.Path,
+				Source:     chunk.Source,
+				HeaderPath: chunk.HeaderPath,
+				Symbol:     s.QualifiedName(),
+			}
+			if doc, err := store.GetDocument(ctx, chunk.ParentDocID); err == nil {
+				mention.Title = doc.Metadata.Title
+			}
+			mentions = append(mentions, mention)
+		}
+	}
+	return mentions, nil
+}
+
+// mentionPattern matches a symbol as a whole word in prose or code: the qualified name
+// or the declared name for functions and types, and "Recv.Method" or ".Method(" for
+// methods, since examples usually call them on a variable.
+func mentionPattern(s *storage.Symbol) *regexp.Regexp {
+	words := []string{regexp.QuoteMeta(s.QualifiedName())}
+	call := ""
+	if recv, method, ok := strings.Cut(s.Name, "."); ok {
+		words = append(words, regexp.QuoteMeta(recv)+`(\[[^\]]*\])?\)?\.`+regexp.QuoteMeta(method))
+		call = `|\.` + regexp.QuoteMeta(method) + `\(`
+	} else {
+		words = append(words, regexp.QuoteMeta(s.Name))
+	}
+	return regexp.MustCompile(`\b(` + strings.Join(words, "|") + `)\b` + call)
+}
+
+// mentionsSymbol reports whether a chunk mentions the symbol in its content or lists
+// it among its document's entities.
+func mentionsSymbol(chunk *storage.Chunk, s *storage.Symbol, pattern *regexp.Regexp) bool {
+	if slices.Contains(chunk.Entities, s.Name) || slices.Contains(chunk.Entities, s.QualifiedName()) {
+		return true
+	}
+	return pattern.MatchString(chunk.Content)
+}
@@ -0,0 +1,115 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mike-a-ellis/eino-docs-mcp/internal/goapi"
+	"github.com/mike-a-ellis/eino-docs-mcp/internal/indexer"
+	"github.com/mike-a-ellis/eino-docs-mcp/internal/storage"
+)
+
+func TestLookupSymbolHandler(t *testing.T) {
+	env := newTestEnv(t)
+	handler := makeLookupSymbolHandler(env.store, env.searcher, env.sources)
+	ctx := context.Background()
+
+	// Nothing indexed yet
+	_, output, err := handler(ctx, nil, LookupSymbolInput{Name: "compose.NewGraph"})
+	require.NoError(t, err)
+	assert.False(t, output.Found)
+	assert.Contains(t, output.Message, "--go-source")
+
+	embedding := make([]float32, env.embedder.Dimension())
+	embedding[0] = 1
+	require.NoError(t, env.store.UpsertSymbols(ctx, []*storage.Symbol{
+		{ID: "11111111-1111-1111-1111-111111111111", Name: "NewGraph", Kind: "func", Package: "github.com/cloudwego/eino/compose", PackageName: "compose",
+			Signature: "func NewGraph[I, O any](opts ...NewGraphOption) *Graph[I, O]", Doc: "NewGraph creates a new graph.", File: "compose/graph.go", Line: 42, Repository: "cloudwego/eino", Embedding: embedding},
+		{ID: "22222222-2222-2222-2222-222222222222", Name: "Graph.Compile", Kind: "method", Package: "github.com/cloudwego/eino/compose", PackageName: "compose",
+			Signature: "func (g *Graph[I, O]) Compile(ctx context.Context, opts ...GraphCompileOption) (Runnable[I, O], error)", File: "compose/graph.go", Line: 90, Repository: "cloudwego/eino", Embedding: embedding},
+	}))
+
+	_, output, err = handler(ctx, nil, LookupSymbolInput{Name: "compose.NewGraph"})
+	require.NoError(t, err)
+	assert.True(t, output.Found)
+	require.Len(t, output.Symbols, 1)
+	symbol := output.Symbols[0]
+	assert.Equal(t, "compose.NewGraph", symbol.Name)
+	assert.Equal(t, "func NewGraph[I, O any](opts ...NewGraphOption) *Graph[I, O]", symbol.Signature)
+	assert.Equal(t, "https://pkg.go.dev/github.com/cloudwego/eino/compose#NewGraph", symbol.URL)
+	require.Len(t, output.MentionedIn, 1)
+	assert.Equal(t, "core/graph.md", output.MentionedIn[0].Path)
+	assert.Equal(t, "Graph Orchestration", output.MentionedIn[0].Title)
+	assert.Equal(t, "compose.NewGraph", output.MentionedIn[0].Symbol)
+
+	// Methods resolve by bare name or pointer receiver; "Compile" alone in prose is not a mention
+	_, output, err = handler(ctx, nil, LookupSymbolInput{Name: "(*Graph).Compile"})
+	require.NoError(t, err)
+	require.Len(t, output.Symbols, 1)
+	assert.Equal(t, "compose.Graph.Compile", output.Symbols[0].Name)
+	assert.Empty(t, output.MentionedIn)
+
+	_, output, err = handler(ctx, nil, LookupSymbolInput{Name: "compose.NewGraf"})
+	require.NoError(t, err)
+	assert.False(t, output.Found)
+	assert.Contains(t, output.Suggestions, "compose.NewGraph")
+}
+
+func TestSearchHandlers_Symbols(t *testing.T) {
+	env := newTestEnv(t)
+	ctx := context.Background()
+
+	_, err := indexer.IndexSymbols(ctx, env.store, env.embedder, indexer.SymbolRepository, "sha-1", []goapi.Symbol{
+		{Name: "NewGraph", Kind: goapi.KindFunc, Package: "github.com/cloudwego/eino/compose", PackageName: "compose",
+			Signature: "func NewGraph[I, O any](opts ...NewGraphOption) *Graph[I, O]", Doc: "NewGraph creates a new graph."},
+	})
+	require.NoError(t, err)
+
+	chunks := makeSearchChunksHandler(env.searcher, env.sources)
+	_, output, err := chunks(ctx, nil, SearchChunksInput{Query: "NewGraphOption", Mode: "sparse"})
+	require.NoError(t, err)
+	require.NotEmpty(t, output.Chunks)
+	hit := output.Chunks[0]
+	assert.Equal(t, "compose.NewGraph", hit.HeaderPath)
+	assert.Empty(t, hit.Path)
+	require.NotNil(t, hit.Symbol)
+	assert.Equal(t, "https://pkg.go.dev/github.com/cloudwego/eino/compose#NewGraph", hit.Symbol.URL)
+	assert.Contains(t, hit.Content, "NewGraph creates a new graph.")
+
+	_, output, err = chunks(ctx, nil, SearchChunksInput{Query: "NewGraphOption", Mode: "sparse", ExcludeSymbols: true})
+	require.NoError(t, err)
+	for _, chunk := range output.Chunks {
+		assert.Nil(t, chunk.Symbol)
+	}
+
+	docs := makeSearchHandler(env.store, env.searcher, env.sources)
+	_, results, err := docs(ctx, nil, SearchDocsInput{Query: "compose.NewGraph", Mode: "sparse"})
+	require.NoError(t, err)
+	types := make(map[string]string)
+	for _, result := range results.Results {
+		types[result.Title] = result.Type
+	}
+	assert.Equal(t, "symbol", types["compose.NewGraph"])
+	assert.Equal(t, "document", types["Graph Orchestration"])
+
+	// Path filters select documents only
+	_, results, err = docs(ctx, nil, SearchDocsInput{Query: "compose.NewGraph", Mode: "sparse", PathPrefix: "core"})
+	require.NoError(t, err)
+	for _, result := range results.Results {
+		assert.Equal(t, "document", result.Type)
+	}
+}
+
+func TestMentionPattern(t *testing.T) {
+	compile := mentionPattern(&storage.Symbol{Name: "Graph.Compile", PackageName: "compose"})
+	assert.True(t, compile.MatchString("r, err := g.Compile(ctx)"))
+	assert.True(t, compile.MatchString("see (*Graph[I, O]).Compile"))
+	assert.False(t, compile.MatchString("Call Compile before Invoke."))
+
+	newGraph := mentionPattern(&storage.Symbol{Name: "NewGraph", PackageName: "compose"})
+	assert.True(t, newGraph.MatchString("Use compose.NewGraph to orchestrate"))
+	assert.False(t, newGraph.MatchString("compose.NewGraphOption"))
+}
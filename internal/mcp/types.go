@@ -11,7 +11,25 @@ type SearchDocsInput struct {
 	// MaxResults is the maximum number of documents to return (1-20, default 5).
 	MaxResults int `json:"max_results,omitempty" jsonschema:"Maximum number of documents to return (1-20, default 5)"`
 	// MinScore is the minimum relevance threshold (0-1, default 0.3).
-	MinScore float64 `json:"min_score,omitempty" jsonschema:"Minimum relevance score threshold (0-1, default 0.3)"`
+	MinScore float64 `json:"min_score,omitempty" jsonschema:"Minimum semantic similarity for dense matches (0-1, default 0.3)"`
+	// Mode selects dense (embedding), sparse (BM25 keyword) or hybrid retrieval.
+	Mode string `json:"mode,omitempty" jsonschema:"Search mode: dense (semantic), sparse (exact keywords/identifiers) or hybrid (both fused, default)"`
+	// MMRLambda trades relevance against diversity (0-1, default 0.7, 1 disables MMR).
+	MMRLambda float64 `json:"mmr_lambda,omitempty" jsonschema:"Relevance vs. diversity trade-off for maximal marginal relevance re-ranking (0-1, default 0.7; lower favours results unlike each other, 1 keeps pure relevance order)"`
+	// PathPrefix restricts results to documents under a directory.
+	PathPrefix string `json:"path_prefix,omitempty" jsonschema:"Only search documents under this directory (e.g. core_modules/components/)"`
+	// Categories restricts results to documents in any of the given top-level directories.
+	Categories []string `json:"categories,omitempty" jsonschema:"Only search documents in these top-level directories (e.g. quick_start or ecosystem_integration); list_docs returns the available categories"`
+	// Entities restricts results to documents mentioning any of the given entities.
+	Entities []string `json:"entities,omitempty" jsonschema:"Only search documents whose extracted entities include any of these exact names (e.g. compose.NewGraph)"`
+	// Rerank re-scores the candidate chunks with the configured reranker.
+	Rerank bool `json:"rerank,omitempty" jsonschema:"Re-score candidates with a reranking model that reads the query and passage together; slower but better for how-to questions"`
+	// RerankCandidates is the number of chunks to rerank (default 30, max 100).
+	RerankCandidates int `json:"rerank_candidates,omitempty" jsonschema:"Number of candidate passages to rerank when rerank is set (default 30, max 100)"`
+	// ExcludeSymbols returns documentation pages only, without Go API symbols.
+	ExcludeSymbols bool `json:"exclude_symbols,omitempty" jsonschema:"Return documentation pages only; by default matching Go API symbols (type symbol) are ranked alongside them"`
+	// Source restricts the search to one documentation source.
+	Source string `json:"source,omitempty" jsonschema:"Only search this documentation source (e.g. eino); list_docs returns the available sources. Omit to search them all"`
 }
 
 // SearchDocsOutput contains the search results.
@@ -22,25 +40,97 @@ type SearchDocsOutput struct {
 	Message string `json:"message,omitempty"`
 }
 
-// SearchResult represents a single document match from semantic search.
+// SearchResult represents a single document or Go API symbol match from semantic search.
 type SearchResult struct {
-	// Path is the document path (e.g., "getting-started/installation.md").
-	Path string `json:"path"`
-	// Score is the similarity score (0-1).
+	// Type is "document" for documentation pages and "symbol" for Go API symbols.
+	Type string `json:"type"`
+	// Path is the document path (e.g., "getting-started/installation.md"); empty for symbols.
+	Path string `json:"path,omitempty"`
+	// Source is the documentation source the document belongs to; empty for symbols.
+	Source string `json:"source,omitempty"`
+	// Score is the relevance score (0-1); its meaning depends on the search mode, and it
+	// is the reranker's score when the results were reranked.
 	Score float64 `json:"score"`
-	// Summary is the LLM-generated document summary.
+	// OriginalScore is the retrieval score before reranking (reranked results only).
+	OriginalScore *float64 `json:"original_score,omitempty"`
+	// Summary is the LLM-generated document summary, or a symbol's doc comment.
 	Summary string `json:"summary"`
 	// Entities lists extracted functions/methods from the document.
 	Entities []string `json:"entities"`
-	// UpdatedAt is when the document was last indexed.
-	UpdatedAt time.Time `json:"updated_at"`
+	// Title is the page title from the document's front matter, or a symbol's
+	// qualified name.
+	Title string `json:"title,omitempty"`
+	// Description is the page description from the document's front matter.
+	Description string `json:"description,omitempty"`
+	// Weight is the Hugo ordering weight within the document's section.
+	Weight int `json:"weight,omitempty"`
+	// Date is the page date from the document's front matter.
+	Date *time.Time `json:"date,omitempty"`
+	// UpdatedAt is when the document was last indexed (documents only).
+	UpdatedAt time.Time `json:"updated_at,omitzero"`
+	// Symbol is the matching declaration (symbols only).
+	Symbol *SymbolResult `json:"symbol,omitempty"`
+}
+
+// SearchChunksInput defines the input parameters for the search_chunks tool.
+// Query is required (no omitempty); the rest are optional.
+type SearchChunksInput struct {
+	// Query is the search query.
+	Query string `json:"query" jsonschema:"The search query for finding relevant documentation passages"`
+	// MaxResults is the maximum number of chunks to return (1-50, default 10).
+	MaxResults int `json:"max_results,omitempty" jsonschema:"Maximum number of passages to return (1-50, default 10)"`
+	// MaxTokens caps the total size of returned passages (default 2000, ~4 characters per token).
+	MaxTokens int `json:"max_tokens,omitempty" jsonschema:"Approximate token budget for all returned passages combined (default 2000)"`
+	// MinScore is the minimum relevance threshold (0-1, default 0.3).
+	MinScore float64 `json:"min_score,omitempty" jsonschema:"Minimum semantic similarity for dense matches (0-1, default 0.3)"`
+	// Mode selects dense (embedding), sparse (BM25 keyword) or hybrid retrieval.
+	Mode string `json:"mode,omitempty" jsonschema:"Search mode: dense (semantic), sparse (exact keywords/identifiers) or hybrid (both fused, default)"`
+	// MMRLambda trades relevance against diversity (0-1, default 0.7, 1 disables MMR).
+	MMRLambda float64 `json:"mmr_lambda,omitempty" jsonschema:"Relevance vs. diversity trade-off for maximal marginal relevance re-ranking (0-1, default 0.7; lower favours passages unlike each other, 1 keeps pure relevance order)"`
+	// ExcludeSymbols returns documentation passages only, without Go API symbols.
+	ExcludeSymbols bool `json:"exclude_symbols,omitempty" jsonschema:"Return documentation passages only; by default matching Go API symbols are returned alongside them with their signature and doc comment as content"`
+	// Source restricts the search to one documentation source.
+	Source string `json:"source,omitempty" jsonschema:"Only search this documentation source (e.g. eino); list_docs returns the available sources. Omit to search them all"`
+}
+
+// SearchChunksOutput contains the matched passages.
+type SearchChunksOutput struct {
+	// Chunks is the list of matching passages, best first.
+	Chunks []ChunkResult `json:"chunks"`
+	// TotalChars is the combined length of all returned passage content.
+	TotalChars int `json:"total_chars"`
+	// Truncated indicates the budget cut off further passages or shortened the last one.
+	Truncated bool `json:"truncated"`
+	// Message provides informational context (e.g., "No matching passages found").
+	Message string `json:"message,omitempty"`
+}
+
+// ChunkResult represents a single matched passage.
+type ChunkResult struct {
+	// Path is the document path the passage belongs to; empty for symbols.
+	Path string `json:"path,omitempty"`
+	// Source is the documentation source of the document; empty for symbols.
+	Source string `json:"source,omitempty"`
+	// HeaderPath is the section hierarchy (e.g., "# Guide > ## Setup").
+	HeaderPath string `json:"header_path"`
+	// ChunkIndex is the passage's position within the document.
+	ChunkIndex int `json:"chunk_index"`
+	// Score is the relevance score (0-1); its meaning depends on the search mode.
+	Score float64 `json:"score"`
+	// Content is the passage markdown, without the header path prefix. For a symbol it
+	// is the signature and doc comment, and HeaderPath is the qualified name.
+	Content string `json:"content"`
+	// Symbol is the matching declaration when the passage is a Go API symbol.
+	Symbol *SymbolResult `json:"symbol,omitempty"`
 }
 
 // FetchDocInput defines the input parameters for the fetch_doc tool.
 // Path is required (no omitempty).
 type FetchDocInput struct {
-	// Path is the document path to retrieve.
-	Path string `json:"path" jsonschema:"The document path to retrieve (e.g. getting-started/installation.md)"`
+	// Path is the document path to retrieve, or a Hugo alias of it.
+	Path string `json:"path" jsonschema:"The document path to retrieve (e.g. getting-started/installation.md) or one of its Hugo aliases"`
+	// Source restricts the lookup to one documentation source.
+	Source string `json:"source,omitempty" jsonschema:"Documentation source to look the document up in (e.g. eino); omit to try every source in order"`
 }
 
 // FetchDocOutput contains the retrieved document.
@@ -49,45 +139,329 @@ type FetchDocOutput struct {
 	Content string `json:"content"`
 	// Path is the document path.
 	Path string `json:"path"`
+	// Source is the documentation source the document belongs to.
+	Source string `json:"source,omitempty"`
 	// Summary is the LLM-generated document summary.
 	Summary string `json:"summary"`
+	// Title is the page title from the document's front matter.
+	Title string `json:"title,omitempty"`
+	// Description is the page description from the document's front matter.
+	Description string `json:"description,omitempty"`
+	// Weight is the Hugo ordering weight within the document's section.
+	Weight int `json:"weight,omitempty"`
+	// Date is the page date from the document's front matter.
+	Date *time.Time `json:"date,omitempty"`
+	// Aliases lists alternate URL paths that resolve to this document.
+	Aliases []string `json:"aliases,omitempty"`
+	// SourceURL is a permalink to the markdown source at the indexed commit.
+	SourceURL string `json:"source_url,omitempty"`
+	// PageURL is the published page on cloudwego.io (Eino User Manual only).
+	PageURL string `json:"page_url,omitempty"`
+	// CommitSHA is the commit the document was indexed from.
+	CommitSHA string `json:"commit_sha,omitempty"`
 	// UpdatedAt is when the document was indexed.
 	UpdatedAt time.Time `json:"updated_at"`
 	// Found indicates whether the document exists.
 	Found bool `json:"found"`
+	// Suggestions lists the closest existing paths when the document was not found.
+	Suggestions []PathSuggestion `json:"suggestions,omitempty"`
+	// Message provides informational context (e.g., "Document not found. Did you mean ...?").
+	Message string `json:"message,omitempty"`
+}
+
+// FetchSectionInput defines the input parameters for the fetch_section tool.
+// Path is required (no omitempty); without Section the table of contents is returned.
+type FetchSectionInput struct {
+	// Path is the document path to read, or a Hugo alias of it.
+	Path string `json:"path" jsonschema:"The document path (e.g. core/graph.md) or one of its Hugo aliases"`
+	// Section selects a heading by header path or anchor ID.
+	Section string `json:"section,omitempty" jsonschema:"Heading to return: a header path such as 'Compile' or 'Graph > Compile', or an anchor ID such as '#compile'. Omit to get the table of contents"`
+	// Context is the number of neighbouring chunks to include on each side (0-5, default 0).
+	Context int `json:"context,omitempty" jsonschema:"Number of neighbouring chunks to include before and after the section for context (0-5, default 0)"`
+	// TOC requests the heading tree instead of section content.
+	TOC bool `json:"toc,omitempty" jsonschema:"Return the document's heading tree with the chunk index of each heading instead of content"`
+	// Source restricts the lookup to one documentation source.
+	Source string `json:"source,omitempty" jsonschema:"Documentation source to look the document up in (e.g. eino); omit to try every source in order"`
+}
+
+// FetchSectionOutput contains the requested section or the document's table of contents.
+type FetchSectionOutput struct {
+	// Path is the document path.
+	Path string `json:"path"`
+	// Source is the documentation source the document belongs to.
+	Source string `json:"source,omitempty"`
+	// Found indicates whether the requested section exists.
+	Found bool `json:"found"`
+	// HeaderPath is the matched section's hierarchy (e.g., "# Guide > ## Setup").
+	HeaderPath string `json:"header_path,omitempty"`
+	// Anchor is the matched section's anchor ID.
+	Anchor string `json:"anchor,omitempty"`
+	// Content is the section markdown, including subsections, with source header prepended.
+	Content string `json:"content,omitempty"`
+	// ChunkStart is the index of the first chunk covering the section (-1 if none).
+	ChunkStart int `json:"chunk_start"`
+	// ChunkEnd is the index of the last chunk covering the section (-1 if none).
+	ChunkEnd int `json:"chunk_end"`
+	// Before lists the chunks preceding the section when context was requested.
+	Before []ChunkResult `json:"before,omitempty"`
+	// After lists the chunks following the section when context was requested.
+	After []ChunkResult `json:"after,omitempty"`
+	// TOC is the heading tree, in document order, in table-of-contents mode or when
+	// the section was not found.
+	TOC []TOCEntry `json:"toc,omitempty"`
+	// Suggestions lists the closest existing paths when the document was not found.
+	Suggestions []PathSuggestion `json:"suggestions,omitempty"`
+	// Message provides informational context (e.g., "Document not found").
+	Message string `json:"message,omitempty"`
+}
+
+// TOCEntry is a heading in a document's table of contents. Entries are listed in
+// document order; Depth gives their nesting in the heading tree.
+type TOCEntry struct {
+	// Level is the markdown heading level (1-6).
+	Level int `json:"level"`
+	// Depth is the nesting depth in the heading tree (1 for top-level headings).
+	Depth int `json:"depth"`
+	// Title is the heading text.
+	Title string `json:"title"`
+	// Anchor is the heading's anchor ID, usable as fetch_section's section.
+	Anchor string `json:"anchor"`
+	// HeaderPath is the heading's hierarchy (e.g., "# Guide > ## Setup").
+	HeaderPath string `json:"header_path"`
+	// ChunkIndex is the chunk the heading falls in (-1 if not indexed).
+	ChunkIndex int `json:"chunk_index"`
+}
+
+// PathSuggestion is an existing document path offered when fetch_doc misses.
+type PathSuggestion struct {
+	// Path is the suggested document path.
+	Path string `json:"path"`
+	// Source is the documentation source the path belongs to.
+	Source string `json:"source,omitempty"`
+	// Score is the match strength (0-1).
+	Score float64 `json:"score"`
+	// Reason is why the path was suggested: similar_path, alias or semantic.
+	Reason string `json:"reason"`
+}
+
+// RelatedDocsInput defines the input parameters for the related_docs tool.
+// Path is required (no omitempty).
+type RelatedDocsInput struct {
+	// Path is the document to find neighbours for, or a Hugo alias of it.
+	Path string `json:"path" jsonschema:"The document path (e.g. core/graph.md) or one of its Hugo aliases"`
+	// MaxResults is the maximum number of related documents to return (1-20, default 5).
+	MaxResults int `json:"max_results,omitempty" jsonschema:"Maximum number of related documents to return (1-20, default 5)"`
+	// Source restricts the lookup and the semantic neighbours to one documentation source.
+	Source string `json:"source,omitempty" jsonschema:"Documentation source to look the document up in and take related documents from (e.g. eino); omit to try every source"`
+}
+
+// RelatedDocsOutput contains the documents related to the requested one.
+type RelatedDocsOutput struct {
+	// Path is the document path the neighbours were computed for.
+	Path string `json:"path"`
+	// Source is the documentation source the document belongs to.
+	Source string `json:"source,omitempty"`
+	// Found indicates whether the document exists.
+	Found bool `json:"found"`
+	// Related is the list of neighbouring documents, best first.
+	Related []RelatedDoc `json:"related"`
+	// Suggestions lists the closest existing paths when the document was not found.
+	Suggestions []PathSuggestion `json:"suggestions,omitempty"`
+	// Message provides informational context (e.g., "No related documents found").
+	Message string `json:"message,omitempty"`
+}
+
+// RelatedDoc is a document related to the one requested from related_docs.
+type RelatedDoc struct {
+	// Path is the related document path.
+	Path string `json:"path"`
+	// Source is the documentation source the related document belongs to.
+	Source string `json:"source,omitempty"`
+	// Title is the page title from the document's front matter.
+	Title string `json:"title,omitempty"`
+	// Summary is the LLM-generated document summary.
+	Summary string `json:"summary"`
+	// Score is the relatedness (0-1): centroid similarity plus a boost per link direction.
+	Score float64 `json:"score"`
+	// Reason is the strongest evidence: links-to, linked-from or semantic.
+	Reason string `json:"reason"`
+}
+
+// LookupSymbolInput defines the input parameters for the lookup_symbol tool.
+// Name is required (no omitempty).
+type LookupSymbolInput struct {
+	// Name is the symbol to look up: bare, package-qualified or a method.
+	Name string `json:"name" jsonschema:"Go symbol to look up, e.g. NewGraph, compose.NewGraph, Graph.Compile or (*Graph).Compile"`
+	// MaxMentions is the maximum number of documentation pages to return (1-50, default 10).
+	MaxMentions int `json:"max_mentions,omitempty" jsonschema:"Maximum number of documentation pages mentioning the symbol to return (1-50, default 10)"`
+	// Source restricts the mentions to one documentation source.
+	Source string `json:"source,omitempty" jsonschema:"Only return pages of this documentation source (e.g. eino); omit to search them all"`
+}
+
+// LookupSymbolOutput contains the matching symbols and the pages mentioning them.
+type LookupSymbolOutput struct {
+	// Name is the requested name.
+	Name string `json:"name"`
+	// Found reports whether any symbol matched.
+	Found bool `json:"found"`
+	// Symbols lists the matching declarations; a bare method name can match several.
+	Symbols []SymbolResult `json:"symbols"`
+	// MentionedIn lists documentation pages that mention the symbols, best match first.
+	MentionedIn []SymbolMention `json:"mentioned_in"`
+	// Suggestions lists similar symbol names when nothing matched.
+	Suggestions []string `json:"suggestions,omitempty"`
+	// Message provides informational context (e.g., "Symbol not found").
+	Message string `json:"message,omitempty"`
+}
+
+// SymbolResult is one exported Go declaration.
+type SymbolResult struct {
+	// Name is the package-qualified name, e.g. "compose.NewGraph" or "compose.Graph.Compile".
+	Name string `json:"name"`
+	// Kind is type, interface, func or method.
+	Kind string `json:"kind"`
+	// Package is the import path.
+	Package string `json:"package"`
+	// Signature is the exact declaration, without body.
+	Signature string `json:"signature"`
+	// Doc is the doc comment.
+	Doc string `json:"doc,omitempty"`
+	// File is the source file relative to the module root.
+	File string `json:"file"`
+	// Line is the line of the declaration in File.
+	Line int `json:"line"`
+	// URL is the symbol's pkg.go.dev reference page.
+	URL string `json:"url"`
+}
+
+// SymbolMention is a documentation page mentioning a symbol.
+type SymbolMention struct {
+	// Path is the document path.
+	Path string `json:"path"`
+	// Source is the documentation source the document belongs to.
+	Source string `json:"source,omitempty"`
+	// Title is the page title from the document's front matter.
+	Title string `json:"title,omitempty"`
+	// HeaderPath is the section of the best-matching mention.
+	HeaderPath string `json:"header_path,omitempty"`
+	// Symbol is the qualified name of the symbol mentioned.
+	Symbol string `json:"symbol"`
 }
 
 // ListDocsInput defines the input parameters for the list_docs tool.
-// This tool takes no parameters and lists all available documents.
+// Without a source, the documents of every source are listed.
 type ListDocsInput struct {
-	// No input parameters required
+	// Source restricts the listing to one documentation source.
+	Source string `json:"source,omitempty" jsonschema:"Only list documents of this documentation source (e.g. eino); omit to list every source"`
 }
 
 // ListDocsOutput contains the list of all available document paths.
 type ListDocsOutput struct {
-	// Paths is all available document paths.
+	// Paths is all available document paths, sorted; a path indexed in several
+	// sources is listed once.
 	Paths []string `json:"paths"`
 	// Count is the total number of documents.
 	Count int `json:"count"`
+	// Categories lists the top-level directories, usable as search_docs categories.
+	Categories []string `json:"categories"`
+	// Sources lists the documentation sources listed, usable as the source argument.
+	Sources []SourceSummary `json:"sources"`
 }
 
-// StatusInput defines input for get_index_status tool (no parameters required)
-type StatusInput struct{}
+// SourceSummary describes a documentation source in list_docs.
+type SourceSummary struct {
+	// Name identifies the source in the source argument of every tool.
+	Name string `json:"name"`
+	// Repository is the GitHub repository the docs are read from (owner/repo).
+	Repository string `json:"repository"`
+	// Count is the number of documents indexed from the source.
+	Count int `json:"count"`
+}
+
+// StatusInput defines input for get_index_status tool
+type StatusInput struct {
+	// Source restricts the status to one documentation source
+	Source string `json:"source,omitempty" jsonschema:"Only report this documentation source (e.g. eino); omit to report every source"`
+}
 
 // StatusOutput contains index status information
 type StatusOutput struct {
-	// TotalDocs is the count of indexed documents
+	// TotalDocs is the count of indexed documents across the sources reported
 	TotalDocs int `json:"total_docs"`
-	// TotalChunks is the count of document chunks
+	// TotalChunks is the count of document chunks in the whole index
 	TotalChunks int `json:"total_chunks"`
-	// IndexedPaths lists all document paths in the index
+	// TotalSymbols is the count of indexed Go API symbols (see lookup_symbol)
+	TotalSymbols int `json:"total_symbols"`
+	// IndexedPaths lists all document paths of the sources reported, sorted and distinct
 	IndexedPaths []string `json:"indexed_paths"`
-	// LastSyncTime is when the index was last updated (RFC3339)
+	// LastSyncTime is when any source reported was last updated (RFC3339)
 	LastSyncTime string `json:"last_sync_time"`
-	// SourceCommit is the GitHub commit SHA of indexed content
+	// SourceCommit is the GitHub commit SHA of the first source reported
 	SourceCommit string `json:"source_commit"`
-	// CommitsBehind shows how many commits the index is behind GitHub HEAD (null if check failed)
+	// CommitsBehind shows how many commits the first source reported is behind its
+	// branch HEAD (null if check failed)
 	CommitsBehind *int `json:"commits_behind"`
-	// StaleWarning is set when index is >20 commits behind
+	// StaleWarning is set when any source reported is >20 commits behind
 	StaleWarning string `json:"stale_warning,omitempty"`
+	// Sources reports each documentation source, in configured order
+	Sources []SourceStatus `json:"sources"`
+	// AutoSync reports the background sync scheduler; omitted when it is disabled
+	AutoSync *AutoSyncStatus `json:"auto_sync,omitempty"`
+}
+
+// SourceStatus reports the index of one documentation source.
+type SourceStatus struct {
+	// Name identifies the source in the source argument of every tool
+	Name string `json:"name"`
+	// Repository is the GitHub repository the docs are read from (owner/repo)
+	Repository string `json:"repository"`
+	// Ref is the branch indexed
+	Ref string `json:"ref"`
+	// BasePath is the docs directory within the repository
+	BasePath string `json:"base_path"`
+	// TotalDocs is the count of documents indexed from the source
+	TotalDocs int `json:"total_docs"`
+	// LastSyncTime is when the source was last updated (RFC3339)
+	LastSyncTime string `json:"last_sync_time"`
+	// SourceCommit is the GitHub commit SHA of the source's indexed content
+	SourceCommit string `json:"source_commit"`
+	// CommitsBehind shows how many commits the source is behind its branch HEAD (null if check failed)
+	CommitsBehind *int `json:"commits_behind"`
+	// StaleWarning is set when the source is >20 commits behind
+	StaleWarning string `json:"stale_warning,omitempty"`
+}
+
+// AutoSyncStatus reports the background sync scheduler.
+type AutoSyncStatus struct {
+	// Interval is how often the upstream commit is polled (e.g. "1h0m0s"); omitted when
+	// only webhook pushes trigger syncs
+	Interval string `json:"interval,omitempty"`
+	// Running is true while a sync is in progress
+	Running bool `json:"running"`
+	// LastCheck is when the upstream commit was last polled
+	LastCheck *time.Time `json:"last_check,omitempty"`
+	// NextCheck is when the next poll, and any sync it triggers, is scheduled
+	NextCheck *time.Time `json:"next_check,omitempty"`
+	// LastRun is the most recent sync attempt, omitted until one has run
+	LastRun *SyncRun `json:"last_run,omitempty"`
+}
+
+// SyncRun is the result of one automatic sync, polled or triggered by a webhook.
+type SyncRun struct {
+	// StartedAt is when the sync started
+	StartedAt time.Time `json:"started_at"`
+	// FinishedAt is when the sync finished
+	FinishedAt time.Time `json:"finished_at"`
+	// Outcome is success, partial (some documents failed) or failed
+	Outcome string `json:"outcome"`
+	// Commit is the commit indexed; empty when the sync failed
+	Commit string `json:"commit,omitempty"`
+	// Error explains a failed or partial sync
+	Error string `json:"error,omitempty"`
+	// Indexed is the number of documents added or changed
+	Indexed int `json:"indexed"`
+	// Deleted is the number of documents removed because they no longer exist upstream
+	Deleted int `json:"deleted"`
+	// Failed is the number of documents that could not be indexed
+	Failed int `json:"failed"`
 }
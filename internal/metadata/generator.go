@@ -18,9 +18,10 @@ type DocumentMetadata struct {
 	Entities []string `json:"entities"`
 }
 
-// Generator produces metadata using GPT-4o.
+// Generator produces metadata using GPT-4o (or the chat model set with WithModel).
 type Generator struct {
 	client    *openai.Client
+	model     string
 	maxTokens int
 }
 
@@ -33,10 +34,22 @@ func NewGenerator(client *openai.Client, maxTokens ...int) *Generator {
 	}
 	return &Generator{
 		client:    client,
+		model:     openai.ChatModelGPT4o,
 		maxTokens: max,
 	}
 }
 
+// WithModel returns a copy of the generator that uses the given chat model, e.g. a
+// local model served by the same OpenAI-compatible endpoint as the embeddings.
+// An empty model keeps the current one.
+func (g *Generator) WithModel(model string) *Generator {
+	c := *g
+	if model != "" {
+		c.model = model
+	}
+	return &c
+}
+
 // GenerateMetadata analyzes document content and produces a summary and entity list.
 func (g *Generator) GenerateMetadata(ctx context.Context, path, content string) (*DocumentMetadata, error) {
 	// Truncate if too long
@@ -64,7 +77,7 @@ Focus on EINO-specific concepts like:
 		Messages: []openai.ChatCompletionMessageParamUnion{
 			openai.UserMessage(prompt),
 		},
-		Model: openai.ChatModelGPT4o,
+		Model: g.model,
 		ResponseFormat: openai.ChatCompletionNewParamsResponseFormatUnion{
 			OfJSONObject: &openai.ResponseFormatJSONObjectParam{
 				Type: "json_object",
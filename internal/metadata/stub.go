@@ -0,0 +1,43 @@
+package metadata
+
+import (
+	"context"
+	"strings"
+)
+
+// StubGenerator returns canned metadata without calling an LLM, for tests and
+// offline runs. Documents listed in Responses get their canned metadata; all others
+// get a summary taken from the first heading of the document and no entities.
+type StubGenerator struct {
+	Responses map[string]DocumentMetadata // Canned metadata by document path
+	Err       error                       // If set, returned for every document
+}
+
+// GenerateMetadata returns the canned metadata for path.
+func (g *StubGenerator) GenerateMetadata(ctx context.Context, path, content string) (*DocumentMetadata, error) {
+	if g.Err != nil {
+		return nil, g.Err
+	}
+
+	if canned, ok := g.Responses[path]; ok {
+		return &DocumentMetadata{
+			Summary:  canned.Summary,
+			Entities: append([]string{}, canned.Entities...),
+		}, nil
+	}
+
+	return &DocumentMetadata{
+		Summary:  firstHeading(content),
+		Entities: []string{},
+	}, nil
+}
+
+// firstHeading returns the text of the first markdown heading, or "" if there is none.
+func firstHeading(content string) string {
+	for _, line := range strings.Split(content, "\n") {
+		if strings.HasPrefix(line, "#") {
+			return strings.TrimSpace(strings.TrimLeft(line, "#"))
+		}
+	}
+	return ""
+}
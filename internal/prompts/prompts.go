@@ -0,0 +1,203 @@
+// Package prompts loads the MCP prompt library from markdown template files.
+//
+// Each template is a markdown file with YAML front matter describing the prompt and
+// its arguments, followed by a text/template body:
+//
+//	---
+//	name: explain-graph-error
+//	title: Explain a compose graph error
+//	description: Diagnose an error returned while building or running a graph
+//	arguments:
+//	  - name: error
+//	    description: The error message
+//	    required: true
+//	  - name: doc
+//	    description: A document to include
+//	    complete: path
+//	search: "compose graph compile {{.error}}"
+//	---
+//	Explain this error: {{.Args.error}}
+//	{{range .Docs}}...{{end}}
+//
+// The search template is rendered with the arguments and used to retrieve Docs from
+// the index; the body is rendered with Args and Docs to produce the prompt text.
+package prompts
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/mike-a-ellis/eino-docs-mcp/internal/markdown"
+	templates "github.com/mike-a-ellis/eino-docs-mcp/prompts"
+)
+
+// DefaultMaxDocs is the number of documents retrieved for a prompt that does not set max_docs.
+const DefaultMaxDocs = 3
+
+// Argument completion kinds, set with "complete:" on an argument.
+const (
+	// CompletePath completes against indexed document paths.
+	CompletePath = "path"
+	// CompleteEntity completes against the entity names extracted from documents.
+	CompleteEntity = "entity"
+)
+
+// Argument describes a prompt argument.
+type Argument struct {
+	Name        string `yaml:"name"`
+	Description string `yaml:"description"`
+	Required    bool   `yaml:"required"`
+	Complete    string `yaml:"complete"` // CompletePath, CompleteEntity or "" for none
+}
+
+// Doc is an indexed document made available to a prompt body.
+type Doc struct {
+	Path     string
+	Title    string
+	Summary  string
+	Entities []string
+	Content  string // Markdown without front matter
+}
+
+// Prompt is a parsed prompt template.
+type Prompt struct {
+	Name        string     `yaml:"name"`
+	Title       string     `yaml:"title"`
+	Description string     `yaml:"description"`
+	Arguments   []Argument `yaml:"arguments"`
+	Search      string     `yaml:"search"`   // Search query template
+	MaxDocs     int        `yaml:"max_docs"` // Documents to retrieve (default DefaultMaxDocs)
+
+	query *template.Template
+	body  *template.Template
+}
+
+// funcs are available to every template.
+var funcs = template.FuncMap{
+	// truncate shortens s to at most n bytes, cutting at a line break when possible.
+	"truncate": func(n int, s string) string {
+		if len(s) <= n {
+			return s
+		}
+		cut := s[:n]
+		if i := strings.LastIndexByte(cut, '\n'); i > n/2 {
+			cut = cut[:i]
+		}
+		return strings.ToValidUTF8(cut, "") + "\n..."
+	},
+}
+
+// Parse parses a prompt template. name is used when the front matter sets none.
+func Parse(name string, source []byte) (*Prompt, error) {
+	p := &Prompt{Name: name}
+	body, err := markdown.DecodeFrontMatter(source, p)
+	if err != nil {
+		return nil, fmt.Errorf("prompt %s: %w", name, err)
+	}
+	if p.Name == "" {
+		return nil, fmt.Errorf("prompt %s: missing name", name)
+	}
+	if p.MaxDocs <= 0 {
+		p.MaxDocs = DefaultMaxDocs
+	}
+	for _, arg := range p.Arguments {
+		switch arg.Complete {
+		case "", CompletePath, CompleteEntity:
+		default:
+			return nil, fmt.Errorf("prompt %s: argument %s: unknown completion %q", p.Name, arg.Name, arg.Complete)
+		}
+	}
+
+	if p.query, err = template.New(p.Name + ".search").Funcs(funcs).Parse(p.Search); err != nil {
+		return nil, fmt.Errorf("prompt %s: search: %w", p.Name, err)
+	}
+	if p.body, err = template.New(p.Name).Funcs(funcs).Parse(string(body)); err != nil {
+		return nil, fmt.Errorf("prompt %s: body: %w", p.Name, err)
+	}
+	return p, nil
+}
+
+// Load returns the built-in prompts (the repository's prompts/ directory) plus any *.md
+// templates in dir. A template in dir replaces the built-in prompt with the same name.
+// An empty dir loads only the built-ins. Prompts are sorted by name.
+func Load(dir string) ([]*Prompt, error) {
+	byName := make(map[string]*Prompt)
+
+	if err := loadFS(templates.Templates, byName); err != nil {
+		return nil, err
+	}
+	if dir != "" {
+		if err := loadFS(os.DirFS(dir), byName); err != nil {
+			return nil, fmt.Errorf("load prompts from %s: %w", dir, err)
+		}
+	}
+
+	prompts := make([]*Prompt, 0, len(byName))
+	for _, p := range byName {
+		prompts = append(prompts, p)
+	}
+	sort.Slice(prompts, func(i, j int) bool { return prompts[i].Name < prompts[j].Name })
+	return prompts, nil
+}
+
+// loadFS parses every *.md file at the root of fsys into byName.
+func loadFS(fsys fs.FS, byName map[string]*Prompt) error {
+	files, err := fs.Glob(fsys, "*.md")
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		source, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return err
+		}
+		p, err := Parse(strings.TrimSuffix(filepath.Base(file), ".md"), source)
+		if err != nil {
+			return err
+		}
+		byName[p.Name] = p
+	}
+	return nil
+}
+
+// Args validates args against the declared arguments and returns them with every
+// declared argument present (missing optional ones are empty).
+func (p *Prompt) Args(args map[string]string) (map[string]string, error) {
+	full := make(map[string]string, len(p.Arguments))
+	for _, arg := range p.Arguments {
+		value := strings.TrimSpace(args[arg.Name])
+		if arg.Required && value == "" {
+			return nil, fmt.Errorf("prompt %s: missing required argument %q", p.Name, arg.Name)
+		}
+		full[arg.Name] = value
+	}
+	return full, nil
+}
+
+// Query renders the search query for args (as returned by Args).
+func (p *Prompt) Query(args map[string]string) (string, error) {
+	var buf bytes.Buffer
+	if err := p.query.Execute(&buf, args); err != nil {
+		return "", fmt.Errorf("prompt %s: search: %w", p.Name, err)
+	}
+	return strings.Join(strings.Fields(buf.String()), " "), nil
+}
+
+// Render renders the prompt text for args (as returned by Args) and the retrieved docs.
+func (p *Prompt) Render(args map[string]string, docs []Doc) (string, error) {
+	var buf bytes.Buffer
+	data := struct {
+		Args map[string]string
+		Docs []Doc
+	}{Args: args, Docs: docs}
+	if err := p.body.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("prompt %s: %w", p.Name, err)
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
@@ -0,0 +1,114 @@
+package prompts
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestLoad_Builtin verifies that every built-in template parses and renders.
+func TestLoad_Builtin(t *testing.T) {
+	library, err := Load("")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	names := make([]string, len(library))
+	for i, p := range library {
+		names[i] = p.Name
+	}
+	want := []string{"build-chatmodel-agent", "explain-graph-error", "migrate-callback-api"}
+	if strings.Join(names, ",") != strings.Join(want, ",") {
+		t.Fatalf("expected prompts %v, got %v", want, names)
+	}
+
+	docs := []Doc{{Path: "core/graph.md", Title: "Graph", Summary: "Graphs", Content: "# Graph\n\nUse compose.NewGraph."}}
+	for _, p := range library {
+		args := make(map[string]string)
+		for _, arg := range p.Arguments {
+			args[arg.Name] = "value-" + arg.Name
+		}
+		args, err := p.Args(args)
+		if err != nil {
+			t.Fatalf("%s: Args failed: %v", p.Name, err)
+		}
+
+		query, err := p.Query(args)
+		if err != nil || query == "" {
+			t.Errorf("%s: Query() = %q, %v", p.Name, query, err)
+		}
+		text, err := p.Render(args, docs)
+		if err != nil {
+			t.Fatalf("%s: Render failed: %v", p.Name, err)
+		}
+		if !strings.Contains(text, "Source: core/graph.md") || !strings.Contains(text, "compose.NewGraph") {
+			t.Errorf("%s: rendered prompt is missing the document:\n%s", p.Name, text)
+		}
+		if strings.Contains(text, "<no value>") {
+			t.Errorf("%s: rendered prompt references an unknown field:\n%s", p.Name, text)
+		}
+	}
+}
+
+// TestLoad_Override verifies that templates in a directory replace and extend the built-ins.
+func TestLoad_Override(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("explain-graph-error.md", "---\ntitle: Custom\nsearch: graph\n---\nCustom body")
+	write("team-onboarding.md", "---\ndescription: Onboarding\n---\nWelcome {{.Args.name}}")
+	write("notes.txt", "ignored")
+
+	library, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(library) != 4 {
+		t.Fatalf("expected 4 prompts, got %d", len(library))
+	}
+
+	byName := make(map[string]*Prompt)
+	for _, p := range library {
+		byName[p.Name] = p
+	}
+	if got := byName["explain-graph-error"].Title; got != "Custom" {
+		t.Errorf("expected the override to replace the built-in, got title %q", got)
+	}
+	onboarding := byName["team-onboarding"]
+	if onboarding == nil || onboarding.MaxDocs != DefaultMaxDocs {
+		t.Fatalf("expected team-onboarding named after its file with default max_docs, got %+v", onboarding)
+	}
+
+	write("broken.md", "---\nname: broken\n---\n{{.Args.name")
+	if _, err := Load(dir); err == nil {
+		t.Error("expected an error for a malformed template")
+	}
+}
+
+// TestPrompt_Args verifies required argument validation and query normalization.
+func TestPrompt_Args(t *testing.T) {
+	p, err := Parse("test", []byte("---\narguments:\n  - name: error\n    required: true\n  - name: code\nsearch: |\n  graph\n  {{.error}}  {{.code}}\n---\nbody"))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	if _, err := p.Args(map[string]string{"code": "x"}); err == nil {
+		t.Error("expected an error for a missing required argument")
+	}
+
+	args, err := p.Args(map[string]string{"error": " type mismatch "})
+	if err != nil {
+		t.Fatalf("Args failed: %v", err)
+	}
+	query, err := p.Query(args)
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	if query != "graph type mismatch" {
+		t.Errorf("Query() = %q, want %q", query, "graph type mismatch")
+	}
+}
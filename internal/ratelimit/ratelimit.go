@@ -0,0 +1,124 @@
+// Package ratelimit shares rate-limit backpressure between concurrent API callers.
+//
+// When one request is rate limited, every request made through the same Gate waits
+// until the server's retry window has passed instead of piling more requests onto a
+// provider that is already refusing them.
+package ratelimit
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// DefaultPause is how long callers wait after a rate-limited response that does not
+// say when to retry.
+const DefaultPause = 5 * time.Second
+
+// MaxPause caps the pause taken from a response, so a bogus header cannot stall a sync.
+const MaxPause = 5 * time.Minute
+
+// Gate blocks callers while a rate-limit pause is in effect.
+// The zero value is ready to use and never blocks until Pause is called.
+type Gate struct {
+	mu    sync.Mutex
+	until time.Time
+}
+
+// Pause makes Wait block for at least d. Overlapping pauses extend, never shorten,
+// the current one.
+func (g *Gate) Pause(d time.Duration) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if until := time.Now().Add(d); until.After(g.until) {
+		g.until = until
+	}
+}
+
+// Wait blocks until no pause is in effect or ctx is done.
+func (g *Gate) Wait(ctx context.Context) error {
+	for {
+		g.mu.Lock()
+		remaining := time.Until(g.until)
+		g.mu.Unlock()
+
+		if remaining <= 0 {
+			return ctx.Err()
+		}
+
+		timer := time.NewTimer(remaining)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+			// Re-check: another caller may have extended the pause meanwhile
+		}
+	}
+}
+
+// Transport is an http.RoundTripper that waits on a Gate before every request and
+// pauses the gate when a response reports a rate limit.
+type Transport struct {
+	Base http.RoundTripper // Underlying transport (http.DefaultTransport if nil)
+	Gate *Gate
+}
+
+// NewTransport wraps base so all requests through it share gate's backpressure.
+func NewTransport(base http.RoundTripper, gate *Gate) *Transport {
+	return &Transport{Base: base, Gate: gate}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.Gate.Wait(req.Context()); err != nil {
+		return nil, err
+	}
+
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	resp, err := base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if d, limited := retryAfter(resp, time.Now()); limited {
+		t.Gate.Pause(d)
+	}
+	return resp, nil
+}
+
+// retryAfter reports whether resp is a rate-limit response and how long to back off.
+// It understands HTTP 429 with Retry-After (seconds or HTTP date), and GitHub's 403
+// responses with Retry-After or an exhausted X-RateLimit-Remaining and its reset time.
+func retryAfter(resp *http.Response, now time.Time) (time.Duration, bool) {
+	header := resp.Header
+	exhausted := header.Get("X-RateLimit-Remaining") == "0"
+
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+	case resp.StatusCode == http.StatusForbidden && (header.Get("Retry-After") != "" || exhausted):
+	default:
+		return 0, false
+	}
+
+	d := DefaultPause
+	if v := header.Get("Retry-After"); v != "" {
+		if seconds, err := strconv.Atoi(v); err == nil {
+			d = time.Duration(seconds) * time.Second
+		} else if at, err := http.ParseTime(v); err == nil {
+			d = at.Sub(now)
+		}
+	} else if exhausted {
+		if reset, err := strconv.ParseInt(header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+			d = time.Unix(reset, 0).Sub(now)
+		}
+	}
+
+	return min(max(d, 0), MaxPause), true
+}
@@ -0,0 +1,111 @@
+package ratelimit
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestGate_Wait verifies that Wait blocks for the pause and honours cancellation.
+func TestGate_Wait(t *testing.T) {
+	var gate Gate
+	if err := gate.Wait(context.Background()); err != nil {
+		t.Fatalf("zero gate should not block: %v", err)
+	}
+
+	gate.Pause(50 * time.Millisecond)
+	gate.Pause(10 * time.Millisecond) // Shorter pauses do not shorten the current one
+	start := time.Now()
+	if err := gate.Wait(context.Background()); err != nil {
+		t.Fatalf("Wait failed: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 40*time.Millisecond {
+		t.Errorf("Wait returned after %s, expected about 50ms", elapsed)
+	}
+
+	gate.Pause(time.Hour)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := gate.Wait(ctx); err != context.Canceled {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+// TestRetryAfter verifies which responses count as rate limited and the pause derived.
+func TestRetryAfter(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+
+	tests := []struct {
+		name    string
+		status  int
+		header  map[string]string
+		limited bool
+		want    time.Duration
+	}{
+		{"ok", 200, nil, false, 0},
+		{"429 without header", 429, nil, true, DefaultPause},
+		{"429 retry-after seconds", 429, map[string]string{"Retry-After": "3"}, true, 3 * time.Second},
+		{"429 retry-after date", 429, map[string]string{"Retry-After": now.Add(7 * time.Second).UTC().Format(http.TimeFormat)}, true, 7 * time.Second},
+		{"403 permission denied", 403, nil, false, 0},
+		{"403 secondary limit", 403, map[string]string{"Retry-After": "60"}, true, time.Minute},
+		{"403 primary limit", 403, map[string]string{
+			"X-RateLimit-Remaining": "0",
+			"X-RateLimit-Reset":     strconv.FormatInt(now.Add(30*time.Second).Unix(), 10),
+		}, true, 30 * time.Second},
+		{"capped", 429, map[string]string{"Retry-After": "86400"}, true, MaxPause},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{StatusCode: tt.status, Header: http.Header{}}
+			for k, v := range tt.header {
+				resp.Header.Set(k, v)
+			}
+			got, limited := retryAfter(resp, now)
+			if limited != tt.limited || got != tt.want {
+				t.Errorf("retryAfter() = %s, %v; want %s, %v", got, limited, tt.want, tt.limited)
+			}
+		})
+	}
+}
+
+// TestTransport_SharesBackpressure verifies that a 429 seen by one request delays
+// the next request made through the same gate.
+func TestTransport_SharesBackpressure(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	gate := &Gate{}
+	client := &http.Client{Transport: NewTransport(nil, gate)}
+
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("first request failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("expected 429, got %d", resp.StatusCode)
+	}
+
+	// A cancelled request must not get through while the gate is paused
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	if _, err := client.Do(req); err == nil {
+		t.Fatal("expected the second request to wait on the gate and time out")
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("expected 1 server call while paused, got %d", got)
+	}
+}
@@ -0,0 +1,140 @@
+package rerank
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// Request formats understood by HTTPReranker.
+const (
+	// FormatCohere sends {"model", "query", "documents"}, as Cohere and Jina expect.
+	FormatCohere = "cohere"
+	// FormatTEI sends {"query", "texts"}, as Hugging Face text-embeddings-inference expects.
+	FormatTEI = "tei"
+)
+
+// HTTPReranker calls a rerank endpoint. Responses may be Cohere/Jina style
+// ({"results": [{"index", "relevance_score"}]}) or TEI style ([{"index", "score"}]).
+type HTTPReranker struct {
+	url    string
+	apiKey string
+	model  string
+	format string
+	client *http.Client
+}
+
+// NewHTTPReranker creates a reranker for the endpoint at url. apiKey and model are
+// optional; format is FormatCohere (the default when empty) or FormatTEI.
+func NewHTTPReranker(url, apiKey, model, format string) *HTTPReranker {
+	if format == "" {
+		format = FormatCohere
+	}
+	return &HTTPReranker{
+		url:    url,
+		apiKey: apiKey,
+		model:  model,
+		format: format,
+		client: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// rerankRequest covers both request formats; unused fields are omitted.
+type rerankRequest struct {
+	Model     string   `json:"model,omitempty"`
+	Query     string   `json:"query"`
+	Documents []string `json:"documents,omitempty"`
+	Texts     []string `json:"texts,omitempty"`
+}
+
+// rerankResult is one scored document; Cohere and Jina call the score
+// relevance_score, TEI calls it score.
+type rerankResult struct {
+	Index          int      `json:"index"`
+	RelevanceScore *float64 `json:"relevance_score"`
+	Score          *float64 `json:"score"`
+}
+
+// Rerank posts the query and documents and maps the returned scores back to
+// document order. Documents the endpoint leaves out score 0.
+func (r *HTTPReranker) Rerank(ctx context.Context, query string, documents []string) ([]float64, error) {
+	if len(documents) == 0 {
+		return nil, nil
+	}
+
+	request := rerankRequest{Model: r.model, Query: query}
+	if r.format == FormatTEI {
+		request.Texts = documents
+	} else {
+		request.Documents = documents
+	}
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode rerank request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create rerank request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if r.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+r.apiKey)
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("rerank request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rerank response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("rerank endpoint returned %s: %s", resp.Status, bytes.TrimSpace(data))
+	}
+
+	results, err := parseResults(data)
+	if err != nil {
+		return nil, err
+	}
+
+	scores := make([]float64, len(documents))
+	for _, result := range results {
+		if result.Index < 0 || result.Index >= len(documents) {
+			return nil, fmt.Errorf("rerank endpoint returned index %d for %d documents", result.Index, len(documents))
+		}
+		switch {
+		case result.RelevanceScore != nil:
+			scores[result.Index] = *result.RelevanceScore
+		case result.Score != nil:
+			scores[result.Index] = *result.Score
+		}
+	}
+	return scores, nil
+}
+
+// parseResults decodes either response shape.
+func parseResults(data []byte) ([]rerankResult, error) {
+	var results []rerankResult
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("[")) {
+		if err := json.Unmarshal(data, &results); err != nil {
+			return nil, fmt.Errorf("failed to parse rerank response: %w", err)
+		}
+		return results, nil
+	}
+
+	var wrapped struct {
+		Results []rerankResult `json:"results"`
+	}
+	if err := json.Unmarshal(data, &wrapped); err != nil {
+		return nil, fmt.Errorf("failed to parse rerank response: %w", err)
+	}
+	return wrapped.Results, nil
+}
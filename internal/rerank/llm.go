@@ -0,0 +1,107 @@
+package rerank
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/openai/openai-go"
+)
+
+// DefaultLLMModel is the chat model used by LLMReranker when none is configured.
+const DefaultLLMModel = openai.ChatModelGPT4oMini
+
+const (
+	// llmBatchSize is the number of passages graded per chat request.
+	llmBatchSize = 10
+	// maxPassageChars truncates long passages; the opening of a chunk carries its topic.
+	maxPassageChars = 2000
+)
+
+// LLMReranker grades query/passage pairs with a chat model on a 0-10 scale.
+type LLMReranker struct {
+	client *openai.Client
+	model  string
+}
+
+// NewLLMReranker creates a reranker using the given chat model (DefaultLLMModel when empty).
+func NewLLMReranker(client *openai.Client, model string) *LLMReranker {
+	if model == "" {
+		model = DefaultLLMModel
+	}
+	return &LLMReranker{client: client, model: model}
+}
+
+// Rerank grades the documents in batches of llmBatchSize and scales the grades to 0-1.
+func (r *LLMReranker) Rerank(ctx context.Context, query string, documents []string) ([]float64, error) {
+	scores := make([]float64, 0, len(documents))
+	for start := 0; start < len(documents); start += llmBatchSize {
+		batch := documents[start:min(start+llmBatchSize, len(documents))]
+		grades, err := r.grade(ctx, query, batch)
+		if err != nil {
+			return nil, err
+		}
+		for _, grade := range grades {
+			scores = append(scores, min(max(grade, 0), 10)/10)
+		}
+	}
+	return scores, nil
+}
+
+// grade asks the model for one 0-10 grade per passage.
+func (r *LLMReranker) grade(ctx context.Context, query string, passages []string) ([]float64, error) {
+	var b strings.Builder
+	fmt.Fprintf(&b, `Grade how well each passage from the EINO framework documentation answers the question.
+Use 0 for unrelated, 5 for related background, and 10 for a passage that directly answers it.
+
+Question: %s
+`, query)
+	for i, passage := range passages {
+		fmt.Fprintf(&b, "\nPassage %d:\n%s\n", i, truncateUTF8(passage, maxPassageChars))
+	}
+	fmt.Fprintf(&b, `
+Respond in JSON format with exactly %d grades, in passage order:
+{"scores": [7, 0, 3]}`, len(passages))
+
+	resp, err := r.client.Chat.Completions.New(ctx, openai.ChatCompletionNewParams{
+		Messages: []openai.ChatCompletionMessageParamUnion{
+			openai.UserMessage(b.String()),
+		},
+		Model: r.model,
+		ResponseFormat: openai.ChatCompletionNewParamsResponseFormatUnion{
+			OfJSONObject: &openai.ResponseFormatJSONObjectParam{
+				Type: "json_object",
+			},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("chat completion failed: %w", err)
+	}
+	if len(resp.Choices) == 0 {
+		return nil, fmt.Errorf("chat completion returned no choices")
+	}
+
+	var graded struct {
+		Scores []float64 `json:"scores"`
+	}
+	if err := json.Unmarshal([]byte(resp.Choices[0].Message.Content), &graded); err != nil {
+		return nil, fmt.Errorf("failed to parse grades: %w", err)
+	}
+	if len(graded.Scores) != len(passages) {
+		return nil, fmt.Errorf("expected %d grades, got %d", len(passages), len(graded.Scores))
+	}
+	return graded.Scores, nil
+}
+
+// truncateUTF8 shortens s to at most n bytes without splitting a UTF-8 sequence.
+func truncateUTF8(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
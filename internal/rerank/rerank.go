@@ -0,0 +1,76 @@
+// Package rerank re-scores search candidates against the query with a model that
+// reads both together, which ranks how-to questions better than embedding similarity.
+package rerank
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/openai/openai-go"
+)
+
+// Reranker scores documents for relevance to a query.
+type Reranker interface {
+	// Rerank returns one score per document, in the order given; higher is more
+	// relevant. Scores are in 0-1.
+	Rerank(ctx context.Context, query string, documents []string) ([]float64, error)
+}
+
+// Supported reranker providers.
+const (
+	// ProviderLLM asks a chat model to grade each query/passage pair.
+	ProviderLLM = "llm"
+	// ProviderHTTP calls a rerank endpoint (Cohere, Jina or Hugging Face TEI compatible).
+	ProviderHTTP = "http"
+)
+
+// Config selects and configures a reranker. An empty Provider disables reranking.
+type Config struct {
+	Provider string // ProviderLLM, ProviderHTTP, or "" for none
+	Model    string // Chat model (llm, default DefaultLLMModel) or rerank model name (http, optional)
+	URL      string // Rerank endpoint (http), e.g. http://localhost:8081/rerank
+	APIKey   string // Bearer token for the endpoint (http, optional)
+	Format   string // Request format (http): FormatCohere (default) or FormatTEI
+}
+
+// ConfigFromEnv reads the reranker configuration from the environment:
+// RERANK_PROVIDER, RERANK_MODEL, RERANK_URL, RERANK_API_KEY and RERANK_FORMAT.
+func ConfigFromEnv() Config {
+	return Config{
+		Provider: os.Getenv("RERANK_PROVIDER"),
+		Model:    os.Getenv("RERANK_MODEL"),
+		URL:      os.Getenv("RERANK_URL"),
+		APIKey:   os.Getenv("RERANK_API_KEY"),
+		Format:   os.Getenv("RERANK_FORMAT"),
+	}
+}
+
+// New creates the configured reranker, or returns nil when reranking is disabled.
+// chat is the OpenAI client used by the llm provider; it may be nil otherwise.
+func New(cfg Config, chat *openai.Client) (Reranker, error) {
+	switch cfg.Provider {
+	case "":
+		return nil, nil
+
+	case ProviderLLM:
+		if chat == nil {
+			return nil, fmt.Errorf("the %s reranker needs a chat client", cfg.Provider)
+		}
+		return NewLLMReranker(chat, cfg.Model), nil
+
+	case ProviderHTTP:
+		if cfg.URL == "" {
+			return nil, fmt.Errorf("RERANK_URL is required for the %s reranker", cfg.Provider)
+		}
+		switch cfg.Format {
+		case "", FormatCohere, FormatTEI:
+		default:
+			return nil, fmt.Errorf("unknown rerank format %q (expected %s or %s)", cfg.Format, FormatCohere, FormatTEI)
+		}
+		return NewHTTPReranker(cfg.URL, cfg.APIKey, cfg.Model, cfg.Format), nil
+
+	default:
+		return nil, fmt.Errorf("unknown rerank provider %q (expected %s or %s)", cfg.Provider, ProviderLLM, ProviderHTTP)
+	}
+}
@@ -0,0 +1,163 @@
+package rerank
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/openai/openai-go"
+	"github.com/openai/openai-go/option"
+)
+
+// TestHTTPReranker_Cohere verifies the Cohere request format and that out-of-order
+// results map back to document order.
+func TestHTTPReranker_Cohere(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer secret" {
+			t.Errorf("Authorization: got %q", got)
+		}
+		var req rerankRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+		if req.Model != "rerank-v3.5" || req.Query != "how to compile" || len(req.Documents) != 3 || req.Texts != nil {
+			t.Errorf("unexpected request: %+v", req)
+		}
+		fmt.Fprint(w, `{"results": [{"index": 2, "relevance_score": 0.9}, {"index": 0, "relevance_score": 0.1}]}`)
+	}))
+	defer server.Close()
+
+	scores, err := NewHTTPReranker(server.URL, "secret", "rerank-v3.5", "").Rerank(context.Background(), "how to compile", []string{"a", "b", "c"})
+	if err != nil {
+		t.Fatalf("Rerank: %v", err)
+	}
+	if want := []float64{0.1, 0, 0.9}; fmt.Sprint(scores) != fmt.Sprint(want) {
+		t.Errorf("scores: expected %v, got %v", want, scores)
+	}
+}
+
+// TestHTTPReranker_TEI verifies the TEI request format and bare-array responses.
+func TestHTTPReranker_TEI(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req rerankRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+		if len(req.Texts) != 2 || req.Documents != nil {
+			t.Errorf("unexpected request: %+v", req)
+		}
+		fmt.Fprint(w, `[{"index": 1, "score": 0.8}, {"index": 0, "score": 0.3}]`)
+	}))
+	defer server.Close()
+
+	scores, err := NewHTTPReranker(server.URL, "", "", FormatTEI).Rerank(context.Background(), "q", []string{"a", "b"})
+	if err != nil {
+		t.Fatalf("Rerank: %v", err)
+	}
+	if scores[0] != 0.3 || scores[1] != 0.8 {
+		t.Errorf("scores: got %v", scores)
+	}
+}
+
+// TestHTTPReranker_Errors verifies failed requests and out-of-range indexes are errors.
+func TestHTTPReranker_Errors(t *testing.T) {
+	for name, handler := range map[string]http.HandlerFunc{
+		"status": func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "model not loaded", http.StatusServiceUnavailable)
+		},
+		"index": func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, `{"results": [{"index": 5, "relevance_score": 0.9}]}`)
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			server := httptest.NewServer(handler)
+			defer server.Close()
+
+			if _, err := NewHTTPReranker(server.URL, "", "", "").Rerank(context.Background(), "q", []string{"a"}); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+// TestLLMReranker verifies passages are graded in batches and scaled to 0-1.
+func TestLLMReranker(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		var req struct {
+			Messages []struct {
+				Content string `json:"content"`
+			} `json:"messages"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+
+		// Grade each passage by whether it mentions Compile
+		var grades []string
+		for _, part := range strings.Split(req.Messages[0].Content, "\nPassage ")[1:] {
+			if strings.Contains(part, "Compile") {
+				grades = append(grades, "10")
+			} else {
+				grades = append(grades, "2")
+			}
+		}
+		content, _ := json.Marshal(fmt.Sprintf(`{"scores": [%s]}`, strings.Join(grades, ", ")))
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"id": "x", "object": "chat.completion", "model": "m", "choices": [{"index": 0, "finish_reason": "stop", "message": {"role": "assistant", "content": %s}}]}`, content)
+	}))
+	defer server.Close()
+
+	client := openai.NewClient(option.WithBaseURL(server.URL), option.WithAPIKey("test"), option.WithMaxRetries(0))
+	documents := make([]string, llmBatchSize+2)
+	for i := range documents {
+		documents[i] = "Unrelated passage."
+	}
+	documents[llmBatchSize+1] = "Call Compile before Invoke."
+
+	scores, err := NewLLMReranker(&client, "").Rerank(context.Background(), "how to compile a graph", documents)
+	if err != nil {
+		t.Fatalf("Rerank: %v", err)
+	}
+	if requests != 2 {
+		t.Errorf("expected 2 batched requests, got %d", requests)
+	}
+	if len(scores) != len(documents) || scores[0] != 0.2 || scores[llmBatchSize+1] != 1 {
+		t.Errorf("unexpected scores: %v", scores)
+	}
+}
+
+// TestNew verifies provider selection and validation.
+func TestNew(t *testing.T) {
+	client := openai.NewClient(option.WithAPIKey("test"))
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantNil bool
+		wantErr bool
+	}{
+		{"disabled", Config{}, true, false},
+		{"llm", Config{Provider: ProviderLLM}, false, false},
+		{"http without URL", Config{Provider: ProviderHTTP}, true, true},
+		{"http", Config{Provider: ProviderHTTP, URL: "http://localhost:8081/rerank"}, false, false},
+		{"http bad format", Config{Provider: ProviderHTTP, URL: "http://localhost:8081/rerank", Format: "xml"}, true, true},
+		{"unknown provider", Config{Provider: "cohere"}, true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reranker, err := New(tt.cfg, &client)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("New() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if (reranker == nil) != tt.wantNil {
+				t.Errorf("New() reranker = %v, wantNil %v", reranker, tt.wantNil)
+			}
+		})
+	}
+}
@@ -0,0 +1,243 @@
+// Package scheduler keeps the index in sync with upstream from inside a long-running
+// process: it polls the revision of the documentation sources on an interval and runs
+// an incremental sync when it differs from the indexed one.
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/mike-a-ellis/eino-docs-mcp/internal/indexer"
+)
+
+// Run outcomes reported in Run.Outcome.
+const (
+	OutcomeSuccess = "success" // Every changed document was indexed
+	OutcomePartial = "partial" // Some documents failed; the rest were indexed
+	OutcomeFailed  = "failed"  // The sync did not complete
+)
+
+// ErrSyncInProgress is returned by SyncNow while another sync is running.
+var ErrSyncInProgress = errors.New("a sync is already running")
+
+// Syncer is the part of the indexing pipelines the scheduler drives.
+// *indexer.Pipelines implements it for every configured source.
+type Syncer interface {
+	Revision(ctx context.Context) (string, error)
+	IndexedRevision(ctx context.Context) (string, error)
+	IndexIncremental(ctx context.Context) (*indexer.IndexResult, error)
+	IndexPaths(ctx context.Context, paths map[string][]string) (*indexer.IndexResult, error)
+}
+
+// Flusher is implemented by syncers whose store buffers writes in memory.
+// *indexer.Pipelines implements it for the embedded store. The scheduler flushes after
+// every run, so the index file on disk stays current and the store keeps reloading
+// the file when another process (eino-sync) replaces it.
+type Flusher interface {
+	Flush() error
+}
+
+// Run records the result of one sync.
+type Run struct {
+	StartedAt  time.Time
+	FinishedAt time.Time
+	Commit     string // Revision indexed; empty when the sync failed
+	Outcome    string // OutcomeSuccess, OutcomePartial or OutcomeFailed
+	Error      string // Why the sync failed, or the first failed document for partial runs
+	Indexed    int    // Documents added or changed
+	Deleted    int    // Documents removed upstream
+	Failed     int    // Documents that could not be indexed
+
+	FailedPaths map[string][]string // Paths of the documents that could not be indexed, by source
+}
+
+// Status is a snapshot of the scheduler's state.
+type Status struct {
+	Interval  time.Duration // Poll interval; zero for a scheduler that is never started
+	Running   bool          // A sync is in progress
+	LastCheck time.Time     // Last time the upstream revision was polled (zero before the first)
+	NextCheck time.Time     // When the next poll is due (zero once stopped)
+	LastRun   *Run          // Most recent sync, nil if none has run
+}
+
+// Scheduler polls for upstream changes and syncs them. At most one sync runs at a
+// time, whether started by the poll loop, SyncNow or SyncPaths. The lock is held in
+// this process only: it does not stop an eino-sync run against the same index.
+type Scheduler struct {
+	syncer   Syncer
+	interval time.Duration
+	logger   *slog.Logger
+
+	running sync.Mutex // Held for the duration of a sync
+
+	mu        sync.Mutex // Guards the fields below
+	syncing   bool
+	lastCheck time.Time
+	nextCheck time.Time
+	lastRun   *Run
+}
+
+// New creates a scheduler that polls every interval once started. Without Start, syncs
+// only run through SyncNow and SyncPaths.
+func New(syncer Syncer, interval time.Duration, logger *slog.Logger) *Scheduler {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &Scheduler{syncer: syncer, interval: interval, logger: logger}
+}
+
+// Start polls immediately and then every interval until ctx is cancelled.
+func (s *Scheduler) Start(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		s.check(ctx)
+
+		s.mu.Lock()
+		s.nextCheck = time.Now().Add(s.interval)
+		s.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			s.mu.Lock()
+			s.nextCheck = time.Time{}
+			s.mu.Unlock()
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// check syncs if the upstream revision differs from the indexed one. Poll failures
+// are logged; they are not sync attempts and leave the last run untouched.
+func (s *Scheduler) check(ctx context.Context) {
+	s.mu.Lock()
+	s.lastCheck = time.Now()
+	s.mu.Unlock()
+
+	upstream, err := s.syncer.Revision(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			s.logger.Warn("Auto-sync: failed to check upstream revision", "error", err)
+		}
+		return
+	}
+	indexed, err := s.syncer.IndexedRevision(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			s.logger.Warn("Auto-sync: failed to read indexed revision", "error", err)
+		}
+		return
+	}
+	if upstream == indexed {
+		s.logger.Debug("Auto-sync: index is up to date", "commit", indexed)
+		return
+	}
+
+	s.logger.Info("Auto-sync: upstream changed", "indexed", indexed, "upstream", upstream)
+	if _, err := s.SyncNow(ctx); errors.Is(err, ErrSyncInProgress) {
+		s.logger.Info("Auto-sync: skipped, a sync is already running")
+	}
+}
+
+// SyncNow runs an incremental sync unless one is already running, in which case it
+// returns ErrSyncInProgress. The run is recorded whatever its outcome.
+func (s *Scheduler) SyncNow(ctx context.Context) (*Run, error) {
+	return s.sync(ctx, s.syncer.IndexIncremental)
+}
+
+// SyncPaths re-indexes only the given documents, keyed by source name, under the same
+// single-flight lock and recording as SyncNow. A source's indexed revision advances
+// only if every document then matches upstream (see indexer.Pipeline.IndexPaths).
+func (s *Scheduler) SyncPaths(ctx context.Context, paths map[string][]string) (*Run, error) {
+	return s.sync(ctx, func(ctx context.Context) (*indexer.IndexResult, error) {
+		return s.syncer.IndexPaths(ctx, paths)
+	})
+}
+
+// sync runs index while holding the single-flight lock and records the run.
+func (s *Scheduler) sync(ctx context.Context, index func(context.Context) (*indexer.IndexResult, error)) (*Run, error) {
+	if !s.running.TryLock() {
+		return nil, ErrSyncInProgress
+	}
+	defer s.running.Unlock()
+
+	s.setSyncing(true)
+	defer s.setSyncing(false)
+
+	run := &Run{StartedAt: time.Now()}
+	result, err := index(ctx)
+	if flusher, ok := s.syncer.(Flusher); ok {
+		// Whatever the outcome, the store holds the run's writes; persist them
+		if flushErr := flusher.Flush(); flushErr != nil && err == nil {
+			err = fmt.Errorf("failed to write the index: %w", flushErr)
+		}
+	}
+	run.FinishedAt = time.Now()
+
+	switch {
+	case err != nil:
+		run.Outcome = OutcomeFailed
+		run.Error = err.Error()
+		s.logger.Warn("Auto-sync failed", "error", err)
+	default:
+		run.Commit = result.CommitSHA
+		run.Indexed = result.SuccessfulDocs
+		run.Deleted = len(result.DeletedDocs)
+		run.Failed = len(result.FailedDocs)
+		for _, failed := range result.FailedDocs {
+			if run.FailedPaths == nil {
+				run.FailedPaths = make(map[string][]string)
+			}
+			run.FailedPaths[failed.Source] = append(run.FailedPaths[failed.Source], failed.Path)
+		}
+		run.Outcome = OutcomeSuccess
+		if run.Failed > 0 {
+			run.Outcome = OutcomePartial
+			first := result.FailedDocs[0]
+			run.Error = fmt.Sprintf("%d documents failed; %s: %s", run.Failed, first.Path, first.Reason)
+		}
+		s.logger.Info("Auto-sync complete",
+			"outcome", run.Outcome,
+			"commit", run.Commit,
+			"indexed", run.Indexed,
+			"deleted", run.Deleted,
+			"failed", run.Failed,
+		)
+	}
+
+	s.mu.Lock()
+	s.lastRun = run
+	s.mu.Unlock()
+
+	return run, err
+}
+
+// Status returns the scheduler's current state.
+func (s *Scheduler) Status() Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	status := Status{
+		Interval:  s.interval,
+		Running:   s.syncing,
+		LastCheck: s.lastCheck,
+		NextCheck: s.nextCheck,
+	}
+	if s.lastRun != nil {
+		run := *s.lastRun
+		status.LastRun = &run
+	}
+	return status
+}
+
+func (s *Scheduler) setSyncing(syncing bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.syncing = syncing
+}
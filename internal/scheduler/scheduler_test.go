@@ -0,0 +1,234 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mike-a-ellis/eino-docs-mcp/internal/indexer"
+)
+
+// stubSyncer reports fixed revisions and records incremental syncs. A sync blocks
+// until release is closed when release is set.
+type stubSyncer struct {
+	mu       sync.Mutex
+	upstream string
+	indexed  string
+	syncs    int
+	paths    []map[string][]string // Paths of each targeted sync, by source
+	err      error
+	failed   []indexer.FailedDoc
+	flushes  int   // Calls to Flush
+	flushErr error // Returned by Flush
+	started  chan struct{}
+	release  chan struct{}
+}
+
+func (s *stubSyncer) Revision(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.upstream, nil
+}
+
+func (s *stubSyncer) IndexedRevision(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.indexed, nil
+}
+
+func (s *stubSyncer) IndexIncremental(ctx context.Context) (*indexer.IndexResult, error) {
+	if s.started != nil {
+		s.started <- struct{}{}
+	}
+	if s.release != nil {
+		<-s.release
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.syncs++
+	if s.err != nil {
+		return nil, s.err
+	}
+	s.indexed = s.upstream
+	return &indexer.IndexResult{CommitSHA: s.upstream, SuccessfulDocs: 2, FailedDocs: s.failed}, nil
+}
+
+func (s *stubSyncer) IndexPaths(ctx context.Context, paths map[string][]string) (*indexer.IndexResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.paths = append(s.paths, paths)
+	result := &indexer.IndexResult{CommitSHA: s.upstream}
+	for _, sourcePaths := range paths {
+		result.SuccessfulDocs += len(sourcePaths)
+	}
+	return result, nil
+}
+
+func (s *stubSyncer) Flush() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.flushes++
+	return s.flushErr
+}
+
+func (s *stubSyncer) syncCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.syncs
+}
+
+// TestStart_SyncsOnlyWhenUpstreamChanges verifies the poll loop syncs a changed
+// revision once and leaves an up-to-date index alone.
+func TestStart_SyncsOnlyWhenUpstreamChanges(t *testing.T) {
+	syncer := &stubSyncer{upstream: "b", indexed: "a"}
+	sched := New(syncer, 10*time.Millisecond, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		sched.Start(ctx)
+		close(done)
+	}()
+	time.Sleep(100 * time.Millisecond)
+
+	status := sched.Status()
+	if status.LastCheck.IsZero() || status.NextCheck.IsZero() {
+		t.Errorf("expected check times, got %+v", status)
+	}
+	cancel()
+	<-done
+
+	if n := syncer.syncCount(); n != 1 {
+		t.Errorf("expected 1 sync, got %d", n)
+	}
+	status = sched.Status()
+	if status.LastRun == nil || status.LastRun.Outcome != OutcomeSuccess || status.LastRun.Commit != "b" || status.LastRun.Indexed != 2 {
+		t.Errorf("unexpected last run: %+v", status.LastRun)
+	}
+	if !status.NextCheck.IsZero() {
+		t.Errorf("expected no next check once stopped, got %v", status.NextCheck)
+	}
+}
+
+// TestSyncNow_SingleFlight verifies a second sync is refused while one is running.
+func TestSyncNow_SingleFlight(t *testing.T) {
+	syncer := &stubSyncer{upstream: "b", started: make(chan struct{}, 1), release: make(chan struct{})}
+	sched := New(syncer, time.Hour, nil)
+	ctx := context.Background()
+
+	finished := make(chan error)
+	go func() {
+		_, err := sched.SyncNow(ctx)
+		finished <- err
+	}()
+	<-syncer.started
+
+	if !sched.Status().Running {
+		t.Error("expected Running during a sync")
+	}
+	if _, err := sched.SyncNow(ctx); !errors.Is(err, ErrSyncInProgress) {
+		t.Errorf("expected ErrSyncInProgress, got %v", err)
+	}
+
+	close(syncer.release)
+	if err := <-finished; err != nil {
+		t.Fatalf("SyncNow: %v", err)
+	}
+	if sched.Status().Running {
+		t.Error("expected Running to clear after the sync")
+	}
+	if n := syncer.syncCount(); n != 1 {
+		t.Errorf("expected 1 sync, got %d", n)
+	}
+}
+
+// TestSyncNow_Outcomes verifies failed and partial syncs are recorded.
+func TestSyncNow_Outcomes(t *testing.T) {
+	ctx := context.Background()
+
+	syncer := &stubSyncer{upstream: "b", err: errors.New("github unavailable")}
+	sched := New(syncer, time.Hour, nil)
+	run, err := sched.SyncNow(ctx)
+	if err == nil || run.Outcome != OutcomeFailed || run.Error != "github unavailable" || run.Commit != "" {
+		t.Errorf("failed sync: run %+v, err %v", run, err)
+	}
+	if last := sched.Status().LastRun; last == nil || last.Outcome != OutcomeFailed {
+		t.Errorf("failed sync not recorded: %+v", last)
+	}
+
+	syncer = &stubSyncer{upstream: "c", failed: []indexer.FailedDoc{{Source: "eino", Path: "core/graph.md", Reason: "embeddings: timeout"}}}
+	run, err = New(syncer, time.Hour, nil).SyncNow(ctx)
+	if err != nil {
+		t.Fatalf("SyncNow: %v", err)
+	}
+	if run.Outcome != OutcomePartial || run.Failed != 1 || run.Error != "1 documents failed; core/graph.md: embeddings: timeout" {
+		t.Errorf("partial sync: %+v", run)
+	}
+	if want := []string{"core/graph.md"}; !slices.Equal(run.FailedPaths["eino"], want) {
+		t.Errorf("expected failed paths %v of source eino, got %v", want, run.FailedPaths)
+	}
+}
+
+// TestSyncPaths verifies targeted syncs are recorded and share the single-flight lock.
+func TestSyncPaths(t *testing.T) {
+	syncer := &stubSyncer{upstream: "b", started: make(chan struct{}, 1), release: make(chan struct{})}
+	sched := New(syncer, 0, nil)
+	ctx := context.Background()
+
+	finished := make(chan error)
+	go func() {
+		_, err := sched.SyncNow(ctx)
+		finished <- err
+	}()
+	<-syncer.started
+	if _, err := sched.SyncPaths(ctx, map[string][]string{"eino": {"overview.md"}}); !errors.Is(err, ErrSyncInProgress) {
+		t.Errorf("expected ErrSyncInProgress, got %v", err)
+	}
+	close(syncer.release)
+	<-finished
+
+	run, err := sched.SyncPaths(ctx, map[string][]string{"eino": {"overview.md"}, "eino-ext": {"core/graph.md"}})
+	if err != nil {
+		t.Fatalf("SyncPaths: %v", err)
+	}
+	if run.Outcome != OutcomeSuccess || run.Indexed != 2 {
+		t.Errorf("unexpected run: %+v", run)
+	}
+	if len(syncer.paths) != 1 || len(syncer.paths[0]) != 2 {
+		t.Errorf("expected one targeted sync of 2 sources, got %v", syncer.paths)
+	}
+	if last := sched.Status().LastRun; last == nil || last.Indexed != 2 {
+		t.Errorf("targeted sync not recorded: %+v", last)
+	}
+}
+
+// TestSync_Flushes verifies every run is flushed, failed ones included, and that a
+// failed flush fails the run.
+func TestSync_Flushes(t *testing.T) {
+	ctx := context.Background()
+	syncer := &stubSyncer{upstream: "b"}
+	sched := New(syncer, 0, nil)
+
+	if _, err := sched.SyncNow(ctx); err != nil {
+		t.Fatalf("SyncNow: %v", err)
+	}
+	if _, err := sched.SyncPaths(ctx, map[string][]string{"eino": {"overview.md"}}); err != nil {
+		t.Fatalf("SyncPaths: %v", err)
+	}
+	syncer.err = errors.New("github unavailable")
+	sched.SyncNow(ctx)
+	if syncer.flushes != 3 {
+		t.Errorf("expected 3 flushes, got %d", syncer.flushes)
+	}
+
+	syncer.err = nil
+	syncer.flushErr = errors.New("disk full")
+	run, err := sched.SyncNow(ctx)
+	if err == nil || run.Outcome != OutcomeFailed {
+		t.Errorf("expected a failed run after a failed flush, got %+v (err %v)", run, err)
+	}
+}
@@ -0,0 +1,64 @@
+package search
+
+import (
+	"sort"
+
+	"github.com/mike-a-ellis/eino-docs-mcp/internal/storage"
+)
+
+// RRFK is the reciprocal rank fusion constant. 60 is the value from the original
+// RRF paper and dampens the advantage of the very top ranks.
+const RRFK = 60
+
+// FuseRRF merges ranked chunk lists with reciprocal rank fusion: each chunk scores
+// the sum of 1/(RRFK+rank) over the lists it appears in. Fused scores are divided by
+// the best achievable score (rank 1 in every list), so they fall in 0-1.
+// Returns at most limit chunks, best first.
+func FuseRRF(limit int, lists ...[]*storage.ScoredChunk) []*storage.ScoredChunk {
+	scores := make(map[string]float64)
+	chunks := make(map[string]*storage.Chunk)
+	var order []string // first-seen order for stable ties
+
+	for _, list := range lists {
+		for rank, scored := range list {
+			if _, seen := chunks[scored.ID]; !seen {
+				chunks[scored.ID] = scored.Chunk
+				order = append(order, scored.ID)
+			}
+			scores[scored.ID] += 1.0 / float64(RRFK+rank+1)
+		}
+	}
+
+	best := float64(len(lists)) / float64(RRFK+1)
+
+	fused := make([]*storage.ScoredChunk, len(order))
+	for i, id := range order {
+		fused[i] = &storage.ScoredChunk{
+			Chunk: chunks[id],
+			Score: scores[id] / best,
+		}
+	}
+
+	sort.SliceStable(fused, func(i, j int) bool {
+		return fused[i].Score > fused[j].Score
+	})
+
+	if limit > 0 && len(fused) > limit {
+		fused = fused[:limit]
+	}
+	return fused
+}
+
+// normalizeByTop rescales scores so the best chunk scores 1.
+// Used for BM25, whose raw scores are unbounded.
+func normalizeByTop(chunks []*storage.ScoredChunk) []*storage.ScoredChunk {
+	if len(chunks) == 0 || chunks[0].Score <= 0 {
+		return chunks
+	}
+
+	top := chunks[0].Score
+	for _, chunk := range chunks {
+		chunk.Score /= top
+	}
+	return chunks
+}
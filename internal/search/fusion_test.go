@@ -0,0 +1,70 @@
+package search
+
+import (
+	"math"
+	"testing"
+
+	"github.com/mike-a-ellis/eino-docs-mcp/internal/storage"
+)
+
+func scored(id string, score float64) *storage.ScoredChunk {
+	return &storage.ScoredChunk{Chunk: &storage.Chunk{ID: id}, Score: score}
+}
+
+// TestFuseRRF_Ordering verifies chunks found by both retrievers outrank single-list hits.
+func TestFuseRRF_Ordering(t *testing.T) {
+	dense := []*storage.ScoredChunk{scored("a", 0.9), scored("b", 0.8), scored("c", 0.7)}
+	sparse := []*storage.ScoredChunk{scored("c", 12), scored("d", 9)}
+
+	fused := FuseRRF(10, dense, sparse)
+
+	var ids []string
+	for _, chunk := range fused {
+		ids = append(ids, chunk.ID)
+	}
+	want := []string{"c", "a", "b", "d"} // b and d tie; first-seen order wins
+	if len(ids) != len(want) {
+		t.Fatalf("Expected %v, got %v", want, ids)
+	}
+	for i := range want {
+		if ids[i] != want[i] {
+			t.Fatalf("Expected %v, got %v", want, ids)
+		}
+	}
+}
+
+// TestFuseRRF_Normalization verifies a chunk ranked first everywhere scores 1.
+func TestFuseRRF_Normalization(t *testing.T) {
+	fused := FuseRRF(10,
+		[]*storage.ScoredChunk{scored("a", 0.9), scored("b", 0.1)},
+		[]*storage.ScoredChunk{scored("a", 5)},
+	)
+
+	if math.Abs(fused[0].Score-1) > 1e-9 {
+		t.Errorf("Expected top score 1, got %f", fused[0].Score)
+	}
+	if fused[1].Score <= 0 || fused[1].Score >= 1 {
+		t.Errorf("Expected second score in (0,1), got %f", fused[1].Score)
+	}
+}
+
+// TestFuseRRF_Limit verifies the result is truncated to the limit.
+func TestFuseRRF_Limit(t *testing.T) {
+	fused := FuseRRF(2, []*storage.ScoredChunk{scored("a", 1), scored("b", 1), scored("c", 1)})
+	if len(fused) != 2 {
+		t.Errorf("Expected 2 results, got %d", len(fused))
+	}
+}
+
+// TestParseMode verifies defaults and validation.
+func TestParseMode(t *testing.T) {
+	if mode, err := ParseMode(""); err != nil || mode != ModeHybrid {
+		t.Errorf("Expected default hybrid, got %q (%v)", mode, err)
+	}
+	if mode, err := ParseMode("sparse"); err != nil || mode != ModeSparse {
+		t.Errorf("Expected sparse, got %q (%v)", mode, err)
+	}
+	if _, err := ParseMode("fuzzy"); err == nil {
+		t.Error("Expected error for unknown mode")
+	}
+}
@@ -0,0 +1,66 @@
+package search
+
+import (
+	"math"
+
+	"github.com/mike-a-ellis/eino-docs-mcp/internal/storage"
+)
+
+// DefaultMMRLambda balances relevance against diversity when re-ranking with MMR.
+const DefaultMMRLambda = 0.7
+
+// MMR re-orders chunks by maximal marginal relevance. Each step picks the chunk
+// maximizing
+//
+//	lambda*score - (1-lambda)*max(cosine similarity to the chunks already picked)
+//
+// so a near-duplicate of a picked chunk falls behind a less relevant but different
+// one. lambda 1 keeps the relevance order; lower values favour diversity. vectors maps
+// chunk IDs to their dense embeddings; chunks without a vector are never penalized.
+// Scores are left unchanged.
+func MMR(chunks []*storage.ScoredChunk, vectors map[string][]float32, lambda float64) []*storage.ScoredChunk {
+	remaining := append([]*storage.ScoredChunk(nil), chunks...)
+	ranked := make([]*storage.ScoredChunk, 0, len(chunks))
+
+	// maxSim[i] is the highest similarity of remaining[i] to any picked chunk
+	maxSim := make([]float64, len(remaining))
+
+	for len(remaining) > 0 {
+		best, bestValue := 0, math.Inf(-1)
+		for i, chunk := range remaining {
+			if value := lambda*chunk.Score - (1-lambda)*maxSim[i]; value > bestValue {
+				best, bestValue = i, value
+			}
+		}
+
+		picked := remaining[best]
+		ranked = append(ranked, picked)
+		remaining = append(remaining[:best], remaining[best+1:]...)
+		maxSim = append(maxSim[:best], maxSim[best+1:]...)
+
+		pickedVector := vectors[picked.ID]
+		for i, chunk := range remaining {
+			if sim := cosine(pickedVector, vectors[chunk.ID]); sim > maxSim[i] {
+				maxSim[i] = sim
+			}
+		}
+	}
+	return ranked
+}
+
+// cosine returns the cosine similarity of a and b (0 if either is missing or zero).
+func cosine(a, b []float32) float64 {
+	if len(a) == 0 || len(a) != len(b) {
+		return 0
+	}
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
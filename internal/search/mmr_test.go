@@ -0,0 +1,44 @@
+package search
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/mike-a-ellis/eino-docs-mcp/internal/storage"
+)
+
+func ids(chunks []*storage.ScoredChunk) []string {
+	var result []string
+	for _, chunk := range chunks {
+		result = append(result, chunk.ID)
+	}
+	return result
+}
+
+// TestMMR_Diversifies verifies a near-duplicate falls behind a different, less relevant chunk.
+func TestMMR_Diversifies(t *testing.T) {
+	chunks := []*storage.ScoredChunk{scored("a", 1.0), scored("a-copy", 0.95), scored("b", 0.8)}
+	vectors := map[string][]float32{
+		"a":      {1, 0},
+		"a-copy": {1, 0.01},
+		"b":      {0, 1},
+	}
+
+	if got, want := ids(MMR(chunks, vectors, DefaultMMRLambda)), []string{"a", "b", "a-copy"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("MMR() = %v, want %v", got, want)
+	}
+	if got, want := ids(MMR(chunks, vectors, 1)), []string{"a", "a-copy", "b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("MMR(lambda 1) = %v, want relevance order %v", got, want)
+	}
+	if chunks[1].ID != "a-copy" || chunks[1].Score != 0.95 {
+		t.Error("MMR should not modify the input or the scores")
+	}
+}
+
+// TestMMR_MissingVectors verifies chunks without vectors keep their relevance order.
+func TestMMR_MissingVectors(t *testing.T) {
+	chunks := []*storage.ScoredChunk{scored("a", 0.9), scored("b", 0.8), scored("c", 0.7)}
+	if got, want := ids(MMR(chunks, nil, 0.5)), []string{"a", "b", "c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("MMR() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,255 @@
+// Package search implements chunk retrieval over the document store: dense vector
+// search, BM25 keyword search, hybrid fusion of the two, and optional reranking.
+package search
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+
+	"github.com/mike-a-ellis/eino-docs-mcp/internal/bm25"
+	"github.com/mike-a-ellis/eino-docs-mcp/internal/embedding"
+	"github.com/mike-a-ellis/eino-docs-mcp/internal/rerank"
+	"github.com/mike-a-ellis/eino-docs-mcp/internal/storage"
+)
+
+// Mode selects how chunks are retrieved.
+type Mode string
+
+const (
+	// ModeDense ranks by embedding cosine similarity.
+	ModeDense Mode = "dense"
+	// ModeSparse ranks by BM25 keyword score.
+	ModeSparse Mode = "sparse"
+	// ModeHybrid fuses dense and sparse rankings with reciprocal rank fusion.
+	ModeHybrid Mode = "hybrid"
+)
+
+// DefaultMode is used when no mode is requested.
+const DefaultMode = ModeHybrid
+
+// ParseMode validates a mode name. An empty string selects DefaultMode.
+func ParseMode(s string) (Mode, error) {
+	switch Mode(s) {
+	case "":
+		return DefaultMode, nil
+	case ModeDense, ModeSparse, ModeHybrid:
+		return Mode(s), nil
+	default:
+		return "", fmt.Errorf("unknown search mode %q (expected dense, sparse or hybrid)", s)
+	}
+}
+
+// Options controls a single search.
+type Options struct {
+	Mode     Mode                 // Retrieval mode (default DefaultMode)
+	Limit    int                  // Maximum number of chunks to return
+	MinScore float64              // Minimum cosine similarity for dense candidates
+	Filter   storage.SearchFilter // Optional repository, path and metadata filters
+	// MMRLambda re-ranks the results with MMR when in (0, 1) (see MMR); 0 keeps
+	// the relevance order. MMR picks Limit chunks from a larger candidate pool.
+	MMRLambda float64
+	// Rerank re-scores every retrieved chunk with the searcher's reranker before
+	// MMR, so Limit is the candidate pool. Ignored when no reranker is configured.
+	Rerank bool
+	// QueryVector is the query's embedding (see EmbedQuery), for callers searching
+	// one query repeatedly. Nil embeds the query when dense search needs it.
+	QueryVector []float32
+}
+
+// mmrPoolFactor is how many candidates per requested chunk MMR chooses from. MMR can
+// only reorder what it is given, so without a larger pool it could not bring in a
+// less relevant but different chunk in place of a near-duplicate.
+const mmrPoolFactor = 4
+
+// maxMMRPool caps the MMR candidate pool; MMR compares every pair of candidates.
+const maxMMRPool = 200
+
+// Searcher retrieves chunks for a text query.
+type Searcher struct {
+	store    storage.Store
+	embedder embedding.Embedder
+	reranker rerank.Reranker
+}
+
+// NewSearcher creates a searcher over the given store.
+func NewSearcher(store storage.Store, embedder embedding.Embedder) *Searcher {
+	return &Searcher{
+		store:    store,
+		embedder: embedder,
+	}
+}
+
+// WithReranker returns a copy of the searcher that reranks when Options.Rerank is set.
+func (s *Searcher) WithReranker(reranker rerank.Reranker) *Searcher {
+	c := *s
+	c.reranker = reranker
+	return &c
+}
+
+// CanRerank reports whether the searcher has a reranker configured.
+func (s *Searcher) CanRerank() bool {
+	return s.reranker != nil
+}
+
+// EmbedQuery returns the embedding of query for Options.QueryVector.
+func (s *Searcher) EmbedQuery(ctx context.Context, query string) ([]float32, error) {
+	embeddings, err := s.embedder.GenerateEmbeddings(ctx, []string{query})
+	if err != nil {
+		return nil, fmt.Errorf("failed to embed query: %w", err)
+	}
+	return embeddings[0], nil
+}
+
+// Search returns up to opts.Limit chunks ordered by relevance, or by MMR when
+// opts.MMRLambda is set.
+// Scores are normalized to 0-1 in every mode: cosine similarity for dense, relative
+// BM25 score for sparse, and the fraction of the best possible fused rank for hybrid.
+// Reranked chunks carry the reranker's score, with the retrieval score kept in
+// OriginalScore.
+func (s *Searcher) Search(ctx context.Context, query string, opts Options) ([]*storage.ScoredChunk, error) {
+	limit := opts.Limit
+	mmr := opts.MMRLambda > 0 && opts.MMRLambda < 1
+	rerank := opts.Rerank && s.reranker != nil
+	if mmr && !rerank {
+		// A reranked search's Limit already is its candidate pool
+		opts.Limit = max(limit, min(limit*mmrPoolFactor, maxMMRPool))
+	}
+
+	chunks, err := s.retrieve(ctx, query, opts)
+	if err != nil {
+		return nil, err
+	}
+	if rerank && len(chunks) > 0 {
+		reranked, err := s.rerank(ctx, query, chunks)
+		if err != nil {
+			// A failing reranker should not fail the search; keep the retrieval order
+			slog.Warn("Reranking failed, using retrieval order", "error", err)
+		} else {
+			chunks = reranked
+		}
+	}
+	if !mmr || len(chunks) < 2 {
+		return chunks, nil
+	}
+
+	ids := make([]string, len(chunks))
+	for i, chunk := range chunks {
+		ids[i] = chunk.ID
+	}
+	vectors, err := s.store.GetChunkEmbeddings(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load chunk vectors: %w", err)
+	}
+	chunks = MMR(chunks, vectors, opts.MMRLambda)
+	if len(chunks) > limit {
+		chunks = chunks[:limit]
+	}
+	return chunks, nil
+}
+
+// rerank scores chunks with the reranker and returns copies ordered by the new score.
+// Each passage is prefixed with its header path, which often names the topic.
+func (s *Searcher) rerank(ctx context.Context, query string, chunks []*storage.ScoredChunk) ([]*storage.ScoredChunk, error) {
+	passages := make([]string, len(chunks))
+	for i, chunk := range chunks {
+		passages[i] = chunk.Content
+		if chunk.HeaderPath != "" {
+			passages[i] = chunk.HeaderPath + "\n\n" + chunk.Content
+		}
+	}
+	scores, err := s.reranker.Rerank(ctx, query, passages)
+	if err != nil {
+		return nil, err
+	}
+	if len(scores) != len(chunks) {
+		return nil, fmt.Errorf("reranker returned %d scores for %d chunks", len(scores), len(chunks))
+	}
+
+	reranked := make([]*storage.ScoredChunk, len(chunks))
+	for i, chunk := range chunks {
+		original := chunk.Score
+		reranked[i] = &storage.ScoredChunk{Chunk: chunk.Chunk, Score: scores[i], OriginalScore: &original}
+	}
+	// Stable, so retrieval order breaks ties
+	sort.SliceStable(reranked, func(i, j int) bool {
+		return reranked[i].Score > reranked[j].Score
+	})
+	return reranked, nil
+}
+
+// retrieve returns the chunks for query in relevance order using opts.Mode.
+func (s *Searcher) retrieve(ctx context.Context, query string, opts Options) ([]*storage.ScoredChunk, error) {
+	mode := opts.Mode
+	if mode == "" {
+		mode = DefaultMode
+	}
+
+	switch mode {
+	case ModeDense:
+		return s.searchDense(ctx, query, opts)
+
+	case ModeSparse:
+		chunks, err := s.searchSparse(ctx, query, opts)
+		if err != nil {
+			return nil, err
+		}
+		return normalizeByTop(chunks), nil
+
+	case ModeHybrid:
+		dense, err := s.searchDense(ctx, query, opts)
+		if err != nil {
+			return nil, err
+		}
+		sparse, err := s.searchSparse(ctx, query, opts)
+		if err != nil {
+			// Collections built before sparse vectors existed cannot answer keyword
+			// queries; degrade to dense results until the next full sync.
+			slog.Warn("Sparse search failed, using dense results only", "error", err)
+			return dense, nil
+		}
+		return FuseRRF(opts.Limit, dense, sparse), nil
+
+	default:
+		return nil, fmt.Errorf("unknown search mode %q", mode)
+	}
+}
+
+// searchDense embeds the query, unless opts carries its vector, and returns chunks at
+// or above opts.MinScore.
+func (s *Searcher) searchDense(ctx context.Context, query string, opts Options) ([]*storage.ScoredChunk, error) {
+	vector := opts.QueryVector
+	if vector == nil {
+		var err error
+		if vector, err = s.EmbedQuery(ctx, query); err != nil {
+			return nil, err
+		}
+	}
+
+	chunks, err := s.store.SearchChunksWithScores(ctx, vector, opts.Limit, opts.Filter)
+	if err != nil {
+		return nil, fmt.Errorf("dense search failed: %w", err)
+	}
+
+	filtered := chunks[:0]
+	for _, chunk := range chunks {
+		if chunk.Score >= opts.MinScore {
+			filtered = append(filtered, chunk)
+		}
+	}
+	return filtered, nil
+}
+
+// searchSparse runs BM25 keyword search for the query terms.
+func (s *Searcher) searchSparse(ctx context.Context, query string, opts Options) ([]*storage.ScoredChunk, error) {
+	vector := bm25.EncodeQuery(query)
+	chunks, err := s.store.SearchChunksSparse(ctx, storage.SparseVector{
+		Indices: vector.Indices,
+		Values:  vector.Values,
+	}, opts.Limit, opts.Filter)
+	if err != nil {
+		return nil, fmt.Errorf("sparse search failed: %w", err)
+	}
+	return chunks, nil
+}
@@ -0,0 +1,96 @@
+package search
+
+import (
+	"context"
+	"math"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/mike-a-ellis/eino-docs-mcp/internal/embedding"
+	"github.com/mike-a-ellis/eino-docs-mcp/internal/storage"
+)
+
+// countingEmbedder counts the texts it embeds.
+type countingEmbedder struct {
+	embedding.Embedder
+	texts int
+}
+
+func (e *countingEmbedder) GenerateEmbeddings(ctx context.Context, texts []string) ([][]float32, error) {
+	e.texts += len(texts)
+	return e.Embedder.GenerateEmbeddings(ctx, texts)
+}
+
+// blend returns the unit vector along q + weight*e.
+func blend(q, e []float32, weight float64) []float32 {
+	v := make([]float32, len(q))
+	var norm float64
+	for i := range q {
+		v[i] = q[i] + float32(weight)*e[i]
+		norm += float64(v[i]) * float64(v[i])
+	}
+	for i := range v {
+		v[i] /= float32(math.Sqrt(norm))
+	}
+	return v
+}
+
+// TestSearch_MMRPool verifies MMR chooses from more candidates than it returns, so a
+// different chunk ranked below Limit replaces a near-duplicate, and that a supplied
+// query vector is used instead of embedding the query.
+func TestSearch_MMRPool(t *testing.T) {
+	ctx := context.Background()
+	embedder := &countingEmbedder{Embedder: embedding.NewHashEmbedder(64)}
+	store, err := storage.NewEmbeddedStorage(filepath.Join(t.TempDir(), "index.idx"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.EnsureCollection(ctx, storage.EmbeddingSpec{Model: embedder.Model(), Dimension: embedder.Dimension()}); err != nil {
+		t.Fatal(err)
+	}
+
+	vectors, err := embedder.GenerateEmbeddings(ctx, []string{"graph"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	query := vectors[0]
+
+	// e is a unit vector orthogonal to the query
+	e := make([]float32, len(query))
+	e[0] = 1
+	for i := range e {
+		e[i] -= query[0] * query[i]
+	}
+	e = blend(e, e, 0)
+
+	// Three copies of one passage outrank a different one
+	var chunks []*storage.Chunk
+	for _, c := range []struct {
+		id     string
+		vector []float32
+	}{{"a1", blend(query, e, 0.9)}, {"a2", blend(query, e, 0.9)}, {"a3", blend(query, e, 0.9)}, {"b", blend(query, e, -1.1)}} {
+		chunks = append(chunks, &storage.Chunk{ID: c.id, ParentDocID: c.id, Path: c.id + ".md", Embedding: c.vector})
+	}
+	if err := store.UpsertChunks(ctx, chunks); err != nil {
+		t.Fatal(err)
+	}
+
+	searcher := NewSearcher(store, embedder)
+	embedder.texts = 0
+	results, err := searcher.Search(ctx, "graph", Options{
+		Mode:        ModeDense,
+		Limit:       2,
+		MMRLambda:   DefaultMMRLambda,
+		QueryVector: query,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := ids(results), []string{"a1", "b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Search() = %v, want %v", got, want)
+	}
+	if embedder.texts != 0 {
+		t.Errorf("Search embedded %d texts despite a query vector", embedder.texts)
+	}
+}
@@ -0,0 +1,91 @@
+package source
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultRef is the branch indexed when a source does not set one.
+const DefaultRef = "main"
+
+// Config describes one documentation source: a docs directory in a GitHub repository.
+type Config struct {
+	Name     string `yaml:"name"`      // Identifies the source in the index and the MCP source filter
+	Owner    string `yaml:"owner"`     // Repository owner: "cloudwego"
+	Repo     string `yaml:"repo"`      // Repository name: "cloudwego.github.io"
+	Ref      string `yaml:"ref"`       // Branch to index (default DefaultRef)
+	BasePath string `yaml:"base_path"` // Docs directory within the repository
+}
+
+// DefaultConfig is the Eino User Manual, indexed when no sources file is given. Its
+// name is storage.LegacySource, the source of documents indexed before sources existed.
+var DefaultConfig = Config{
+	Name:     "eino",
+	Owner:    "cloudwego",
+	Repo:     "cloudwego.github.io",
+	Ref:      DefaultRef,
+	BasePath: "content/en/docs/eino",
+}
+
+// Repository returns the full repository path: "cloudwego/cloudwego.github.io".
+func (c Config) Repository() string {
+	return c.Owner + "/" + c.Repo
+}
+
+// PublishedOnSite reports whether the source is the Eino User Manual as published on
+// the CloudWeGo site, the only docs whose page URLs are known (see markdown.PageURL).
+func (c Config) PublishedOnSite() bool {
+	return c.Owner == DefaultConfig.Owner && c.Repo == DefaultConfig.Repo && c.BasePath == DefaultConfig.BasePath
+}
+
+// LoadConfig reads the sources listed in a YAML file:
+//
+//	sources:
+//	  - name: eino
+//	    owner: cloudwego
+//	    repo: cloudwego.github.io
+//	    ref: main
+//	    base_path: content/en/docs/eino
+//
+// An empty path returns DefaultConfig alone.
+func LoadConfig(path string) ([]Config, error) {
+	if path == "" {
+		return []Config{DefaultConfig}, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read sources file: %w", err)
+	}
+
+	var file struct {
+		Sources []Config `yaml:"sources"`
+	}
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse sources file %s: %w", path, err)
+	}
+	if len(file.Sources) == 0 {
+		return nil, fmt.Errorf("sources file %s lists no sources", path)
+	}
+
+	seen := make(map[string]bool, len(file.Sources))
+	for i := range file.Sources {
+		cfg := &file.Sources[i]
+		cfg.BasePath = strings.Trim(cfg.BasePath, "/")
+		if cfg.Ref == "" {
+			cfg.Ref = DefaultRef
+		}
+		switch {
+		case cfg.Name == "":
+			return nil, fmt.Errorf("sources file %s: source %d has no name", path, i+1)
+		case seen[cfg.Name]:
+			return nil, fmt.Errorf("sources file %s: source %q is listed twice", path, cfg.Name)
+		case cfg.Owner == "" || cfg.Repo == "" || cfg.BasePath == "":
+			return nil, fmt.Errorf("sources file %s: source %q needs owner, repo and base_path", path, cfg.Name)
+		}
+		seen[cfg.Name] = true
+	}
+	return file.Sources, nil
+}
@@ -0,0 +1,70 @@
+package source
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestLoadConfig verifies sources are read with their defaults, and that an empty path
+// selects the Eino docs alone.
+func TestLoadConfig(t *testing.T) {
+	sources, err := LoadConfig("")
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	if !reflect.DeepEqual(sources, []Config{DefaultConfig}) {
+		t.Errorf("expected the default source, got %+v", sources)
+	}
+
+	path := filepath.Join(t.TempDir(), "sources.yaml")
+	yaml := `sources:
+  - name: eino
+    owner: cloudwego
+    repo: cloudwego.github.io
+    base_path: content/en/docs/eino
+  - name: eino-ext
+    owner: example
+    repo: eino-ext-docs
+    ref: release
+    base_path: /docs/
+`
+	if err := os.WriteFile(path, []byte(yaml), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	sources, err = LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	want := []Config{
+		DefaultConfig,
+		{Name: "eino-ext", Owner: "example", Repo: "eino-ext-docs", Ref: "release", BasePath: "docs"},
+	}
+	if !reflect.DeepEqual(sources, want) {
+		t.Errorf("expected %+v, got %+v", want, sources)
+	}
+	if !sources[0].PublishedOnSite() || sources[1].PublishedOnSite() {
+		t.Error("only the Eino docs are published on the site")
+	}
+}
+
+// TestLoadConfig_Invalid verifies sources without a name or location, or with a
+// duplicate name, are rejected.
+func TestLoadConfig_Invalid(t *testing.T) {
+	for name, yaml := range map[string]string{
+		"no sources":   "sources: []\n",
+		"no name":      "sources:\n  - owner: a\n    repo: b\n    base_path: docs\n",
+		"no base path": "sources:\n  - name: a\n    owner: a\n    repo: b\n",
+		"duplicate":    "sources:\n  - {name: a, owner: a, repo: b, base_path: docs}\n  - {name: a, owner: c, repo: d, base_path: docs}\n",
+	} {
+		path := filepath.Join(t.TempDir(), strings.ReplaceAll(name, " ", "-")+".yaml")
+		if err := os.WriteFile(path, []byte(yaml), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadConfig(path); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package source
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Local reads documents from a directory, such as the docs folder of a checkout.
+type Local struct {
+	root string // Absolute path of the docs directory
+}
+
+// NewLocal creates a source for the markdown files under dir.
+func NewLocal(dir string) (*Local, error) {
+	root, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	info, err := os.Stat(root)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%s is not a directory", dir)
+	}
+	return &Local{root: root}, nil
+}
+
+// List walks the directory for markdown files, skipping hidden directories such as .git.
+func (l *Local) List(ctx context.Context) ([]Entry, error) {
+	var entries []Entry
+	err := filepath.WalkDir(l.root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if p != l.root && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(d.Name(), ".md") {
+			return nil
+		}
+
+		content, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(l.root, p)
+		if err != nil {
+			return err
+		}
+		entries = append(entries, Entry{Path: filepath.ToSlash(rel), SHA: BlobSHA(content)})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s: %w", l.root, err)
+	}
+	return entries, nil
+}
+
+// Fetch reads a document. Paths are relative to the directory and may not leave it.
+func (l *Local) Fetch(ctx context.Context, relativePath string) (*Document, error) {
+	clean := path.Clean(relativePath)
+	if path.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, "../") {
+		return nil, fmt.Errorf("invalid document path %q", relativePath)
+	}
+
+	fullPath := filepath.Join(l.root, filepath.FromSlash(clean))
+	content, err := os.ReadFile(fullPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", relativePath, err)
+	}
+
+	return &Document{
+		Path:    clean,
+		Content: string(content),
+		SHA:     BlobSHA(content),
+		URL:     "file://" + filepath.ToSlash(fullPath),
+	}, nil
+}
+
+// Revision returns the HEAD commit when the directory is inside a git working copy.
+// Otherwise it returns a hash of every document's path and content, so re-indexing an
+// unchanged tree reports the same revision. Uncommitted changes in a working copy are
+// not reflected in the commit SHA.
+func (l *Local) Revision(ctx context.Context) (string, error) {
+	if sha, err := gitHead(ctx, l.root); err == nil {
+		return sha, nil
+	}
+
+	entries, err := l.List(ctx)
+	if err != nil {
+		return "", err
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Path < entries[j].Path })
+	h := sha1.New()
+	for _, entry := range entries {
+		fmt.Fprintf(h, "%s %s\n", entry.SHA, entry.Path)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// gitHead returns the HEAD commit of the working copy containing dir. It fails when
+// dir is not in a working copy, the repository has no commits, or git is not installed.
+func gitHead(ctx context.Context, dir string) (string, error) {
+	out, err := exec.CommandContext(ctx, "git", "-C", dir, "rev-parse", "--verify", "HEAD").Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
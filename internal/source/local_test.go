@@ -0,0 +1,135 @@
+package source
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeTree creates files (slash-separated path -> content) under a new directory.
+func writeTree(t *testing.T, files map[string]string) string {
+	t.Helper()
+	root := t.TempDir()
+	for name, content := range files {
+		p := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+// TestLocal verifies only markdown outside hidden directories is listed, that blob SHAs
+// match Git's, and that paths cannot escape the directory.
+func TestLocal(t *testing.T) {
+	ctx := context.Background()
+	root := writeTree(t, map[string]string{
+		"overview.md":     "# Overview\n",
+		"core/graph.md":   "# Graph\n",
+		"core/notes.txt":  "not markdown",
+		".github/note.md": "# Hidden\n",
+	})
+	src, err := NewLocal(root)
+	if err != nil {
+		t.Fatalf("NewLocal: %v", err)
+	}
+
+	entries, err := src.List(ctx)
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(entries) != 2 || entries[0].Path != "core/graph.md" || entries[1].Path != "overview.md" {
+		t.Fatalf("unexpected entries: %v", entries)
+	}
+	// git hash-object of "# Graph\n"
+	if want := "286e64b18751acbbc3c124b75ed86e522414baf2"; entries[0].SHA != want {
+		t.Errorf("blob SHA: expected %s, got %s", want, entries[0].SHA)
+	}
+
+	doc, err := src.Fetch(ctx, "core/graph.md")
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	if doc.Content != "# Graph\n" || doc.SHA != entries[0].SHA || !strings.HasPrefix(doc.URL, "file://") {
+		t.Errorf("unexpected document: %+v", doc)
+	}
+
+	for _, p := range []string{"../secret.md", "/etc/passwd", "missing.md"} {
+		if _, err := src.Fetch(ctx, p); err == nil {
+			t.Errorf("Fetch(%q): expected an error", p)
+		}
+	}
+
+	if _, err := NewLocal(filepath.Join(root, "overview.md")); err == nil {
+		t.Error("NewLocal on a file: expected an error")
+	}
+}
+
+// TestLocal_RevisionContentHash verifies trees outside git get a content hash that
+// only changes when a document does.
+func TestLocal_RevisionContentHash(t *testing.T) {
+	ctx := context.Background()
+	root := writeTree(t, map[string]string{"overview.md": "# Overview\n"})
+	if _, err := gitHead(ctx, root); err == nil {
+		t.Skip("temporary directory is inside a git working copy")
+	}
+	src, err := NewLocal(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first, err := src.Revision(ctx)
+	if err != nil {
+		t.Fatalf("Revision: %v", err)
+	}
+	second, _ := src.Revision(ctx)
+	if first != second || len(first) != 40 {
+		t.Errorf("expected a stable 40-character hash, got %q and %q", first, second)
+	}
+
+	if err := os.WriteFile(filepath.Join(root, "overview.md"), []byte("# Changed\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if changed, _ := src.Revision(ctx); changed == first {
+		t.Error("revision did not change with the content")
+	}
+}
+
+// TestLocal_RevisionGit verifies a git working copy reports its HEAD commit.
+func TestLocal_RevisionGit(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	ctx := context.Background()
+	repo := writeTree(t, map[string]string{"docs/overview.md": "# Overview\n"})
+	git := func(args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", repo, "-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+	git("init", "-q")
+	git("add", ".")
+	git("commit", "-q", "-m", "docs")
+
+	src, err := NewLocal(filepath.Join(repo, "docs"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	revision, err := src.Revision(ctx)
+	if err != nil {
+		t.Fatalf("Revision: %v", err)
+	}
+	if head := git("rev-parse", "HEAD"); revision != head {
+		t.Errorf("expected HEAD %s, got %s", head, revision)
+	}
+}
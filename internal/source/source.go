@@ -0,0 +1,45 @@
+// Package source defines where documentation comes from. The indexer reads documents
+// through the Source interface, implemented by the GitHub fetcher and by Local for a
+// directory on disk.
+package source
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+)
+
+// Document is a markdown document read from a source.
+type Document struct {
+	Path    string // Relative path within the docs directory
+	Content string // Full markdown content
+	SHA     string // Git blob SHA of the content
+	URL     string // Where the original can be viewed, pinned to the revision when possible
+}
+
+// Entry identifies a markdown document without its content.
+type Entry struct {
+	Path string // Relative path within the docs directory
+	SHA  string // Git blob SHA of the content
+}
+
+// Source lists and reads the markdown documents of a docs tree.
+type Source interface {
+	// List returns every markdown document with its blob SHA, so incremental syncs
+	// can skip unchanged documents without reading them.
+	List(ctx context.Context) ([]Entry, error)
+	// Fetch reads one document by the path List returned.
+	Fetch(ctx context.Context, path string) (*Document, error)
+	// Revision identifies the version of the tree being indexed, normally a commit SHA.
+	Revision(ctx context.Context) (string, error)
+}
+
+// BlobSHA returns the Git blob SHA of content, the hash GitHub reports for files,
+// so documents keep the same SHA whichever source they are read from.
+func BlobSHA(content []byte) string {
+	h := sha1.New()
+	fmt.Fprintf(h, "blob %d\x00", len(content))
+	h.Write(content)
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,885 @@
+package storage
+
+import (
+	"context"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+)
+
+// embeddedFormatVersion is bumped whenever the on-disk layout changes incompatibly.
+// Version 2 added the embedding spec; version 1 files are read as LegacyEmbeddingSpec.
+const embeddedFormatVersion = 2
+
+// embeddedSnapshot is the gob-encoded on-disk representation of an EmbeddedStorage.
+type embeddedSnapshot struct {
+	Version   int
+	Spec      EmbeddingSpec
+	Documents []*Document
+	Chunks    []*Chunk
+	Symbols   []*Symbol // Absent from files written before symbols were indexed
+}
+
+// EmbeddedStorage is a pure-Go, in-process Store that persists to a single local file.
+// Search is brute-force cosine similarity, which is fast enough for a docs corpus of
+// a few thousand chunks and needs no external server.
+//
+// Writes are kept in memory and flushed atomically on Flush or Close. Readers in other
+// processes (e.g. the MCP server while eino-sync runs) pick up a new file automatically.
+type EmbeddedStorage struct {
+	path string
+
+	mu        sync.RWMutex
+	spec      EmbeddingSpec        // model the stored vectors were built with
+	documents map[string]*Document // by ID
+	chunks    map[string]*Chunk    // by ID
+	symbols   map[string]*Symbol   // by ID
+	dirty     bool                 // unflushed writes pending
+	newSpec   bool                 // spec recorded by EnsureCollection, not yet in any file
+	modTime   time.Time            // mtime of the file when last loaded or written
+}
+
+// NewEmbeddedStorage opens (or creates on first flush) the index file at path.
+func NewEmbeddedStorage(path string) (*EmbeddedStorage, error) {
+	if path == "" {
+		return nil, fmt.Errorf("embedded storage path is required")
+	}
+
+	s := &EmbeddedStorage{
+		path:      path,
+		documents: make(map[string]*Document),
+		chunks:    make(map[string]*Chunk),
+		symbols:   make(map[string]*Symbol),
+	}
+
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// load reads the index file into memory. A missing file yields an empty store.
+func (s *EmbeddedStorage) load() error {
+	f, err := os.Open(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to open index file: %w", err)
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to stat index file: %w", err)
+	}
+
+	var snapshot embeddedSnapshot
+	if err := gob.NewDecoder(f).Decode(&snapshot); err != nil {
+		return fmt.Errorf("failed to decode index file: %w", err)
+	}
+	switch snapshot.Version {
+	case 1:
+		snapshot.Spec = LegacyEmbeddingSpec
+	case embeddedFormatVersion:
+	default:
+		return fmt.Errorf("index file version %d is not supported (expected %d), re-run sync",
+			snapshot.Version, embeddedFormatVersion)
+	}
+
+	// Files written before sources were configured hold only the Eino docs
+	documents := make(map[string]*Document, len(snapshot.Documents))
+	for _, doc := range snapshot.Documents {
+		if doc.Metadata.Source == "" {
+			doc.Metadata.Source = LegacySource
+		}
+		documents[doc.ID] = doc
+	}
+	chunks := make(map[string]*Chunk, len(snapshot.Chunks))
+	for _, chunk := range snapshot.Chunks {
+		if chunk.Source == "" {
+			chunk.Source = LegacySource
+		}
+		chunks[chunk.ID] = chunk
+	}
+	symbols := make(map[string]*Symbol, len(snapshot.Symbols))
+	for _, symbol := range snapshot.Symbols {
+		symbols[symbol.ID] = symbol
+	}
+
+	s.spec = snapshot.Spec
+	s.newSpec = false
+	s.documents = documents
+	s.chunks = chunks
+	s.symbols = symbols
+	s.modTime = info.ModTime()
+	return nil
+}
+
+// refresh reloads the index file if another process replaced it since it was last read.
+// Local unflushed writes take precedence, so a writer never reloads over itself.
+func (s *EmbeddedStorage) refresh() error {
+	s.mu.RLock()
+	dirty, modTime := s.dirty, s.modTime
+	s.mu.RUnlock()
+	if dirty {
+		return nil
+	}
+
+	info, err := os.Stat(s.path)
+	if err != nil || !info.ModTime().After(modTime) {
+		return nil // Missing or unchanged file: keep what we have
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.dirty {
+		return nil
+	}
+	return s.load()
+}
+
+// Flush writes the in-memory index to disk atomically (temp file + rename). A store
+// holding only the spec EnsureCollection recorded is written only while no index file
+// exists, so it never replaces one another process wrote in the meantime.
+func (s *EmbeddedStorage) Flush() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.dirty {
+		if !s.newSpec {
+			return nil
+		}
+		if _, err := os.Stat(s.path); err == nil {
+			return nil
+		}
+	}
+
+	snapshot := embeddedSnapshot{
+		Version:   embeddedFormatVersion,
+		Spec:      s.spec,
+		Documents: make([]*Document, 0, len(s.documents)),
+		Chunks:    make([]*Chunk, 0, len(s.chunks)),
+		Symbols:   make([]*Symbol, 0, len(s.symbols)),
+	}
+	for _, doc := range s.documents {
+		snapshot.Documents = append(snapshot.Documents, doc)
+	}
+	for _, chunk := range s.chunks {
+		snapshot.Chunks = append(snapshot.Chunks, chunk)
+	}
+	for _, symbol := range s.symbols {
+		snapshot.Symbols = append(snapshot.Symbols, symbol)
+	}
+
+	if dir := filepath.Dir(s.path); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return fmt.Errorf("failed to create index directory: %w", err)
+		}
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create temp index file: %w", err)
+	}
+	defer os.Remove(tmp.Name()) // No-op after successful rename
+
+	if err := gob.NewEncoder(tmp).Encode(&snapshot); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to encode index: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write index: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("failed to replace index file: %w", err)
+	}
+
+	if info, err := os.Stat(s.path); err == nil {
+		s.modTime = info.ModTime()
+	}
+	s.dirty = false
+	s.newSpec = false
+	return nil
+}
+
+// Health always succeeds; the embedded store has no remote dependency.
+func (s *EmbeddedStorage) Health(ctx context.Context) error {
+	return nil
+}
+
+// EnsureCollection records the embedding spec of an empty store, or validates it
+// against the recorded one. No other schema setup is needed. A recorded spec is not an
+// unflushed write: an index file another process writes later still replaces it.
+func (s *EmbeddedStorage) EnsureCollection(ctx context.Context, spec EmbeddingSpec) error {
+	if err := s.refresh(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.spec.IsZero() {
+		s.spec = spec
+		s.newSpec = true
+		return nil
+	}
+	return checkEmbeddingSpec(s.spec, spec)
+}
+
+// EmbeddingSpec returns the embedding model the stored vectors were built with.
+func (s *EmbeddedStorage) EmbeddingSpec(ctx context.Context) (EmbeddingSpec, error) {
+	if err := s.refresh(); err != nil {
+		return EmbeddingSpec{}, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.spec, nil
+}
+
+// ClearCollection removes all documents, chunks and symbols and forgets the embedding spec,
+// so the next EnsureCollection may switch models.
+func (s *EmbeddedStorage) ClearCollection(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.spec = EmbeddingSpec{}
+	s.newSpec = false
+	s.documents = make(map[string]*Document)
+	s.chunks = make(map[string]*Chunk)
+	s.symbols = make(map[string]*Symbol)
+	s.dirty = true
+	return nil
+}
+
+// Discard drops unflushed writes and reloads the index file, so a rebuild that fails
+// leaves the file on disk, and what Close then writes, as it was.
+func (s *EmbeddedStorage) Discard() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.spec = EmbeddingSpec{}
+	s.documents = make(map[string]*Document)
+	s.chunks = make(map[string]*Chunk)
+	s.symbols = make(map[string]*Symbol)
+	s.modTime = time.Time{}
+	s.dirty = false
+	s.newSpec = false
+	return s.load()
+}
+
+// Close flushes pending writes to disk.
+func (s *EmbeddedStorage) Close() error {
+	return s.Flush()
+}
+
+// UpsertDocument stores a parent document.
+func (s *EmbeddedStorage) UpsertDocument(ctx context.Context, doc *Document) error {
+	stored := *doc
+	stored.Metadata.Entities = append([]string(nil), doc.Metadata.Entities...)
+	stored.Metadata.Aliases = make([]string, len(doc.Metadata.Aliases))
+	for i, alias := range doc.Metadata.Aliases {
+		stored.Metadata.Aliases[i] = NormalizeAlias(alias)
+	}
+	stored.Metadata.Links = append([]string(nil), doc.Metadata.Links...)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.documents[doc.ID] = &stored
+	s.dirty = true
+	return nil
+}
+
+// UpsertChunks stores chunks with embeddings.
+func (s *EmbeddedStorage) UpsertChunks(ctx context.Context, chunks []*Chunk) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Validate embedding dimensions
+	for i, chunk := range chunks {
+		if err := checkDimension(s.spec, len(chunk.Embedding), fmt.Sprintf("chunk %d", i)); err != nil {
+			return err
+		}
+	}
+
+	for _, chunk := range chunks {
+		stored := *chunk
+		stored.Entities = append([]string(nil), chunk.Entities...)
+		stored.Embedding = append([]float32(nil), chunk.Embedding...)
+		stored.Sparse = SparseVector{
+			Indices: append([]uint32(nil), chunk.Sparse.Indices...),
+			Values:  append([]float32(nil), chunk.Sparse.Values...),
+		}
+		s.chunks[chunk.ID] = &stored
+	}
+	if len(chunks) > 0 {
+		s.dirty = true
+	}
+	return nil
+}
+
+// DeleteDocumentByPath removes the parent document and all chunks for a path.
+func (s *EmbeddedStorage) DeleteDocumentByPath(ctx context.Context, path string, source string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for id, doc := range s.documents {
+		if doc.Metadata.Path == path && matchesOptional(doc.Metadata.Source, source) {
+			delete(s.documents, id)
+			s.dirty = true
+		}
+	}
+	for id, chunk := range s.chunks {
+		if chunk.Path == path && matchesOptional(chunk.Source, source) {
+			delete(s.chunks, id)
+			s.dirty = true
+		}
+	}
+	return nil
+}
+
+// UpdateCommitSHA stamps every parent document of a source with the given commit SHA.
+func (s *EmbeddedStorage) UpdateCommitSHA(ctx context.Context, source string, commitSHA string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, doc := range s.documents {
+		if doc.Metadata.Source == source {
+			doc.Metadata.CommitSHA = commitSHA
+			s.dirty = true
+		}
+	}
+	return nil
+}
+
+// GetDocument retrieves a parent document by ID.
+// Returns ErrDocumentNotFound if document doesn't exist.
+func (s *EmbeddedStorage) GetDocument(ctx context.Context, id string) (*Document, error) {
+	if err := s.refresh(); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	doc, ok := s.documents[id]
+	if !ok {
+		return nil, ErrDocumentNotFound
+	}
+	return copyDocument(doc), nil
+}
+
+// GetDocumentByPath retrieves a parent document by its path.
+// Returns ErrDocumentNotFound if no document exists with the given path.
+func (s *EmbeddedStorage) GetDocumentByPath(ctx context.Context, path string, source string) (*Document, error) {
+	if err := s.refresh(); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, doc := range s.documents {
+		if doc.Metadata.Path == path && matchesOptional(doc.Metadata.Source, source) {
+			return copyDocument(doc), nil
+		}
+	}
+	return nil, ErrDocumentNotFound
+}
+
+// GetDocumentByAlias retrieves a parent document by one of its Hugo aliases.
+// Returns ErrDocumentNotFound if no document declares the alias.
+func (s *EmbeddedStorage) GetDocumentByAlias(ctx context.Context, alias string, source string) (*Document, error) {
+	if err := s.refresh(); err != nil {
+		return nil, err
+	}
+
+	alias = NormalizeAlias(alias)
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, doc := range s.documents {
+		if !matchesOptional(doc.Metadata.Source, source) {
+			continue
+		}
+		if slices.Contains(doc.Metadata.Aliases, alias) {
+			return copyDocument(doc), nil
+		}
+	}
+	return nil, ErrDocumentNotFound
+}
+
+// GetDocumentChunks returns the chunks of the document at path ordered by chunk index,
+// without vectors. Returns an empty slice if the path is not indexed.
+func (s *EmbeddedStorage) GetDocumentChunks(ctx context.Context, path string, source string) ([]*Chunk, error) {
+	if err := s.refresh(); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	chunks := []*Chunk{}
+	for _, chunk := range s.chunks {
+		if chunk.Path == path && matchesOptional(chunk.Source, source) {
+			chunks = append(chunks, withoutVectors(chunk))
+		}
+	}
+
+	sort.Slice(chunks, func(i, j int) bool { return chunks[i].ChunkIndex < chunks[j].ChunkIndex })
+	return chunks, nil
+}
+
+// GetDocumentEmbeddings returns the dense vectors of the chunks of the document at path.
+// Returns an empty slice if the path is not indexed.
+func (s *EmbeddedStorage) GetDocumentEmbeddings(ctx context.Context, path string, source string) ([][]float32, error) {
+	if err := s.refresh(); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	embeddings := [][]float32{}
+	for _, chunk := range s.chunks {
+		if chunk.Path == path && matchesOptional(chunk.Source, source) && len(chunk.Embedding) > 0 {
+			embeddings = append(embeddings, slices.Clone(chunk.Embedding))
+		}
+	}
+	return embeddings, nil
+}
+
+// GetChunkEmbeddings returns the dense vectors of the chunks (or symbols) with the
+// given IDs. Unknown IDs are omitted from the result.
+func (s *EmbeddedStorage) GetChunkEmbeddings(ctx context.Context, ids []string) (map[string][]float32, error) {
+	if err := s.refresh(); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	embeddings := make(map[string][]float32, len(ids))
+	for _, id := range ids {
+		if chunk, ok := s.chunks[id]; ok && len(chunk.Embedding) > 0 {
+			embeddings[id] = slices.Clone(chunk.Embedding)
+		} else if symbol, ok := s.symbols[id]; ok && len(symbol.Embedding) > 0 {
+			embeddings[id] = slices.Clone(symbol.Embedding)
+		}
+	}
+	return embeddings, nil
+}
+
+// SearchChunksWithScores performs brute-force cosine similarity search on chunks, and
+// on symbols when filter.Symbols is set.
+// Returns top N chunks with similarity scores, ordered by score descending.
+func (s *EmbeddedStorage) SearchChunksWithScores(ctx context.Context, embedding []float32, limit int, filter SearchFilter) ([]*ScoredChunk, error) {
+	if err := s.refresh(); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if err := checkDimension(s.spec, len(embedding), "query"); err != nil {
+		return nil, err
+	}
+
+	scored := make([]*ScoredChunk, 0, len(s.chunks))
+	for _, chunk := range s.chunks {
+		if !filter.matches(chunk) {
+			continue
+		}
+		scored = append(scored, &ScoredChunk{
+			Chunk: withoutVectors(chunk),
+			Score: cosineSimilarity(embedding, chunk.Embedding),
+		})
+	}
+	if filter.matchesSymbols() {
+		for _, symbol := range s.symbols {
+			if len(symbol.Embedding) == 0 {
+				continue // Indexed before symbols were embedded
+			}
+			scored = append(scored, &ScoredChunk{
+				Chunk: symbol.searchChunk(),
+				Score: cosineSimilarity(embedding, symbol.Embedding),
+			})
+		}
+	}
+
+	return topScored(scored, limit), nil
+}
+
+// SearchChunksSparse performs BM25 keyword search on chunks (and symbols when
+// filter.Symbols is set), applying IDF computed from the stored chunks and symbols
+// (the same formula Qdrant's IDF modifier uses).
+// Only chunks sharing at least one term with the query are returned.
+func (s *EmbeddedStorage) SearchChunksSparse(ctx context.Context, query SparseVector, limit int, filter SearchFilter) ([]*ScoredChunk, error) {
+	if err := s.refresh(); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	queryWeights := make(map[uint32]float64, len(query.Indices))
+	for i, index := range query.Indices {
+		queryWeights[index] = float64(query.Values[i])
+	}
+
+	// Document frequency of each query term across all chunks and symbols, like
+	// Qdrant's collection-wide statistics
+	docFreq := make(map[uint32]int, len(queryWeights))
+	total := len(s.chunks) + len(s.symbols)
+	countTerms := func(vector SparseVector) {
+		for _, index := range vector.Indices {
+			if _, ok := queryWeights[index]; ok {
+				docFreq[index]++
+			}
+		}
+	}
+	for _, chunk := range s.chunks {
+		countTerms(chunk.Sparse)
+	}
+	for _, symbol := range s.symbols {
+		countTerms(symbol.Sparse)
+	}
+
+	score := func(vector SparseVector) float64 {
+		score := 0.0
+		for i, index := range vector.Indices {
+			weight, ok := queryWeights[index]
+			if !ok {
+				continue
+			}
+			n := float64(docFreq[index])
+			idf := math.Log(1 + (float64(total)-n+0.5)/(n+0.5))
+			score += weight * idf * float64(vector.Values[i])
+		}
+		return score
+	}
+
+	scored := make([]*ScoredChunk, 0)
+	for _, chunk := range s.chunks {
+		if !filter.matches(chunk) {
+			continue
+		}
+		if score := score(chunk.Sparse); score > 0 {
+			scored = append(scored, &ScoredChunk{Chunk: withoutVectors(chunk), Score: score})
+		}
+	}
+	if filter.matchesSymbols() {
+		for _, symbol := range s.symbols {
+			if score := score(symbol.Sparse); score > 0 {
+				scored = append(scored, &ScoredChunk{Chunk: symbol.searchChunk(), Score: score})
+			}
+		}
+	}
+
+	return topScored(scored, limit), nil
+}
+
+// withoutVectors copies a chunk without its vectors, matching Qdrant search results.
+func withoutVectors(chunk *Chunk) *Chunk {
+	result := *chunk
+	result.Embedding = nil
+	result.Sparse = SparseVector{}
+	return &result
+}
+
+// topScored sorts by score descending (ties broken by ID) and truncates to limit.
+func topScored(scored []*ScoredChunk, limit int) []*ScoredChunk {
+	sort.Slice(scored, func(i, j int) bool {
+		if scored[i].Score != scored[j].Score {
+			return scored[i].Score > scored[j].Score
+		}
+		return scored[i].ID < scored[j].ID // Stable order for equal scores
+	})
+
+	if limit >= 0 && len(scored) > limit {
+		scored = scored[:limit]
+	}
+	return scored
+}
+
+// ListDocumentPaths returns all unique document paths in the index, sorted.
+func (s *EmbeddedStorage) ListDocumentPaths(ctx context.Context, source string) ([]string, error) {
+	if err := s.refresh(); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	paths := []string{} // Initialize as empty slice, not nil (nil marshals to JSON null)
+	for _, doc := range s.documents {
+		if matchesOptional(doc.Metadata.Source, source) && doc.Metadata.Path != "" {
+			paths = append(paths, doc.Metadata.Path)
+		}
+	}
+
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// ListDocumentSHAs returns the stored blob SHA for every indexed document path.
+func (s *EmbeddedStorage) ListDocumentSHAs(ctx context.Context, source string) (map[string]string, error) {
+	if err := s.refresh(); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	shas := make(map[string]string)
+	for _, doc := range s.documents {
+		if matchesOptional(doc.Metadata.Source, source) && doc.Metadata.Path != "" {
+			shas[doc.Metadata.Path] = doc.Metadata.BlobSHA
+		}
+	}
+	return shas, nil
+}
+
+// LastIndexedAt returns the latest IndexedAt among the source's documents.
+func (s *EmbeddedStorage) LastIndexedAt(ctx context.Context, source string) (time.Time, error) {
+	if err := s.refresh(); err != nil {
+		return time.Time{}, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var latest time.Time
+	for _, doc := range s.documents {
+		if matchesOptional(doc.Metadata.Source, source) && doc.Metadata.IndexedAt.After(latest) {
+			latest = doc.Metadata.IndexedAt
+		}
+	}
+	return latest, nil
+}
+
+// ListEntities returns the sorted, de-duplicated union of all parent documents' entities.
+func (s *EmbeddedStorage) ListEntities(ctx context.Context, source string) ([]string, error) {
+	if err := s.refresh(); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	seen := make(map[string]bool)
+	for _, doc := range s.documents {
+		if !matchesOptional(doc.Metadata.Source, source) {
+			continue
+		}
+		for _, entity := range doc.Metadata.Entities {
+			if entity != "" {
+				seen[entity] = true
+			}
+		}
+	}
+	return sortedKeys(seen), nil
+}
+
+// ListAliases maps every normalized alias to the path of the document declaring it.
+func (s *EmbeddedStorage) ListAliases(ctx context.Context, source string) (map[string]string, error) {
+	if err := s.refresh(); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	aliases := make(map[string]string)
+	for _, doc := range s.documents {
+		if !matchesOptional(doc.Metadata.Source, source) {
+			continue
+		}
+		for _, alias := range doc.Metadata.Aliases {
+			aliases[alias] = doc.Metadata.Path
+		}
+	}
+	return aliases, nil
+}
+
+// ListLinks maps every document path to the internal link targets it contains.
+func (s *EmbeddedStorage) ListLinks(ctx context.Context, source string) (map[string][]string, error) {
+	if err := s.refresh(); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	links := make(map[string][]string)
+	for _, doc := range s.documents {
+		if matchesOptional(doc.Metadata.Source, source) && len(doc.Metadata.Links) > 0 {
+			links[doc.Metadata.Path] = slices.Clone(doc.Metadata.Links)
+		}
+	}
+	return links, nil
+}
+
+// GetCommitSHA retrieves the commit SHA for indexed content from a source.
+// Returns empty string if no documents found for the source.
+func (s *EmbeddedStorage) GetCommitSHA(ctx context.Context, source string) (string, error) {
+	if err := s.refresh(); err != nil {
+		return "", err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	// Pick the lowest path so the answer is deterministic across calls
+	var found *Document
+	for _, doc := range s.documents {
+		if doc.Metadata.Source != source {
+			continue
+		}
+		if found == nil || doc.Metadata.Path < found.Metadata.Path {
+			found = doc
+		}
+	}
+	if found == nil {
+		return "", nil
+	}
+	return found.Metadata.CommitSHA, nil
+}
+
+// GetCollectionInfo returns the number of stored points (documents, chunks and symbols),
+// matching how Qdrant counts them in one collection.
+func (s *EmbeddedStorage) GetCollectionInfo(ctx context.Context) (*CollectionInfo, error) {
+	if err := s.refresh(); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return &CollectionInfo{
+		PointsCount:  uint64(len(s.documents) + len(s.chunks) + len(s.symbols)),
+		ChunksCount:  uint64(len(s.chunks)),
+		SymbolsCount: uint64(len(s.symbols)),
+	}, nil
+}
+
+// UpsertSymbols stores Go API symbols.
+func (s *EmbeddedStorage) UpsertSymbols(ctx context.Context, symbols []*Symbol) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Validate embedding dimensions
+	for _, symbol := range symbols {
+		if err := checkDimension(s.spec, len(symbol.Embedding), "symbol "+symbol.QualifiedName()); err != nil {
+			return err
+		}
+	}
+
+	for _, symbol := range symbols {
+		stored := *symbol
+		s.symbols[symbol.ID] = &stored
+	}
+	if len(symbols) > 0 {
+		s.dirty = true
+	}
+	return nil
+}
+
+// DeleteSymbols removes all symbols of a repository.
+func (s *EmbeddedStorage) DeleteSymbols(ctx context.Context, repository string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for id, symbol := range s.symbols {
+		if matchesOptional(symbol.Repository, repository) {
+			delete(s.symbols, id)
+			s.dirty = true
+		}
+	}
+	return nil
+}
+
+// FindSymbols returns the symbols matching name, ordered by package and name.
+func (s *EmbeddedStorage) FindSymbols(ctx context.Context, name string, repository string) ([]*Symbol, error) {
+	if err := s.refresh(); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	key := SymbolKey(name)
+	var found []*Symbol
+	for _, symbol := range s.symbols {
+		if matchesOptional(symbol.Repository, repository) && slices.Contains(symbol.lookupKeys(), key) {
+			c := *symbol
+			c.Embedding = nil
+			c.Sparse = SparseVector{}
+			found = append(found, &c)
+		}
+	}
+	sortSymbols(found)
+	return found, nil
+}
+
+// ListSymbolNames returns the sorted qualified names of all symbols.
+func (s *EmbeddedStorage) ListSymbolNames(ctx context.Context, repository string) ([]string, error) {
+	if err := s.refresh(); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	seen := make(map[string]bool)
+	for _, symbol := range s.symbols {
+		if matchesOptional(symbol.Repository, repository) {
+			seen[symbol.QualifiedName()] = true
+		}
+	}
+	return sortedKeys(seen), nil
+}
+
+// matchesOptional reports whether value passes an optional filter (empty matches all).
+func matchesOptional(value, filter string) bool {
+	return filter == "" || value == filter
+}
+
+// copyDocument returns a copy that callers can mutate without affecting the store.
+func copyDocument(doc *Document) *Document {
+	c := *doc
+	c.Metadata.Entities = append([]string(nil), doc.Metadata.Entities...)
+	c.Metadata.Aliases = append([]string(nil), doc.Metadata.Aliases...)
+	c.Metadata.Links = append([]string(nil), doc.Metadata.Links...)
+	return &c
+}
+
+// cosineSimilarity returns the cosine of the angle between a and b (0 for zero vectors).
+func cosineSimilarity(a, b []float32) float64 {
+	if len(a) != len(b) {
+		return 0
+	}
+
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
@@ -0,0 +1,577 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testSpec keeps test vectors small.
+var testSpec = EmbeddingSpec{Model: "test-model", Dimension: 8}
+
+// unitVector returns a testSpec-sized vector with a single non-zero axis.
+func unitVector(axis int) []float32 {
+	v := make([]float32, testSpec.Dimension)
+	v[axis] = 1
+	return v
+}
+
+func newTestEmbeddedStorage(t *testing.T) (*EmbeddedStorage, string) {
+	path := filepath.Join(t.TempDir(), "index.idx")
+	store, err := NewEmbeddedStorage(path)
+	require.NoError(t, err)
+	require.NoError(t, store.EnsureCollection(context.Background(), testSpec))
+	return store, path
+}
+
+func TestEmbeddedStorage_SearchAndPersist(t *testing.T) {
+	store, path := newTestEmbeddedStorage(t)
+	ctx := context.Background()
+
+	docID := uuid.New().String()
+	doc := &Document{
+		ID:      docID,
+		Content: "# Embedded\n\nContent.",
+		Metadata: DocumentMetadata{
+			Path:      "docs/embedded.md",
+			Source:    "test",
+			CommitSHA: "abc123",
+			BlobSHA:   "blob1",
+			IndexedAt: time.Now().UTC().Truncate(time.Second),
+			Summary:   "Embedded summary",
+			Entities:  []string{"EmbeddedStorage"},
+		},
+	}
+	require.NoError(t, store.UpsertDocument(ctx, doc))
+
+	chunks := []*Chunk{
+		{ID: uuid.New().String(), ParentDocID: docID, ChunkIndex: 0, Content: "first", Path: doc.Metadata.Path, Source: "test", Embedding: unitVector(0)},
+		{ID: uuid.New().String(), ParentDocID: docID, ChunkIndex: 1, Content: "second", Path: doc.Metadata.Path, Source: "test", Embedding: unitVector(1)},
+	}
+	require.NoError(t, store.UpsertChunks(ctx, chunks))
+
+	// Nearest chunk ranks first with a cosine score of 1
+	results, err := store.SearchChunksWithScores(ctx, unitVector(1), 10, SearchFilter{Source: "test"})
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.Equal(t, "second", results[0].Content)
+	assert.InDelta(t, 1.0, results[0].Score, 1e-6)
+	assert.InDelta(t, 0.0, results[1].Score, 1e-6)
+
+	// Source filter excludes other sources
+	results, err = store.SearchChunksWithScores(ctx, unitVector(1), 10, SearchFilter{Source: "other"})
+	require.NoError(t, err)
+	assert.Empty(t, results)
+
+	// Data survives close and reopen
+	require.NoError(t, store.Close())
+	reopened, err := NewEmbeddedStorage(path)
+	require.NoError(t, err)
+
+	retrieved, err := reopened.GetDocumentByPath(ctx, "docs/embedded.md", "test")
+	require.NoError(t, err)
+	assert.Equal(t, doc.Content, retrieved.Content)
+	assert.Equal(t, doc.Metadata.BlobSHA, retrieved.Metadata.BlobSHA)
+	assert.ElementsMatch(t, doc.Metadata.Entities, retrieved.Metadata.Entities)
+	assert.True(t, doc.Metadata.IndexedAt.Equal(retrieved.Metadata.IndexedAt))
+
+	info, err := reopened.GetCollectionInfo(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3), info.PointsCount)
+}
+
+func TestEmbeddedStorage_DeleteAndCommitSHA(t *testing.T) {
+	store, _ := newTestEmbeddedStorage(t)
+	ctx := context.Background()
+
+	for _, path := range []string{"docs/b.md", "docs/a.md"} {
+		docID := uuid.New().String()
+		require.NoError(t, store.UpsertDocument(ctx, &Document{
+			ID:       docID,
+			Metadata: DocumentMetadata{Path: path, Source: "test", CommitSHA: "old", BlobSHA: "sha-" + path},
+		}))
+		require.NoError(t, store.UpsertChunks(ctx, []*Chunk{
+			{ID: uuid.New().String(), ParentDocID: docID, Path: path, Source: "test", Embedding: unitVector(0)},
+		}))
+	}
+
+	paths, err := store.ListDocumentPaths(ctx, "test")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"docs/a.md", "docs/b.md"}, paths)
+
+	require.NoError(t, store.DeleteDocumentByPath(ctx, "docs/b.md", "test"))
+
+	shas, err := store.ListDocumentSHAs(ctx, "test")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"docs/a.md": "sha-docs/a.md"}, shas)
+
+	results, err := store.SearchChunksWithScores(ctx, unitVector(0), 10, SearchFilter{})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "docs/a.md", results[0].Path)
+
+	require.NoError(t, store.UpdateCommitSHA(ctx, "test", "new"))
+	commitSHA, err := store.GetCommitSHA(ctx, "test")
+	require.NoError(t, err)
+	assert.Equal(t, "new", commitSHA)
+
+	_, err = store.GetDocumentByPath(ctx, "docs/b.md", "test")
+	assert.ErrorIs(t, err, ErrDocumentNotFound)
+}
+
+func TestEmbeddedStorage_LastIndexedAt(t *testing.T) {
+	store, _ := newTestEmbeddedStorage(t)
+	ctx := context.Background()
+
+	latest, err := store.LastIndexedAt(ctx, "test")
+	require.NoError(t, err)
+	assert.True(t, latest.IsZero())
+
+	synced := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	for i, doc := range []struct {
+		path, source string
+		indexedAt    time.Time
+	}{
+		{"docs/a.md", "test", synced},
+		{"docs/b.md", "test", synced.Add(time.Hour)},
+		{"docs/c.md", "other", synced.Add(2 * time.Hour)},
+	} {
+		require.NoError(t, store.UpsertDocument(ctx, &Document{
+			ID:       uuid.New().String(),
+			Metadata: DocumentMetadata{Path: doc.path, Source: doc.source, BlobSHA: fmt.Sprint(i), IndexedAt: doc.indexedAt},
+		}))
+	}
+
+	latest, err = store.LastIndexedAt(ctx, "test")
+	require.NoError(t, err)
+	assert.True(t, synced.Add(time.Hour).Equal(latest), "a re-indexed document counts, not an arbitrary one")
+
+	latest, err = store.LastIndexedAt(ctx, "")
+	require.NoError(t, err)
+	assert.True(t, synced.Add(2*time.Hour).Equal(latest))
+}
+
+func TestEmbeddedStorage_GetDocumentByAlias(t *testing.T) {
+	store, _ := newTestEmbeddedStorage(t)
+	ctx := context.Background()
+
+	require.NoError(t, store.UpsertDocument(ctx, &Document{
+		ID: uuid.New().String(),
+		Metadata: DocumentMetadata{
+			Path:    "docs/new.md",
+			Source:  "test",
+			Title:   "New Page",
+			Aliases: []string{"/Docs/Old-Page/"},
+			Links:   []string{"docs/other"},
+		},
+	}))
+
+	doc, err := store.GetDocumentByAlias(ctx, "docs/old-page", "test")
+	require.NoError(t, err)
+	assert.Equal(t, "docs/new.md", doc.Metadata.Path)
+	assert.Equal(t, "New Page", doc.Metadata.Title)
+	assert.Equal(t, []string{"docs/old-page"}, doc.Metadata.Aliases)
+
+	_, err = store.GetDocumentByAlias(ctx, "/docs/old-page/", "other")
+	assert.ErrorIs(t, err, ErrDocumentNotFound)
+
+	aliases, err := store.ListAliases(ctx, "test")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"docs/old-page": "docs/new.md"}, aliases)
+
+	links, err := store.ListLinks(ctx, "test")
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]string{"docs/new.md": {"docs/other"}}, links)
+}
+
+func TestEmbeddedStorage_ListEntities(t *testing.T) {
+	store, _ := newTestEmbeddedStorage(t)
+	ctx := context.Background()
+
+	for path, entities := range map[string][]string{
+		"docs/a.md": {"compose.NewGraph", "ChatModel"},
+		"docs/b.md": {"ChatModel", "Retriever"},
+	} {
+		require.NoError(t, store.UpsertDocument(ctx, &Document{
+			ID:       uuid.New().String(),
+			Metadata: DocumentMetadata{Path: path, Source: "test", Entities: entities},
+		}))
+	}
+
+	entities, err := store.ListEntities(ctx, "test")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ChatModel", "Retriever", "compose.NewGraph"}, entities)
+
+	entities, err = store.ListEntities(ctx, "other")
+	require.NoError(t, err)
+	assert.Empty(t, entities)
+}
+
+func TestEmbeddedStorage_GetDocumentChunks(t *testing.T) {
+	store, _ := newTestEmbeddedStorage(t)
+	ctx := context.Background()
+
+	var chunks []*Chunk
+	for _, index := range []int{2, 0, 1} {
+		chunks = append(chunks, &Chunk{
+			ID:         uuid.New().String(),
+			ChunkIndex: index,
+			Path:       "docs/a.md",
+			Source:     "test",
+			Embedding:  unitVector(index),
+		})
+	}
+	chunks = append(chunks, &Chunk{
+		ID:        uuid.New().String(),
+		Path:      "docs/b.md",
+		Source:    "test",
+		Embedding: unitVector(0),
+	})
+	require.NoError(t, store.UpsertChunks(ctx, chunks))
+
+	got, err := store.GetDocumentChunks(ctx, "docs/a.md", "test")
+	require.NoError(t, err)
+	require.Len(t, got, 3)
+	for i, chunk := range got {
+		assert.Equal(t, i, chunk.ChunkIndex, "chunks are ordered by index")
+		assert.Nil(t, chunk.Embedding)
+	}
+
+	got, err = store.GetDocumentChunks(ctx, "docs/missing.md", "test")
+	require.NoError(t, err)
+	assert.Empty(t, got)
+
+	embeddings, err := store.GetDocumentEmbeddings(ctx, "docs/a.md", "test")
+	require.NoError(t, err)
+	assert.Len(t, embeddings, 3)
+
+	byID, err := store.GetChunkEmbeddings(ctx, []string{chunks[0].ID, "missing"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]float32{chunks[0].ID: unitVector(2)}, byID)
+}
+
+func TestEmbeddedStorage_SearchFilter(t *testing.T) {
+	store, _ := newTestEmbeddedStorage(t)
+	ctx := context.Background()
+
+	chunks := []*Chunk{
+		{ID: "graph", Path: "core_modules/chain_and_graph/graph.md", Entities: []string{"compose.NewGraph"}},
+		{ID: "model", Path: "core_modules/components/chat_model.md", Entities: []string{"model.ChatModel"}},
+		{ID: "start", Path: "quick_start/simple.md"},
+		{ID: "root", Path: "overview.md"},
+	}
+	for _, chunk := range chunks {
+		chunk.Source = "test"
+		chunk.Embedding = unitVector(0)
+	}
+	require.NoError(t, store.UpsertChunks(ctx, chunks))
+
+	search := func(filter SearchFilter) []string {
+		results, err := store.SearchChunksWithScores(ctx, unitVector(0), 10, filter)
+		require.NoError(t, err)
+		var ids []string
+		for _, r := range results {
+			ids = append(ids, r.ID)
+		}
+		return ids
+	}
+
+	assert.ElementsMatch(t, []string{"graph", "model"}, search(SearchFilter{Categories: []string{"core_modules"}}))
+	assert.ElementsMatch(t, []string{"graph", "model", "start"}, search(SearchFilter{Categories: []string{"quick_start", "core_modules"}}),
+		"any listed category matches")
+	assert.Equal(t, []string{"model"}, search(SearchFilter{PathPrefix: "core_modules/components/"}))
+	assert.Empty(t, search(SearchFilter{PathPrefix: "core_mod"}), "prefixes match whole directories")
+	assert.Equal(t, []string{"graph"}, search(SearchFilter{Entities: []string{"compose.NewGraph", "missing"}}))
+	assert.Empty(t, search(SearchFilter{Categories: []string{"quick_start"}, Entities: []string{"compose.NewGraph"}}))
+}
+
+func TestEmbeddedStorage_Symbols(t *testing.T) {
+	store, path := newTestEmbeddedStorage(t)
+	ctx := context.Background()
+
+	symbols := []*Symbol{
+		{ID: uuid.New().String(), Name: "NewGraph", Kind: "func", Package: "github.com/cloudwego/eino/compose", PackageName: "compose", Signature: "func NewGraph[I, O any](opts ...NewGraphOption) *Graph[I, O]", Repository: "cloudwego/eino", Embedding: unitVector(0)},
+		{ID: uuid.New().String(), Name: "Graph.Compile", Kind: "method", Package: "github.com/cloudwego/eino/compose", PackageName: "compose", Repository: "cloudwego/eino", Embedding: unitVector(1)},
+		{ID: uuid.New().String(), Name: "Chain.Compile", Kind: "method", Package: "github.com/cloudwego/eino/compose", PackageName: "compose", Repository: "cloudwego/eino", Embedding: unitVector(2)},
+		{ID: uuid.New().String(), Name: "Message", Kind: "type", Package: "github.com/cloudwego/eino/schema", PackageName: "schema", Repository: "other/repo", Embedding: unitVector(3)},
+	}
+	require.NoError(t, store.UpsertSymbols(ctx, symbols))
+
+	// Bare, qualified, import-path and receiver forms all match, case-insensitively
+	for _, name := range []string{"NewGraph", "compose.newgraph", "github.com/cloudwego/eino/compose.NewGraph"} {
+		found, err := store.FindSymbols(ctx, name, "cloudwego/eino")
+		require.NoError(t, err)
+		require.Len(t, found, 1, name)
+		assert.Equal(t, symbols[0].Signature, found[0].Signature)
+	}
+	found, err := store.FindSymbols(ctx, "(*Graph).Compile", "")
+	require.NoError(t, err)
+	require.Len(t, found, 1)
+	assert.Equal(t, "compose.Graph.Compile", found[0].QualifiedName())
+
+	found, err = store.FindSymbols(ctx, "Compile", "")
+	require.NoError(t, err)
+	require.Len(t, found, 2, "a bare method name matches every receiver")
+	assert.Equal(t, "Chain.Compile", found[0].Name)
+
+	names, err := store.ListSymbolNames(ctx, "cloudwego/eino")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"compose.Chain.Compile", "compose.Graph.Compile", "compose.NewGraph"}, names)
+
+	// Symbols persist and are replaced per repository
+	require.NoError(t, store.Close())
+	reopened, err := NewEmbeddedStorage(path)
+	require.NoError(t, err)
+	require.NoError(t, reopened.DeleteSymbols(ctx, "cloudwego/eino"))
+	names, err = reopened.ListSymbolNames(ctx, "")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"schema.Message"}, names)
+}
+
+func TestEmbeddedStorage_SearchSymbols(t *testing.T) {
+	store, _ := newTestEmbeddedStorage(t)
+	ctx := context.Background()
+
+	keyword := SparseVector{Indices: []uint32{7}, Values: []float32{1}}
+	require.NoError(t, store.UpsertChunks(ctx, []*Chunk{
+		{ID: "chunk", Path: "core/graph.md", Source: "docs", Embedding: unitVector(0), Sparse: keyword},
+	}))
+	require.NoError(t, store.UpsertSymbols(ctx, []*Symbol{
+		{ID: "symbol", Name: "NewGraph", Kind: "func", PackageName: "compose", Signature: "func NewGraph()", Doc: "NewGraph creates a graph.",
+			Repository: "cloudwego/eino", Embedding: unitVector(0), Sparse: keyword},
+	}))
+
+	ids := func(results []*ScoredChunk) []string {
+		var ids []string
+		for _, result := range results {
+			ids = append(ids, result.ID)
+		}
+		return ids
+	}
+
+	dense, err := store.SearchChunksWithScores(ctx, unitVector(0), 10, SearchFilter{Source: "docs"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"chunk"}, ids(dense), "symbols are only searched on request")
+
+	dense, err = store.SearchChunksWithScores(ctx, unitVector(0), 10, SearchFilter{Source: "docs", Symbols: true})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"chunk", "symbol"}, ids(dense))
+
+	sparse, err := store.SearchChunksSparse(ctx, keyword, 10, SearchFilter{Symbols: true})
+	require.NoError(t, err)
+	require.Len(t, sparse, 2)
+	for _, hit := range sparse {
+		if hit.ID != "symbol" {
+			continue
+		}
+		require.NotNil(t, hit.Symbol)
+		assert.Equal(t, "compose.NewGraph", hit.HeaderPath)
+		assert.Equal(t, "func NewGraph()\n\nNewGraph creates a graph.", hit.Content)
+		assert.Empty(t, hit.Symbol.Embedding, "vectors should not be returned")
+	}
+
+	filtered, err := store.SearchChunksSparse(ctx, keyword, 10, SearchFilter{Symbols: true, PathPrefix: "core"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"chunk"}, ids(filtered), "path filters exclude symbols")
+
+	vectors, err := store.GetChunkEmbeddings(ctx, []string{"symbol"})
+	require.NoError(t, err)
+	assert.Equal(t, unitVector(0), vectors["symbol"])
+}
+
+func TestCategory(t *testing.T) {
+	assert.Equal(t, "core_modules", Category("core_modules/components/chat_model.md"))
+	assert.Equal(t, "", Category("overview.md"))
+	assert.Equal(t, []string{"a", "a/b"}, pathDirs("a/b/c.md"))
+}
+
+func TestEmbeddedStorage_ReloadsAfterExternalWrite(t *testing.T) {
+	writer, path := newTestEmbeddedStorage(t)
+	ctx := context.Background()
+
+	reader, err := NewEmbeddedStorage(path)
+	require.NoError(t, err)
+
+	paths, err := reader.ListDocumentPaths(ctx, "")
+	require.NoError(t, err)
+	assert.Empty(t, paths)
+
+	require.NoError(t, writer.UpsertDocument(ctx, &Document{
+		ID:       uuid.New().String(),
+		Metadata: DocumentMetadata{Path: "docs/new.md", Source: "test"},
+	}))
+	require.NoError(t, writer.Flush())
+
+	paths, err = reader.ListDocumentPaths(ctx, "")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"docs/new.md"}, paths)
+}
+
+// TestEmbeddedStorage_SpecOnlyStoreReloads verifies a server opening an empty index
+// serves the file a sync writes later, and does not overwrite it on close.
+func TestEmbeddedStorage_SpecOnlyStoreReloads(t *testing.T) {
+	server, path := newTestEmbeddedStorage(t)
+	ctx := context.Background()
+
+	writer, err := NewEmbeddedStorage(path)
+	require.NoError(t, err)
+	require.NoError(t, writer.EnsureCollection(ctx, testSpec))
+	require.NoError(t, writer.UpsertDocument(ctx, &Document{
+		ID:       uuid.New().String(),
+		Metadata: DocumentMetadata{Path: "docs/synced.md", Source: "test"},
+	}))
+	require.NoError(t, writer.Close())
+
+	paths, err := server.ListDocumentPaths(ctx, "")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"docs/synced.md"}, paths)
+	require.NoError(t, server.Close())
+
+	reopened, err := NewEmbeddedStorage(path)
+	require.NoError(t, err)
+	paths, err = reopened.ListDocumentPaths(ctx, "")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"docs/synced.md"}, paths)
+}
+
+// TestEmbeddedStorage_SpecOnlyStoreClosesBeforeSync verifies closing the server
+// before any sync does not overwrite a file written since without being read.
+func TestEmbeddedStorage_SpecOnlyStoreClosesBeforeSync(t *testing.T) {
+	server, path := newTestEmbeddedStorage(t)
+	ctx := context.Background()
+
+	writer, err := NewEmbeddedStorage(path)
+	require.NoError(t, err)
+	require.NoError(t, writer.EnsureCollection(ctx, testSpec))
+	require.NoError(t, writer.UpsertDocument(ctx, &Document{
+		ID:       uuid.New().String(),
+		Metadata: DocumentMetadata{Path: "docs/synced.md", Source: "test"},
+	}))
+	require.NoError(t, writer.Close())
+	require.NoError(t, server.Close())
+
+	reopened, err := NewEmbeddedStorage(path)
+	require.NoError(t, err)
+	paths, err := reopened.ListDocumentPaths(ctx, "")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"docs/synced.md"}, paths)
+}
+
+func TestEmbeddedStorage_Discard(t *testing.T) {
+	store, path := newTestEmbeddedStorage(t)
+	ctx := context.Background()
+
+	require.NoError(t, store.UpsertDocument(ctx, &Document{
+		ID:       uuid.New().String(),
+		Metadata: DocumentMetadata{Path: "docs/kept.md", Source: "test"},
+	}))
+	require.NoError(t, store.Flush())
+
+	// A rebuild clears the index, then fails part-way
+	require.NoError(t, store.ClearCollection(ctx))
+	require.NoError(t, store.Discard())
+	require.NoError(t, store.Close())
+
+	reopened, err := NewEmbeddedStorage(path)
+	require.NoError(t, err)
+	paths, err := reopened.ListDocumentPaths(ctx, "")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"docs/kept.md"}, paths)
+
+	spec, err := reopened.EmbeddingSpec(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, testSpec, spec)
+}
+
+func TestEmbeddedStorage_LegacySource(t *testing.T) {
+	store, path := newTestEmbeddedStorage(t)
+	ctx := context.Background()
+
+	// Indexed before sources were configured
+	docID := uuid.New().String()
+	require.NoError(t, store.UpsertDocument(ctx, &Document{
+		ID:       docID,
+		Metadata: DocumentMetadata{Path: "overview.md", Repository: "cloudwego/cloudwego.github.io"},
+	}))
+	require.NoError(t, store.UpsertChunks(ctx, []*Chunk{
+		{ID: uuid.New().String(), ParentDocID: docID, Path: "overview.md", Embedding: unitVector(0)},
+	}))
+	require.NoError(t, store.Close())
+
+	reopened, err := NewEmbeddedStorage(path)
+	require.NoError(t, err)
+	paths, err := reopened.ListDocumentPaths(ctx, LegacySource)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"overview.md"}, paths)
+
+	results, err := reopened.SearchChunksWithScores(ctx, unitVector(0), 10, SearchFilter{Source: LegacySource})
+	require.NoError(t, err)
+	assert.Len(t, results, 1)
+}
+
+func TestEmbeddedStorage_DimensionValidation(t *testing.T) {
+	store, _ := newTestEmbeddedStorage(t)
+	ctx := context.Background()
+
+	err := store.UpsertChunks(ctx, []*Chunk{{ID: uuid.New().String(), Embedding: make([]float32, 512)}})
+	assert.ErrorIs(t, err, ErrDimensionMismatch)
+
+	err = store.UpsertSymbols(ctx, []*Symbol{{ID: uuid.New().String(), Name: "NewGraph", PackageName: "compose", Embedding: make([]float32, 512)}})
+	assert.ErrorIs(t, err, ErrDimensionMismatch)
+
+	_, err = store.SearchChunksWithScores(ctx, make([]float32, 512), 10, SearchFilter{})
+	assert.ErrorIs(t, err, ErrDimensionMismatch)
+}
+
+func TestEmbeddedStorage_EmbeddingSpec(t *testing.T) {
+	store, path := newTestEmbeddedStorage(t)
+	ctx := context.Background()
+	require.NoError(t, store.Close())
+
+	// The spec survives reopening and is validated on EnsureCollection
+	reopened, err := NewEmbeddedStorage(path)
+	require.NoError(t, err)
+	spec, err := reopened.EmbeddingSpec(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, testSpec, spec)
+
+	require.NoError(t, reopened.EnsureCollection(ctx, testSpec))
+	err = reopened.EnsureCollection(ctx, EmbeddingSpec{Model: "other-model", Dimension: 8})
+	assert.ErrorIs(t, err, ErrEmbeddingMismatch)
+
+	// Clearing the store allows switching models
+	require.NoError(t, reopened.ClearCollection(ctx))
+	require.NoError(t, reopened.EnsureCollection(ctx, LegacyEmbeddingSpec))
+	spec, err = reopened.EmbeddingSpec(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, LegacyEmbeddingSpec, spec)
+}
+
+func TestEmbeddedStorage_SparseSearch(t *testing.T) {
+	store, _ := newTestEmbeddedStorage(t)
+	ctx := context.Background()
+
+	// Term 1 is rare (one chunk), term 2 is common (all chunks)
+	chunks := []*Chunk{
+		{ID: "rare", Path: "a.md", Embedding: unitVector(0), Sparse: SparseVector{Indices: []uint32{1, 2}, Values: []float32{1, 1}}},
+		{ID: "common-1", Path: "b.md", Embedding: unitVector(0), Sparse: SparseVector{Indices: []uint32{2}, Values: []float32{1}}},
+		{ID: "common-2", Path: "c.md", Embedding: unitVector(0), Sparse: SparseVector{Indices: []uint32{2, 3}, Values: []float32{1, 1}}},
+		{ID: "none", Path: "d.md", Embedding: unitVector(0), Sparse: SparseVector{Indices: []uint32{4}, Values: []float32{1}}},
+	}
+	require.NoError(t, store.UpsertChunks(ctx, chunks))
+
+	results, err := store.SearchChunksSparse(ctx, SparseVector{Indices: []uint32{1, 2}, Values: []float32{1, 1}}, 10, SearchFilter{})
+	require.NoError(t, err)
+
+	// Chunks without any query term are excluded; the rare term dominates
+	require.Len(t, results, 3)
+	assert.Equal(t, "rare", results[0].ID)
+	assert.Greater(t, results[0].Score, results[1].Score)
+	assert.Empty(t, results[0].Sparse.Indices, "vectors should not be returned")
+}
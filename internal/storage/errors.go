@@ -7,4 +7,5 @@ var (
 	ErrCollectionNotFound = errors.New("collection not found")
 	ErrDimensionMismatch  = errors.New("embedding dimension mismatch")
 	ErrDocumentNotFound   = errors.New("document not found")
+	ErrEmbeddingMismatch  = errors.New("embedding model mismatch")
 )
@@ -0,0 +1,262 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/qdrant/go-client/qdrant"
+)
+
+// Full syncs build into a fresh, versioned "generation" collection and then atomically
+// repoint the CollectionName alias at it, so readers never see a partially built index.
+// Older generations are kept for rollback until pruned.
+
+// generationPrefix prefixes every generation collection name.
+const generationPrefix = CollectionName + "_"
+
+// generationTimeFormat sorts lexically in chronological order. A microsecond suffix
+// is appended so syncs started within the same second get distinct names.
+const generationTimeFormat = "20060102-150405"
+
+// legacyGeneration receives a copy of the legacy CollectionName collection when the
+// first generation replaces it. Its zero timestamp sorts it before every other
+// generation, so it is the rollback target of that first promotion.
+var legacyGeneration = generationName(time.Time{})
+
+// Generation describes one versioned collection.
+type Generation struct {
+	Name   string // Physical collection name, e.g. "documents_20250115-103000-123456"
+	Active bool   // Whether the CollectionName alias points at it
+}
+
+// CreateGeneration creates a new, empty generation collection for the given embedding
+// model and returns its name. Switching models only requires a new generation.
+func (s *QdrantStorage) CreateGeneration(ctx context.Context, spec EmbeddingSpec) (string, error) {
+	name := generationName(time.Now())
+	if err := s.createCollection(ctx, name, spec); err != nil {
+		return "", fmt.Errorf("failed to create generation %s: %w", name, err)
+	}
+	return name, nil
+}
+
+// generationName returns the collection name of a generation created at t.
+func generationName(t time.Time) string {
+	t = t.UTC()
+	return fmt.Sprintf("%s%s-%06d", generationPrefix, t.Format(generationTimeFormat), t.Nanosecond()/1000)
+}
+
+// ActiveGeneration returns the collection the CollectionName alias points at.
+// Returns an empty string when the alias does not exist.
+func (s *QdrantStorage) ActiveGeneration(ctx context.Context) (string, error) {
+	aliases, err := s.client.ListAliases(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to list aliases: %w", err)
+	}
+	for _, alias := range aliases {
+		if alias.GetAliasName() == CollectionName {
+			return alias.GetCollectionName(), nil
+		}
+	}
+	return "", nil
+}
+
+// ListGenerations returns all generation collections, oldest first.
+func (s *QdrantStorage) ListGenerations(ctx context.Context) ([]Generation, error) {
+	collections, err := s.client.ListCollections(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list collections: %w", err)
+	}
+
+	active, err := s.ActiveGeneration(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, name := range collections {
+		if strings.HasPrefix(name, generationPrefix) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	generations := make([]Generation, len(names))
+	for i, name := range names {
+		generations[i] = Generation{Name: name, Active: name == active}
+	}
+	return generations, nil
+}
+
+// PromoteGeneration atomically points the CollectionName alias at the given generation.
+// A legacy physical collection named CollectionName (from before aliases were used) is
+// first copied into a generation, so it stays available for rollback, and deleted just
+// before the switch, since an alias cannot share its name; this is a one-time migration.
+// Nothing is changed when the generation does not exist.
+func (s *QdrantStorage) PromoteGeneration(ctx context.Context, generation string) error {
+	collections, err := s.client.ListCollections(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list collections: %w", err)
+	}
+
+	found, legacy := false, false
+	for _, name := range collections {
+		switch name {
+		case generation:
+			found = true
+		case CollectionName:
+			legacy = true
+		}
+	}
+	if !found || generation == CollectionName {
+		return fmt.Errorf("%w: %s", ErrCollectionNotFound, generation)
+	}
+
+	active, err := s.ActiveGeneration(ctx)
+	if err != nil {
+		return err
+	}
+
+	actions := []*qdrant.AliasOperations{}
+	if active != "" {
+		actions = append(actions, qdrant.NewAliasDelete(CollectionName))
+	}
+	actions = append(actions, qdrant.NewAliasCreate(CollectionName, generation))
+
+	if legacy {
+		if err := s.preserveLegacy(ctx, collections); err != nil {
+			return fmt.Errorf("failed to copy legacy collection: %w", err)
+		}
+		if err := s.client.DeleteCollection(ctx, CollectionName); err != nil {
+			return fmt.Errorf("failed to delete legacy collection: %w", err)
+		}
+	}
+	if err := s.client.UpdateAliases(ctx, actions); err != nil {
+		if legacy {
+			return fmt.Errorf("failed to switch alias to %s (the legacy index is kept as %s): %w", generation, legacyGeneration, err)
+		}
+		return fmt.Errorf("failed to switch alias to %s: %w", generation, err)
+	}
+	s.invalidateSpec()
+	return nil
+}
+
+// preserveLegacy copies the legacy CollectionName collection, with its vector
+// configuration and every point, into legacyGeneration. A partial copy left by an
+// interrupted promotion is replaced.
+func (s *QdrantStorage) preserveLegacy(ctx context.Context, collections []string) error {
+	if slices.Contains(collections, legacyGeneration) {
+		if err := s.client.DeleteCollection(ctx, legacyGeneration); err != nil {
+			return fmt.Errorf("failed to delete partial copy: %w", err)
+		}
+	}
+
+	info, err := s.client.GetCollectionInfo(ctx, CollectionName)
+	if err != nil {
+		return fmt.Errorf("failed to get collection: %w", err)
+	}
+	params := info.GetConfig().GetParams()
+	err = s.client.CreateCollection(ctx, &qdrant.CreateCollection{
+		CollectionName:      legacyGeneration,
+		VectorsConfig:       params.GetVectorsConfig(),
+		SparseVectorsConfig: params.GetSparseVectorsConfig(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", legacyGeneration, err)
+	}
+	if err := s.createPayloadIndexes(ctx, legacyGeneration, nil); err != nil {
+		return fmt.Errorf("failed to create payload indexes: %w", err)
+	}
+
+	target := s.WithCollection(legacyGeneration)
+	batchSize := uint32(100)
+	var offset *qdrant.PointId
+	for {
+		results, err := s.client.Scroll(ctx, &qdrant.ScrollPoints{
+			CollectionName: CollectionName,
+			Limit:          qdrant.PtrOf(batchSize),
+			Offset:         offset,
+			WithPayload:    qdrant.NewWithPayload(true),
+			WithVectors:    qdrant.NewWithVectors(true),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to scroll points: %w", err)
+		}
+
+		page := afterOffset(results, offset)
+		points := make([]*qdrant.PointStruct, len(page))
+		for i, result := range page {
+			points[i] = &qdrant.PointStruct{Id: result.Id, Vectors: result.Vectors, Payload: result.Payload}
+		}
+		if len(points) > 0 {
+			if err := target.upsertWithRetry(ctx, points); err != nil {
+				return fmt.Errorf("failed to copy points: %w", err)
+			}
+		}
+
+		if uint32(len(results)) < batchSize {
+			return nil
+		}
+		offset = results[len(results)-1].Id
+	}
+}
+
+// DeleteGeneration drops a generation collection. The active generation cannot be deleted.
+func (s *QdrantStorage) DeleteGeneration(ctx context.Context, generation string) error {
+	if !strings.HasPrefix(generation, generationPrefix) {
+		return fmt.Errorf("%s is not a generation collection", generation)
+	}
+
+	active, err := s.ActiveGeneration(ctx)
+	if err != nil {
+		return err
+	}
+	if generation == active {
+		return fmt.Errorf("refusing to delete active generation %s", generation)
+	}
+
+	if err := s.client.DeleteCollection(ctx, generation); err != nil {
+		return fmt.Errorf("failed to delete generation %s: %w", generation, err)
+	}
+	return nil
+}
+
+// PruneGenerations deletes the oldest inactive generations, keeping the newest keep
+// generations (including the active one). Returns the names of deleted generations.
+func (s *QdrantStorage) PruneGenerations(ctx context.Context, keep int) ([]string, error) {
+	generations, err := s.ListGenerations(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var deleted []string
+	for i, generation := range generations {
+		if len(generations)-i <= keep {
+			break
+		}
+		if generation.Active {
+			continue
+		}
+		if err := s.DeleteGeneration(ctx, generation.Name); err != nil {
+			return deleted, err
+		}
+		deleted = append(deleted, generation.Name)
+	}
+	return deleted, nil
+}
+
+// PreviousGeneration returns the newest generation older than the active one,
+// which is the rollback target. Returns an empty string when there is none.
+func PreviousGeneration(generations []Generation) string {
+	previous := ""
+	for _, generation := range generations {
+		if generation.Active {
+			return previous
+		}
+		previous = generation.Name
+	}
+	return ""
+}
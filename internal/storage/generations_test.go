@@ -0,0 +1,43 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPreviousGeneration(t *testing.T) {
+	generations := []Generation{
+		{Name: "documents_20250101-000000"},
+		{Name: "documents_20250102-000000"},
+		{Name: "documents_20250103-000000", Active: true},
+		{Name: "documents_20250104-000000"}, // Built but failed validation or rolled back from
+	}
+	assert.Equal(t, "documents_20250102-000000", PreviousGeneration(generations))
+
+	// Oldest generation active: nothing to roll back to
+	assert.Equal(t, "", PreviousGeneration([]Generation{{Name: "documents_20250101-000000", Active: true}}))
+
+	// No active generation (alias missing)
+	assert.Equal(t, "", PreviousGeneration([]Generation{{Name: "documents_20250101-000000"}}))
+}
+
+func TestGenerationName(t *testing.T) {
+	at := time.Date(2025, 1, 15, 10, 30, 0, 123456789, time.UTC)
+	assert.Equal(t, "documents_20250115-103000-123456", generationName(at))
+
+	// Syncs started within the same second get distinct names that still sort in order
+	later := generationName(at.Add(time.Millisecond))
+	assert.NotEqual(t, generationName(at), later)
+	assert.Less(t, generationName(at), later)
+}
+
+func TestLegacyGeneration(t *testing.T) {
+	first := generationName(time.Date(2025, 1, 15, 10, 30, 0, 0, time.UTC))
+	assert.Less(t, legacyGeneration, first)
+
+	// The copied legacy collection is the rollback target of the first generation
+	generations := []Generation{{Name: legacyGeneration}, {Name: first, Active: true}}
+	assert.Equal(t, legacyGeneration, PreviousGeneration(generations))
+}
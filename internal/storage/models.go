@@ -1,47 +1,226 @@
 package storage
 
-import "time"
+import (
+	"slices"
+	"strings"
+	"time"
+)
 
 // Document represents a full markdown document stored in Qdrant.
 // Documents have no embedding vector - they exist for full-content retrieval.
 type Document struct {
-	ID       string           // UUID
-	Content  string           // Full markdown content
+	ID       string // UUID
+	Content  string // Full markdown content
 	Metadata DocumentMetadata
 }
 
 // DocumentMetadata contains indexing metadata for a document.
 type DocumentMetadata struct {
 	Path       string    // Relative path: "getting-started/installation.md"
-	URL        string    // GitHub raw URL for source
+	URL        string    // Permalink to the source file at CommitSHA
+	PageURL    string    // Published page on the documentation site
 	Repository string    // Full repo path: "cloudwego/eino"
+	Source     string    // Name of the configured documentation source: "eino"
 	CommitSHA  string    // Git commit SHA when indexed
+	BlobSHA    string    // Git blob SHA of the source file (for incremental sync)
 	IndexedAt  time.Time // When this version was indexed
 	Summary    string    // LLM-generated summary (populated in Phase 2)
 	Entities   []string  // Extracted functions/methods (populated in Phase 2)
+
+	// Hugo front matter
+	Title       string    // Page title
+	Description string    // Page description
+	Weight      int       // Hugo ordering weight within its section
+	Date        time.Time // Page date (zero if unset)
+	Aliases     []string  // Alternate URL paths, normalized with NormalizeAlias
+
+	Links []string // Internal link targets, resolved against Path (see markdown.Links)
+}
+
+// LegacySource is the source of documents indexed before sources were configured,
+// when the Eino docs were the only ones indexed. It is the name of source.DefaultConfig.
+const LegacySource = "eino"
+
+// NormalizeAlias converts a Hugo alias ("/docs/eino/overview/") into the form
+// stored and matched by GetDocumentByAlias ("docs/eino/overview").
+func NormalizeAlias(alias string) string {
+	return strings.ToLower(strings.Trim(strings.TrimSpace(alias), "/"))
 }
 
 // Chunk represents a document section with an embedding vector.
 // Chunks are used for semantic search, then parent document is retrieved.
 type Chunk struct {
-	ID          string    // UUID
-	ParentDocID string    // Links to parent Document.ID
-	ChunkIndex  int       // Position in document (0, 1, 2...)
-	HeaderPath  string    // Section hierarchy: "Installation > Prerequisites"
-	Content     string    // Chunk text content
-	Path        string    // Same as parent document path (for filtering)
-	Repository  string    // Same as parent (for filtering)
-	Embedding   []float32 // 1536-dim vector (text-embedding-3-small)
+	ID          string       // UUID
+	ParentDocID string       // Links to parent Document.ID
+	ChunkIndex  int          // Position in document (0, 1, 2...)
+	HeaderPath  string       // Section hierarchy: "Installation > Prerequisites"
+	Content     string       // Chunk text content
+	Path        string       // Same as parent document path (for filtering)
+	Repository  string       // Same as parent
+	Source      string       // Same as parent (for filtering)
+	Entities    []string     // Same as parent document entities (for filtering)
+	Embedding   []float32    // 1536-dim vector (text-embedding-3-small)
+	Sparse      SparseVector // BM25 lexical vector for keyword search
+	Symbol      *Symbol      // Set on search hits that are Go API symbols (see SearchFilter.Symbols)
+}
+
+// SparseVector is a sparse lexical vector of hashed term indices and weights.
+type SparseVector struct {
+	Indices []uint32
+	Values  []float32
+}
+
+// SearchFilter restricts chunk searches. Zero fields do not filter.
+type SearchFilter struct {
+	Source     string   // Exact source name (see DocumentMetadata.Source)
+	PathPrefix string   // Directory the document lives under, e.g. "core_modules/components/"
+	Categories []string // Top-level directories (see Category); any may match
+	Entities   []string // Entity names from the document's metadata; any may match
+	// Symbols also matches Go API symbols of any repository. Symbols have no path or
+	// entities, so PathPrefix, Categories and Entities exclude them.
+	Symbols bool
+}
+
+// Category returns the top-level directory of a document path, or "" for documents
+// at the root of the docs tree.
+func Category(path string) string {
+	if i := strings.IndexByte(path, '/'); i > 0 {
+		return path[:i]
+	}
+	return ""
+}
+
+// pathDirs returns every directory containing path, outermost first:
+// "a/b/c.md" -> ["a", "a/b"]. Stored on chunks so prefixes match as keywords.
+func pathDirs(path string) []string {
+	var dirs []string
+	for i := 0; i < len(path); i++ {
+		if path[i] == '/' {
+			dirs = append(dirs, path[:i])
+		}
+	}
+	return dirs
+}
+
+// normalizePrefix strips slashes so "core/" and "/core" both select directory "core".
+func normalizePrefix(prefix string) string {
+	return strings.Trim(strings.TrimSpace(prefix), "/")
+}
+
+// matches reports whether chunk passes the filter.
+func (f SearchFilter) matches(chunk *Chunk) bool {
+	if f.Source != "" && chunk.Source != f.Source {
+		return false
+	}
+	if prefix := normalizePrefix(f.PathPrefix); prefix != "" && !strings.HasPrefix(chunk.Path, prefix+"/") {
+		return false
+	}
+	if len(f.Categories) > 0 && !slices.Contains(f.Categories, Category(chunk.Path)) {
+		return false
+	}
+	if len(f.Entities) > 0 && !slices.ContainsFunc(f.Entities, func(entity string) bool {
+		return slices.Contains(chunk.Entities, entity)
+	}) {
+		return false
+	}
+	return true
+}
+
+// matchesSymbols reports whether the filter lets Go API symbols through.
+func (f SearchFilter) matchesSymbols() bool {
+	return f.Symbols && normalizePrefix(f.PathPrefix) == "" && len(f.Categories) == 0 && len(f.Entities) == 0
+}
+
+// Symbol is an exported Go declaration indexed from source code, stored alongside the
+// documents as a point of type "symbol".
+type Symbol struct {
+	ID          string // UUID
+	Name        string // Declared name; methods are "Recv.Method"
+	Kind        string // "type", "interface", "func" or "method"
+	Package     string // Import path: "github.com/cloudwego/eino/compose"
+	PackageName string // Package clause name: "compose"
+	Signature   string // Declaration without body or doc comment
+	Doc         string // Doc comment text
+	File        string // Source file relative to the module root
+	Line        int    // Line of the declaration in File
+	Repository  string // Full repo path: "cloudwego/eino"
+	CommitSHA   string // Git commit SHA when indexed
+
+	Embedding []float32    // Dense vector of Text
+	Sparse    SparseVector // BM25 lexical vector of Text
+}
+
+// Text returns what a symbol is embedded and keyword-indexed as: its qualified name,
+// signature and doc comment.
+func (s *Symbol) Text() string {
+	text := s.QualifiedName() + "\n\n" + s.Signature
+	if s.Doc != "" {
+		text += "\n\n" + s.Doc
+	}
+	return text
+}
+
+// searchChunk presents a symbol as a search hit: the qualified name as the header
+// path and the signature and doc comment as the content. Vectors are not copied.
+func (s *Symbol) searchChunk() *Chunk {
+	symbol := *s
+	symbol.Embedding = nil
+	symbol.Sparse = SparseVector{}
+
+	content := s.Signature
+	if s.Doc != "" {
+		content += "\n\n" + s.Doc
+	}
+	return &Chunk{
+		ID:         s.ID,
+		HeaderPath: s.QualifiedName(),
+		Content:    content,
+		Repository: s.Repository,
+		Symbol:     &symbol,
+	}
+}
+
+// QualifiedName returns the name as referenced from other packages: "compose.NewGraph".
+func (s *Symbol) QualifiedName() string {
+	return s.PackageName + "." + s.Name
+}
+
+// lookupKeys returns the normalized names FindSymbols matches: the declared, qualified
+// and import-path-qualified names, plus the bare method name for methods.
+func (s *Symbol) lookupKeys() []string {
+	keys := []string{
+		SymbolKey(s.Name),
+		SymbolKey(s.QualifiedName()),
+		SymbolKey(s.Package + "." + s.Name),
+	}
+	if _, method, ok := strings.Cut(s.Name, "."); ok {
+		keys = append(keys, SymbolKey(method))
+	}
+	return keys
+}
+
+// SymbolKey normalizes a symbol reference for lookup: case-insensitive, with pointer
+// receivers and parentheses removed, so "(*Graph).Compile" matches "Graph.Compile".
+func SymbolKey(name string) string {
+	name = strings.NewReplacer("(", "", ")", "", "*", "").Replace(strings.TrimSpace(name))
+	return strings.ToLower(name)
 }
 
 // ScoredChunk wraps a Chunk with its similarity score from vector search.
 type ScoredChunk struct {
 	*Chunk
 	Score float64 // Similarity score (0-1, higher is more similar)
+	// OriginalScore is the retrieval score when Score was replaced by a reranker's
+	// score, nil otherwise.
+	OriginalScore *float64
 }
 
-// CollectionName is the single Qdrant collection for all documents.
+// CollectionName is the Qdrant alias that readers use for all documents.
+// It points at the live generation collection (CollectionName + "_" + timestamp).
 const CollectionName = "documents"
 
-// VectorDimension is the embedding size for text-embedding-3-small.
-const VectorDimension = 1536
+// Named vectors stored on each chunk point.
+const (
+	denseVectorName  = "content" // Dense embedding (cosine)
+	sparseVectorName = "bm25"    // Sparse BM25 term weights (IDF applied by Qdrant)
+)
@@ -2,8 +2,10 @@ package storage
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sort"
+	"sync"
 	"time"
 
 	"github.com/cenkalti/backoff/v4"
@@ -11,12 +13,30 @@ import (
 )
 
 // QdrantStorage wraps the Qdrant client with connection management and health checks.
+// By default it reads and writes through the CollectionName alias, which points at the
+// live generation (see generations.go).
 type QdrantStorage struct {
-	client *qdrant.Client
-	host   string
-	port   int
+	client     *qdrant.Client
+	host       string
+	port       int
+	collection string // Alias or physical collection all operations target
+	shared     bool   // Client is owned by another QdrantStorage (see WithCollection)
+
+	specMu     sync.Mutex
+	spec       EmbeddingSpec // Cached embedding spec of the collection (see loadSpec)
+	specLoaded time.Time     // When spec was read; zero when nothing is cached
+	sparse     bool          // Collection has the "bm25" sparse vector, read with spec
 }
 
+// specCacheTTL bounds how long a cached embedding spec is trusted. Another process
+// (eino-sync rollback or a full sync) can repoint the alias at a generation built with
+// a different model at any time.
+const specCacheTTL = 30 * time.Second
+
+// metaPointID is the fixed ID of the point holding collection-level metadata
+// (the embedding spec). It is stored with type "meta" and a zero vector.
+const metaPointID = "00000000-0000-0000-0000-000000000001"
+
 // NewQdrantStorage creates a new Qdrant client with health validation.
 // It performs health check with retry on startup and fails fast if Qdrant is unreachable.
 func NewQdrantStorage(host string, port int) (*QdrantStorage, error) {
@@ -30,9 +50,10 @@ func NewQdrantStorage(host string, port int) (*QdrantStorage, error) {
 	}
 
 	storage := &QdrantStorage{
-		client: client,
-		host:   host,
-		port:   port,
+		client:     client,
+		host:       host,
+		port:       port,
+		collection: CollectionName,
 	}
 
 	// Perform health check with exponential backoff retry
@@ -82,10 +103,29 @@ func (s *QdrantStorage) Health(ctx context.Context) error {
 	return nil
 }
 
-// EnsureCollection ensures the documents collection exists with proper configuration.
-// Creates collection with 1536-dimension vectors (cosine distance) and payload indexes.
+// WithCollection returns a QdrantStorage that targets a specific collection while
+// sharing this instance's connection. Closing the returned storage is a no-op.
+func (s *QdrantStorage) WithCollection(name string) *QdrantStorage {
+	return &QdrantStorage{
+		client:     s.client,
+		host:       s.host,
+		port:       s.port,
+		collection: name,
+		shared:     true,
+	}
+}
+
+// Collection returns the alias or collection name this storage targets.
+func (s *QdrantStorage) Collection() string {
+	return s.collection
+}
+
+// EnsureCollection ensures the target collection exists with proper configuration,
+// creating it for spec or validating spec against the recorded embedding model.
+// For the CollectionName alias, a first generation is created and the alias pointed at it
+// when neither the alias nor a legacy collection of that name exists.
 // Idempotent - safe to call multiple times.
-func (s *QdrantStorage) EnsureCollection(ctx context.Context) error {
+func (s *QdrantStorage) EnsureCollection(ctx context.Context, spec EmbeddingSpec) error {
 	// Check if collection already exists
 	collections, err := s.client.ListCollections(ctx)
 	if err != nil {
@@ -94,50 +134,325 @@ func (s *QdrantStorage) EnsureCollection(ctx context.Context) error {
 
 	// Check if our collection exists
 	for _, name := range collections {
-		if name == CollectionName {
-			// Collection already exists, nothing to do
-			return nil
+		if name == s.collection {
+			// Collection already exists: validate the embedding model and upgrade it
+			return s.upgradeCollection(ctx, spec)
 		}
 	}
 
-	// Collection doesn't exist, create it with named vectors
-	// This allows parent documents (no vector) and chunks (with "content" vector) in same collection
-	err = s.client.CreateCollection(ctx, &qdrant.CreateCollection{
-		CollectionName: CollectionName,
+	// Check if our name is an alias for an existing generation
+	aliases, err := s.client.ListAliases(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list aliases: %w", err)
+	}
+	for _, alias := range aliases {
+		if alias.GetAliasName() == s.collection {
+			return s.upgradeCollection(ctx, spec)
+		}
+	}
+
+	if s.collection != CollectionName {
+		return s.createCollection(ctx, s.collection, spec)
+	}
+
+	// Fresh install: create the first generation and point the alias at it
+	generation, err := s.CreateGeneration(ctx, spec)
+	if err != nil {
+		return err
+	}
+	return s.PromoteGeneration(ctx, generation)
+}
+
+// createCollection creates a collection with named vectors and payload indexes,
+// and records the embedding spec in its meta point.
+func (s *QdrantStorage) createCollection(ctx context.Context, name string, spec EmbeddingSpec) error {
+	// Create it with named vectors
+	// This allows parent documents (no vector) and chunks (with "content" vector) in same collection.
+	// The sparse "bm25" vector holds term weights; Qdrant applies IDF at query time.
+	err := s.client.CreateCollection(ctx, &qdrant.CreateCollection{
+		CollectionName: name,
 		VectorsConfig: qdrant.NewVectorsConfigMap(map[string]*qdrant.VectorParams{
-			"content": {
-				Size:     VectorDimension,
+			denseVectorName: {
+				Size:     uint64(spec.Dimension),
 				Distance: qdrant.Distance_Cosine,
 			},
 		}),
+		SparseVectorsConfig: qdrant.NewSparseVectorsConfig(map[string]*qdrant.SparseVectorParams{
+			sparseVectorName: {
+				Modifier: qdrant.Modifier_Idf.Enum(),
+			},
+		}),
 	})
 	if err != nil {
 		return fmt.Errorf("failed to create collection: %w", err)
 	}
 
 	// Create payload indexes for all filterable fields
-	err = s.createPayloadIndexes(ctx)
+	err = s.createPayloadIndexes(ctx, name, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create payload indexes: %w", err)
+	}
+
+	if err := s.writeSpec(ctx, name, spec); err != nil {
+		return fmt.Errorf("failed to record embedding spec: %w", err)
+	}
+
+	return nil
+}
+
+// writeSpec stores the embedding spec in the meta point of a collection.
+func (s *QdrantStorage) writeSpec(ctx context.Context, collection string, spec EmbeddingSpec) error {
+	_, err := s.client.Upsert(ctx, &qdrant.UpsertPoints{
+		CollectionName: collection,
+		Wait:           qdrant.PtrOf(true),
+		Points: []*qdrant.PointStruct{{
+			Id: qdrant.NewIDUUID(metaPointID),
+			Vectors: qdrant.NewVectorsMap(map[string]*qdrant.Vector{
+				denseVectorName: qdrant.NewVector(make([]float32, spec.Dimension)...),
+			}),
+			Payload: qdrant.NewValueMap(map[string]any{
+				"type":                "meta",
+				"embedding_model":     spec.Model,
+				"embedding_dimension": spec.Dimension,
+			}),
+		}},
+	})
+	return err
+}
+
+// EmbeddingSpec returns the embedding model the collection was built with.
+func (s *QdrantStorage) EmbeddingSpec(ctx context.Context) (EmbeddingSpec, error) {
+	return s.readSpec(ctx)
+}
+
+// checkSpec validates want against the collection's recorded embedding spec, read
+// afresh in case the alias now points at another generation.
+func (s *QdrantStorage) checkSpec(ctx context.Context, want EmbeddingSpec) error {
+	recorded, err := s.readSpec(ctx)
+	if err != nil {
+		return err
+	}
+	return checkEmbeddingSpec(recorded, want)
+}
+
+// loadSpec returns the embedding spec for per-request dimension checks, from the
+// cache when it was read within specCacheTTL.
+func (s *QdrantStorage) loadSpec(ctx context.Context) (EmbeddingSpec, error) {
+	s.specMu.Lock()
+	spec, loaded := s.spec, s.specLoaded
+	s.specMu.Unlock()
+	if !loaded.IsZero() && time.Since(loaded) < specCacheTTL {
+		return spec, nil
+	}
+	return s.readSpec(ctx)
+}
+
+// SparseEnabled reports whether the collection has the "bm25" sparse vector. Collections
+// created before hybrid search lack it: chunks are stored without sparse vectors and
+// keyword search returns nothing, leaving dense search only, until a full sync builds
+// a new generation.
+func (s *QdrantStorage) SparseEnabled(ctx context.Context) (bool, error) {
+	if _, err := s.loadSpec(ctx); err != nil {
+		return false, err
+	}
+	s.specMu.Lock()
+	defer s.specMu.Unlock()
+	return s.sparse, nil
+}
+
+// invalidateSpec drops the cached embedding spec, e.g. after the alias is repointed.
+func (s *QdrantStorage) invalidateSpec() {
+	s.specMu.Lock()
+	defer s.specMu.Unlock()
+	s.spec = EmbeddingSpec{}
+	s.sparse = false
+	s.specLoaded = time.Time{}
+}
+
+// readSpec reads the embedding spec from the meta point and caches it, along with
+// whether the collection has the sparse vector. Collections created before specs were
+// recorded have no meta point; their dimension comes from the vector config and the
+// model is assumed to be the legacy default.
+func (s *QdrantStorage) readSpec(ctx context.Context) (EmbeddingSpec, error) {
+	info, err := s.client.GetCollectionInfo(ctx, s.collection)
+	if err != nil {
+		return EmbeddingSpec{}, fmt.Errorf("failed to get collection: %w", err)
+	}
+	_, sparse := info.GetConfig().GetParams().GetSparseVectorsConfig().GetMap()[sparseVectorName]
+
+	points, err := s.client.Get(ctx, &qdrant.GetPoints{
+		CollectionName: s.collection,
+		Ids:            []*qdrant.PointId{qdrant.NewIDUUID(metaPointID)},
+		WithPayload:    qdrant.NewWithPayload(true),
+	})
+	if err != nil {
+		return EmbeddingSpec{}, fmt.Errorf("failed to read embedding spec: %w", err)
+	}
+
+	var spec EmbeddingSpec
+	if len(points) > 0 {
+		payload := points[0].Payload
+		spec = EmbeddingSpec{
+			Model:     payload["embedding_model"].GetStringValue(),
+			Dimension: int(payload["embedding_dimension"].GetIntegerValue()),
+		}
+	} else {
+		params := info.GetConfig().GetParams().GetVectorsConfig().GetParamsMap().GetMap()[denseVectorName]
+		spec = EmbeddingSpec{Dimension: int(params.GetSize())}
+		if spec.Dimension == LegacyEmbeddingSpec.Dimension {
+			spec.Model = LegacyEmbeddingSpec.Model
+		}
+	}
+
+	s.specMu.Lock()
+	defer s.specMu.Unlock()
+	s.spec = spec
+	s.sparse = sparse
+	s.specLoaded = time.Now()
+	return spec, nil
+}
+
+// upgradeCollection validates spec against an existing collection and brings the
+// collection up to date with fields added since it was created: missing payload
+// indexes are created and chunks indexed without the filter fields are backfilled,
+// so filtered searches do not silently miss them.
+func (s *QdrantStorage) upgradeCollection(ctx context.Context, spec EmbeddingSpec) error {
+	if err := s.checkSpec(ctx, spec); err != nil {
+		return err
+	}
+
+	info, err := s.client.GetCollectionInfo(ctx, s.collection)
 	if err != nil {
+		return fmt.Errorf("failed to get collection: %w", err)
+	}
+	if err := s.createPayloadIndexes(ctx, s.collection, info.GetPayloadSchema()); err != nil {
 		return fmt.Errorf("failed to create payload indexes: %w", err)
 	}
+	if err := s.backfillChunkFilters(ctx); err != nil {
+		return fmt.Errorf("failed to backfill chunk filter fields: %w", err)
+	}
+	if err := s.backfillSource(ctx); err != nil {
+		return fmt.Errorf("failed to backfill document sources: %w", err)
+	}
+	return nil
+}
+
+// backfillSource assigns LegacySource to documents and chunks stored before sources
+// were configured, when the Eino docs were the only ones indexed.
+func (s *QdrantStorage) backfillSource(ctx context.Context) error {
+	_, err := s.client.SetPayload(ctx, &qdrant.SetPayloadPoints{
+		CollectionName: s.collection,
+		Payload: qdrant.NewValueMap(map[string]any{
+			"source": LegacySource,
+		}),
+		PointsSelector: qdrant.NewPointsSelectorFilter(&qdrant.Filter{
+			Must: []*qdrant.Condition{
+				qdrant.NewMatchKeywords("type", "parent", "chunk"),
+				qdrant.NewIsEmpty("source"),
+			},
+		}),
+		Wait: qdrant.PtrOf(true),
+	})
+	return err
+}
+
+// backfillChunkFilters sets category, dirs and entities on chunks stored before those
+// fields existed. Entities are copied from each chunk's parent document.
+func (s *QdrantStorage) backfillChunkFilters(ctx context.Context) error {
+	filter := &qdrant.Filter{
+		Must: []*qdrant.Condition{
+			qdrant.NewMatch("type", "chunk"),
+			qdrant.NewIsEmpty("category"),
+		},
+	}
+
+	// Group the chunks by parent document
+	chunkIDs := make(map[string][]*qdrant.PointId)
+	parentPaths := make(map[string]string)
+	seen := make(map[string]bool)
+	batchSize := uint32(100)
+	var offset *qdrant.PointId
+	for {
+		results, err := s.client.Scroll(ctx, &qdrant.ScrollPoints{
+			CollectionName: s.collection,
+			Filter:         filter,
+			Limit:          qdrant.PtrOf(batchSize),
+			Offset:         offset,
+			WithPayload:    qdrant.NewWithPayloadInclude("parent_doc_id", "path"),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to scroll chunks: %w", err)
+		}
+
+		for _, result := range results {
+			id := result.Id.GetUuid()
+			if seen[id] {
+				continue // The offset point starts the next page too
+			}
+			seen[id] = true
+			parentID := result.Payload["parent_doc_id"].GetStringValue()
+			chunkIDs[parentID] = append(chunkIDs[parentID], result.Id)
+			parentPaths[parentID] = result.Payload["path"].GetStringValue()
+		}
+
+		if uint32(len(results)) < batchSize {
+			break
+		}
+		offset = results[len(results)-1].Id
+	}
 
+	for parentID, ids := range chunkIDs {
+		var entities []string
+		doc, err := s.GetDocument(ctx, parentID)
+		switch {
+		case err == nil:
+			entities = doc.Metadata.Entities
+		case !errors.Is(err, ErrDocumentNotFound):
+			return err
+		}
+
+		path := parentPaths[parentID]
+		_, err = s.client.SetPayload(ctx, &qdrant.SetPayloadPoints{
+			CollectionName: s.collection,
+			Payload: qdrant.NewValueMap(map[string]any{
+				"category": Category(path),
+				"dirs":     stringList(pathDirs(path)),
+				"entities": stringList(entities),
+			}),
+			PointsSelector: qdrant.NewPointsSelectorIDs(ids),
+			Wait:           qdrant.PtrOf(true),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to update chunks of %s: %w", path, err)
+		}
+	}
 	return nil
 }
 
 // createPayloadIndexes creates indexes for all filterable fields.
 // CRITICAL: Without these indexes, filtering becomes 10-100x slower.
-func (s *QdrantStorage) createPayloadIndexes(ctx context.Context) error {
+// Fields already in existing are skipped.
+func (s *QdrantStorage) createPayloadIndexes(ctx context.Context, collection string, existing map[string]*qdrant.PayloadSchemaInfo) error {
 	fields := []string{
 		"path",          // Filter documents by file path
 		"repository",    // Filter by repository
+		"source",        // Filter by documentation source
 		"commit_sha",    // Filter by commit
 		"type",          // Distinguish "parent" vs "chunk"
 		"parent_doc_id", // Lookup chunks by parent
+		"aliases",       // Resolve Hugo aliases in fetch_doc
+		"category",      // Filter chunks by top-level directory
+		"dirs",          // Filter chunks by path prefix
+		"entities",      // Filter chunks by entity
+		"symbol_keys",   // Look up Go symbols by name
 	}
 
 	for _, field := range fields {
+		if _, ok := existing[field]; ok {
+			continue
+		}
 		_, err := s.client.CreateFieldIndex(ctx, &qdrant.CreateFieldIndexCollection{
-			CollectionName: CollectionName,
+			CollectionName: collection,
 			FieldName:      field,
 			FieldType:      qdrant.FieldType_FieldTypeKeyword.Enum(),
 		})
@@ -149,22 +464,26 @@ func (s *QdrantStorage) createPayloadIndexes(ctx context.Context) error {
 	return nil
 }
 
-// ClearCollection deletes all points in the collection.
-// Useful for re-indexing scenarios.
+// ClearCollection deletes all documents and chunks, keeping the collection configuration
+// and embedding spec (the vector size of a Qdrant collection is fixed at creation).
+// Works through aliases; full syncs build a fresh generation instead.
 func (s *QdrantStorage) ClearCollection(ctx context.Context) error {
-	// Delete collection and recreate it
-	err := s.client.DeleteCollection(ctx, CollectionName)
+	_, err := s.client.Delete(ctx, &qdrant.DeletePoints{
+		CollectionName: s.collection,
+		Points: qdrant.NewPointsSelectorFilter(&qdrant.Filter{
+			MustNot: []*qdrant.Condition{qdrant.NewMatch("type", "meta")},
+		}),
+		Wait: qdrant.PtrOf(true),
+	})
 	if err != nil {
-		return fmt.Errorf("failed to delete collection: %w", err)
+		return fmt.Errorf("failed to clear collection: %w", err)
 	}
-
-	// Recreate with proper configuration
-	return s.EnsureCollection(ctx)
+	return nil
 }
 
 // Close closes the Qdrant client connection.
 func (s *QdrantStorage) Close() error {
-	if s.client != nil {
+	if s.client != nil && !s.shared {
 		return s.client.Close()
 	}
 	return nil
@@ -179,7 +498,7 @@ func (s *QdrantStorage) upsertWithRetry(ctx context.Context, points []*qdrant.Po
 
 	operation := func() error {
 		_, err := s.client.Upsert(ctx, &qdrant.UpsertPoints{
-			CollectionName: CollectionName,
+			CollectionName: s.collection,
 			Points:         points,
 		})
 		return err
@@ -191,17 +510,44 @@ func (s *QdrantStorage) upsertWithRetry(ctx context.Context, points []*qdrant.Po
 // UpsertDocument stores a parent document in Qdrant.
 // Parent documents have no embedding vector - they exist for full-content retrieval.
 func (s *QdrantStorage) UpsertDocument(ctx context.Context, doc *Document) error {
+	spec, err := s.loadSpec(ctx)
+	if err != nil {
+		return err
+	}
+
 	// Build payload map
 	payload := map[string]any{
-		"type":       "parent",
-		"content":    doc.Content,
-		"path":       doc.Metadata.Path,
-		"url":        doc.Metadata.URL,
-		"repository": doc.Metadata.Repository,
-		"commit_sha": doc.Metadata.CommitSHA,
-		"indexed_at": doc.Metadata.IndexedAt.Format(time.RFC3339),
-		"summary":    doc.Metadata.Summary,
+		"type":        "parent",
+		"content":     doc.Content,
+		"path":        doc.Metadata.Path,
+		"url":         doc.Metadata.URL,
+		"page_url":    doc.Metadata.PageURL,
+		"repository":  doc.Metadata.Repository,
+		"source":      doc.Metadata.Source,
+		"commit_sha":  doc.Metadata.CommitSHA,
+		"blob_sha":    doc.Metadata.BlobSHA,
+		"indexed_at":  doc.Metadata.IndexedAt.Format(time.RFC3339),
+		"summary":     doc.Metadata.Summary,
+		"title":       doc.Metadata.Title,
+		"description": doc.Metadata.Description,
+		"weight":      doc.Metadata.Weight,
+		"date":        "",
 	}
+	if !doc.Metadata.Date.IsZero() {
+		payload["date"] = doc.Metadata.Date.Format(time.RFC3339)
+	}
+
+	aliases := make([]interface{}, len(doc.Metadata.Aliases))
+	for i, alias := range doc.Metadata.Aliases {
+		aliases[i] = NormalizeAlias(alias)
+	}
+	payload["aliases"] = aliases
+
+	links := make([]interface{}, len(doc.Metadata.Links))
+	for i, link := range doc.Metadata.Links {
+		links[i] = link
+	}
+	payload["links"] = links
 
 	// Add entities as interface slice (NewValueMap will handle conversion)
 	if len(doc.Metadata.Entities) > 0 {
@@ -216,11 +562,11 @@ func (s *QdrantStorage) UpsertDocument(ctx context.Context, doc *Document) error
 
 	// Parent documents need a zero vector for the named vector collection
 	// (Qdrant requires all points to have vectors when using named vectors)
-	zeroVector := make([]float32, VectorDimension)
+	zeroVector := make([]float32, spec.Dimension)
 	point := &qdrant.PointStruct{
 		Id:      qdrant.NewIDUUID(doc.ID),
 		Vectors: qdrant.NewVectorsMap(map[string]*qdrant.Vector{
-			"content": qdrant.NewVector(zeroVector...),
+			denseVectorName: qdrant.NewVector(zeroVector...),
 		}),
 		Payload: qdrant.NewValueMap(payload),
 	}
@@ -236,12 +582,19 @@ func (s *QdrantStorage) UpsertChunks(ctx context.Context, chunks []*Chunk) error
 	}
 
 	// Validate embedding dimensions
+	spec, err := s.loadSpec(ctx)
+	if err != nil {
+		return err
+	}
 	for i, chunk := range chunks {
-		if len(chunk.Embedding) != VectorDimension {
-			return fmt.Errorf("%w: chunk %d has %d dimensions, expected %d",
-				ErrDimensionMismatch, i, len(chunk.Embedding), VectorDimension)
+		if err := checkDimension(spec, len(chunk.Embedding), fmt.Sprintf("chunk %d", i)); err != nil {
+			return err
 		}
 	}
+	sparse, err := s.SparseEnabled(ctx)
+	if err != nil {
+		return err
+	}
 
 	// Batch upserts in groups of 100
 	batchSize := 100
@@ -255,11 +608,16 @@ func (s *QdrantStorage) UpsertChunks(ctx context.Context, chunks []*Chunk) error
 		points := make([]*qdrant.PointStruct, len(batch))
 
 		for j, chunk := range batch {
+			vectors := map[string]*qdrant.Vector{
+				denseVectorName: qdrant.NewVector(chunk.Embedding...),
+			}
+			if sparse && len(chunk.Sparse.Indices) > 0 {
+				vectors[sparseVectorName] = qdrant.NewVectorSparse(chunk.Sparse.Indices, chunk.Sparse.Values)
+			}
+
 			points[j] = &qdrant.PointStruct{
-				Id: qdrant.NewIDUUID(chunk.ID),
-				Vectors: qdrant.NewVectorsMap(map[string]*qdrant.Vector{
-					"content": qdrant.NewVector(chunk.Embedding...),
-				}),
+				Id:      qdrant.NewIDUUID(chunk.ID),
+				Vectors: qdrant.NewVectorsMap(vectors),
 				Payload: qdrant.NewValueMap(map[string]any{
 					"type":          "chunk",
 					"parent_doc_id": chunk.ParentDocID,
@@ -268,6 +626,10 @@ func (s *QdrantStorage) UpsertChunks(ctx context.Context, chunks []*Chunk) error
 					"content":       chunk.Content,
 					"path":          chunk.Path,
 					"repository":    chunk.Repository,
+					"source":        chunk.Source,
+					"category":      Category(chunk.Path),
+					"dirs":          stringList(pathDirs(chunk.Path)),
+					"entities":      stringList(chunk.Entities),
 				}),
 			}
 		}
@@ -285,7 +647,7 @@ func (s *QdrantStorage) UpsertChunks(ctx context.Context, chunks []*Chunk) error
 // Returns ErrDocumentNotFound if document doesn't exist.
 func (s *QdrantStorage) GetDocument(ctx context.Context, id string) (*Document, error) {
 	result, err := s.client.Get(ctx, &qdrant.GetPoints{
-		CollectionName: CollectionName,
+		CollectionName: s.collection,
 		Ids:            []*qdrant.PointId{qdrant.NewIDUUID(id)},
 		WithPayload:    qdrant.NewWithPayload(true),
 	})
@@ -298,14 +660,18 @@ func (s *QdrantStorage) GetDocument(ctx context.Context, id string) (*Document,
 	}
 
 	point := result[0]
-	payload := point.Payload
 
 	// Verify this is a parent document
-	typeVal, ok := payload["type"]
+	typeVal, ok := point.Payload["type"]
 	if !ok || typeVal.GetStringValue() != "parent" {
 		return nil, ErrDocumentNotFound
 	}
 
+	return documentFromPayload(id, point.Payload), nil
+}
+
+// documentFromPayload converts a parent point payload into a Document.
+func documentFromPayload(id string, payload map[string]*qdrant.Value) *Document {
 	// Parse indexed_at timestamp
 	indexedAt, err := time.Parse(time.RFC3339, payload["indexed_at"].GetStringValue())
 	if err != nil {
@@ -320,37 +686,60 @@ func (s *QdrantStorage) GetDocument(ctx context.Context, id string) (*Document,
 		}
 	}
 
-	doc := &Document{
+	// Front matter fields; documents indexed before they existed leave them empty
+	date, _ := time.Parse(time.RFC3339, payload["date"].GetStringValue())
+	var aliases []string
+	if aliasesVal, ok := payload["aliases"]; ok && aliasesVal.GetListValue() != nil {
+		for _, val := range aliasesVal.GetListValue().Values {
+			aliases = append(aliases, val.GetStringValue())
+		}
+	}
+	var links []string
+	for _, val := range payload["links"].GetListValue().GetValues() {
+		links = append(links, val.GetStringValue())
+	}
+
+	return &Document{
 		ID:      id,
 		Content: payload["content"].GetStringValue(),
 		Metadata: DocumentMetadata{
-			Path:       payload["path"].GetStringValue(),
-			URL:        payload["url"].GetStringValue(),
-			Repository: payload["repository"].GetStringValue(),
-			CommitSHA:  payload["commit_sha"].GetStringValue(),
-			IndexedAt:  indexedAt,
-			Summary:    payload["summary"].GetStringValue(),
-			Entities:   entities,
+			Path:        payload["path"].GetStringValue(),
+			URL:         payload["url"].GetStringValue(),
+			PageURL:     payload["page_url"].GetStringValue(),
+			Repository:  payload["repository"].GetStringValue(),
+			Source:      payload["source"].GetStringValue(),
+			CommitSHA:   payload["commit_sha"].GetStringValue(),
+			BlobSHA:     payload["blob_sha"].GetStringValue(),
+			IndexedAt:   indexedAt,
+			Summary:     payload["summary"].GetStringValue(),
+			Entities:    entities,
+			Title:       payload["title"].GetStringValue(),
+			Description: payload["description"].GetStringValue(),
+			Weight:      int(payload["weight"].GetIntegerValue()),
+			Date:        date,
+			Aliases:     aliases,
+			Links:       links,
 		},
 	}
-
-	return doc, nil
 }
 
 // SearchChunks performs vector similarity search on chunks.
 // Returns top N chunks ordered by similarity score.
-func (s *QdrantStorage) SearchChunks(ctx context.Context, embedding []float32, limit int, repository string) ([]*Chunk, error) {
-	if len(embedding) != VectorDimension {
-		return nil, fmt.Errorf("%w: query has %d dimensions, expected %d",
-			ErrDimensionMismatch, len(embedding), VectorDimension)
+func (s *QdrantStorage) SearchChunks(ctx context.Context, embedding []float32, limit int, source string) ([]*Chunk, error) {
+	spec, err := s.loadSpec(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkDimension(spec, len(embedding), "query"); err != nil {
+		return nil, err
 	}
 
 	// Build filter conditions
 	must := []*qdrant.Condition{
 		qdrant.NewMatch("type", "chunk"),
 	}
-	if repository != "" {
-		must = append(must, qdrant.NewMatch("repository", repository))
+	if source != "" {
+		must = append(must, qdrant.NewMatch("source", source))
 	}
 
 	filter := &qdrant.Filter{
@@ -358,9 +747,9 @@ func (s *QdrantStorage) SearchChunks(ctx context.Context, embedding []float32, l
 	}
 
 	// Perform vector search using named vector "content"
-	vectorName := "content"
+	vectorName := denseVectorName
 	results, err := s.client.Query(ctx, &qdrant.QueryPoints{
-		CollectionName: CollectionName,
+		CollectionName: s.collection,
 		Query:          qdrant.NewQuery(embedding...),
 		Using:          &vectorName,
 		Filter:         filter,
@@ -373,19 +762,8 @@ func (s *QdrantStorage) SearchChunks(ctx context.Context, embedding []float32, l
 
 	chunks := make([]*Chunk, 0, len(results))
 	for _, result := range results {
-		payload := result.Payload
-
-		chunk := &Chunk{
-			ID:          result.Id.GetUuid(),
-			ParentDocID: payload["parent_doc_id"].GetStringValue(),
-			ChunkIndex:  int(payload["chunk_index"].GetIntegerValue()),
-			HeaderPath:  payload["header_path"].GetStringValue(),
-			Content:     payload["content"].GetStringValue(),
-			Path:        payload["path"].GetStringValue(),
-			Repository:  payload["repository"].GetStringValue(),
-			// Note: Embedding not returned in search results (not needed)
-		}
-		chunks = append(chunks, chunk)
+		// Note: Embedding not returned in search results (not needed)
+		chunks = append(chunks, chunkFromPayload(result.Id.GetUuid(), result.Payload))
 	}
 
 	return chunks, nil
@@ -394,31 +772,22 @@ func (s *QdrantStorage) SearchChunks(ctx context.Context, embedding []float32, l
 // SearchChunksWithScores performs vector similarity search on chunks.
 // Returns top N chunks with similarity scores, ordered by score descending.
 // This replaces SearchChunks for MCP handlers that need relevance scores.
-func (s *QdrantStorage) SearchChunksWithScores(ctx context.Context, embedding []float32, limit int, repository string) ([]*ScoredChunk, error) {
-	if len(embedding) != VectorDimension {
-		return nil, fmt.Errorf("%w: query has %d dimensions, expected %d",
-			ErrDimensionMismatch, len(embedding), VectorDimension)
-	}
-
-	// Build filter conditions
-	must := []*qdrant.Condition{
-		qdrant.NewMatch("type", "chunk"),
-	}
-	if repository != "" {
-		must = append(must, qdrant.NewMatch("repository", repository))
+func (s *QdrantStorage) SearchChunksWithScores(ctx context.Context, embedding []float32, limit int, filter SearchFilter) ([]*ScoredChunk, error) {
+	spec, err := s.loadSpec(ctx)
+	if err != nil {
+		return nil, err
 	}
-
-	filter := &qdrant.Filter{
-		Must: must,
+	if err := checkDimension(spec, len(embedding), "query"); err != nil {
+		return nil, err
 	}
 
 	// Perform vector search using named vector "content"
-	vectorName := "content"
+	vectorName := denseVectorName
 	results, err := s.client.Query(ctx, &qdrant.QueryPoints{
-		CollectionName: CollectionName,
+		CollectionName: s.collection,
 		Query:          qdrant.NewQuery(embedding...),
 		Using:          &vectorName,
-		Filter:         filter,
+		Filter:         chunkFilter(filter),
 		Limit:          qdrant.PtrOf(uint64(limit)),
 		WithPayload:    qdrant.NewWithPayload(true),
 		WithVectors:    qdrant.NewWithVectors(false), // Don't need vectors in response
@@ -427,39 +796,134 @@ func (s *QdrantStorage) SearchChunksWithScores(ctx context.Context, embedding []
 		return nil, fmt.Errorf("failed to search chunks: %w", err)
 	}
 
+	return scoredChunksFromResults(results), nil
+}
+
+// SearchChunksSparse performs BM25 keyword search on chunks using the sparse "bm25" vector.
+// Returns top N chunks that share at least one term with the query, ordered by score descending.
+// Scores are unbounded BM25 values, not similarities. Collections without the sparse
+// vector return no results, so hybrid search falls back to dense results.
+func (s *QdrantStorage) SearchChunksSparse(ctx context.Context, query SparseVector, limit int, filter SearchFilter) ([]*ScoredChunk, error) {
+	if len(query.Indices) == 0 {
+		return []*ScoredChunk{}, nil
+	}
+	sparse, err := s.SparseEnabled(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !sparse {
+		return []*ScoredChunk{}, nil
+	}
+
+	vectorName := sparseVectorName
+	results, err := s.client.Query(ctx, &qdrant.QueryPoints{
+		CollectionName: s.collection,
+		Query:          qdrant.NewQuerySparse(query.Indices, query.Values),
+		Using:          &vectorName,
+		Filter:         chunkFilter(filter),
+		Limit:          qdrant.PtrOf(uint64(limit)),
+		WithPayload:    qdrant.NewWithPayload(true),
+		WithVectors:    qdrant.NewWithVectors(false),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to search chunks (sparse): %w", err)
+	}
+
+	return scoredChunksFromResults(results), nil
+}
+
+// chunkFilter builds the Qdrant payload filter for a chunk search: chunks matching
+// filter, or any symbol when the filter lets symbols through.
+func chunkFilter(filter SearchFilter) *qdrant.Filter {
+	must := []*qdrant.Condition{
+		qdrant.NewMatch("type", "chunk"),
+	}
+	if filter.Source != "" {
+		must = append(must, qdrant.NewMatch("source", filter.Source))
+	}
+	if prefix := normalizePrefix(filter.PathPrefix); prefix != "" {
+		must = append(must, qdrant.NewMatch("dirs", prefix))
+	}
+	if len(filter.Categories) > 0 {
+		must = append(must, qdrant.NewMatchKeywords("category", filter.Categories...))
+	}
+	if len(filter.Entities) > 0 {
+		must = append(must, qdrant.NewMatchKeywords("entities", filter.Entities...))
+	}
+	if !filter.matchesSymbols() {
+		return &qdrant.Filter{Must: must}
+	}
+	return &qdrant.Filter{Should: []*qdrant.Condition{
+		qdrant.NewFilterAsCondition(&qdrant.Filter{Must: must}),
+		qdrant.NewMatch("type", "symbol"),
+	}}
+}
+
+// stringList converts strings into a payload list value.
+func stringList(values []string) []interface{} {
+	list := make([]interface{}, len(values))
+	for i, v := range values {
+		list[i] = v
+	}
+	return list
+}
+
+// scoredChunksFromResults converts Qdrant query results into scored chunks. Symbol
+// points become chunks carrying the symbol (see Symbol.searchChunk).
+func scoredChunksFromResults(results []*qdrant.ScoredPoint) []*ScoredChunk {
 	scoredChunks := make([]*ScoredChunk, 0, len(results))
 	for _, result := range results {
-		payload := result.Payload
-
-		chunk := &Chunk{
-			ID:          result.Id.GetUuid(),
-			ParentDocID: payload["parent_doc_id"].GetStringValue(),
-			ChunkIndex:  int(payload["chunk_index"].GetIntegerValue()),
-			HeaderPath:  payload["header_path"].GetStringValue(),
-			Content:     payload["content"].GetStringValue(),
-			Path:        payload["path"].GetStringValue(),
-			Repository:  payload["repository"].GetStringValue(),
+		chunk := chunkFromPayload(result.Id.GetUuid(), result.Payload)
+		if result.Payload["type"].GetStringValue() == "symbol" {
+			chunk = symbolFromPayload(result.Id.GetUuid(), result.Payload).searchChunk()
 		}
-
 		scoredChunks = append(scoredChunks, &ScoredChunk{
 			Chunk: chunk,
 			Score: float64(result.Score), // Qdrant returns float32, convert to float64
 		})
 	}
+	return scoredChunks
+}
+
+// afterOffset drops the point a scroll page starts with if it is the page's offset:
+// Qdrant's scroll offset is inclusive, so that point already ended the previous page.
+func afterOffset(results []*qdrant.RetrievedPoint, offset *qdrant.PointId) []*qdrant.RetrievedPoint {
+	if offset != nil && len(results) > 0 &&
+		results[0].Id.GetUuid() == offset.GetUuid() && results[0].Id.GetNum() == offset.GetNum() {
+		return results[1:]
+	}
+	return results
+}
 
-	return scoredChunks, nil
+// chunkFromPayload converts a chunk point payload into a Chunk (without vectors).
+func chunkFromPayload(id string, payload map[string]*qdrant.Value) *Chunk {
+	var entities []string
+	for _, val := range payload["entities"].GetListValue().GetValues() {
+		entities = append(entities, val.GetStringValue())
+	}
+	return &Chunk{
+		ID:          id,
+		ParentDocID: payload["parent_doc_id"].GetStringValue(),
+		ChunkIndex:  int(payload["chunk_index"].GetIntegerValue()),
+		HeaderPath:  payload["header_path"].GetStringValue(),
+		Content:     payload["content"].GetStringValue(),
+		Path:        payload["path"].GetStringValue(),
+		Repository:  payload["repository"].GetStringValue(),
+		Source:      payload["source"].GetStringValue(),
+		Entities:    entities,
+	}
 }
 
-// GetCommitSHA retrieves the commit SHA for indexed content from a repository.
-// Returns empty string if no documents found for the repository.
-func (s *QdrantStorage) GetCommitSHA(ctx context.Context, repository string) (string, error) {
-	// Scroll for any parent document from this repository (no vector search needed)
+// GetCommitSHA retrieves the commit SHA for indexed content from a source.
+// Returns empty string if no documents found for the source.
+func (s *QdrantStorage) GetCommitSHA(ctx context.Context, source string) (string, error) {
+	// Scroll for any parent document from this source (no vector search needed)
 	results, err := s.client.Scroll(ctx, &qdrant.ScrollPoints{
-		CollectionName: CollectionName,
+		CollectionName: s.collection,
 		Filter: &qdrant.Filter{
 			Must: []*qdrant.Condition{
 				qdrant.NewMatch("type", "parent"),
-				qdrant.NewMatch("repository", repository),
+				qdrant.NewMatch("source", source),
 			},
 		},
 		Limit:       qdrant.PtrOf(uint32(1)),
@@ -470,7 +934,7 @@ func (s *QdrantStorage) GetCommitSHA(ctx context.Context, repository string) (st
 	}
 
 	if len(results) == 0 {
-		return "", nil // No documents found for this repository
+		return "", nil // No documents found for this source
 	}
 
 	commitSHA := results[0].Payload["commit_sha"].GetStringValue()
@@ -479,7 +943,7 @@ func (s *QdrantStorage) GetCommitSHA(ctx context.Context, repository string) (st
 
 // ListDocumentPaths returns all unique document paths in the index.
 // Uses Scroll API to iterate through all parent documents.
-func (s *QdrantStorage) ListDocumentPaths(ctx context.Context, repository string) ([]string, error) {
+func (s *QdrantStorage) ListDocumentPaths(ctx context.Context, source string) ([]string, error) {
 	paths := []string{} // Initialize as empty slice, not nil (nil marshals to JSON null)
 	var offset *qdrant.PointId
 
@@ -487,8 +951,8 @@ func (s *QdrantStorage) ListDocumentPaths(ctx context.Context, repository string
 	must := []*qdrant.Condition{
 		qdrant.NewMatch("type", "parent"),
 	}
-	if repository != "" {
-		must = append(must, qdrant.NewMatch("repository", repository))
+	if source != "" {
+		must = append(must, qdrant.NewMatch("source", source))
 	}
 
 	filter := &qdrant.Filter{
@@ -500,7 +964,7 @@ func (s *QdrantStorage) ListDocumentPaths(ctx context.Context, repository string
 	// Scroll through all parent documents
 	for {
 		results, err := s.client.Scroll(ctx, &qdrant.ScrollPoints{
-			CollectionName: CollectionName,
+			CollectionName: s.collection,
 			Filter:         filter,
 			Limit:          qdrant.PtrOf(batchSize),
 			Offset:         offset,
@@ -532,14 +996,14 @@ func (s *QdrantStorage) ListDocumentPaths(ctx context.Context, repository string
 
 // GetDocumentByPath retrieves a parent document by its path.
 // Returns ErrDocumentNotFound if no document exists with the given path.
-func (s *QdrantStorage) GetDocumentByPath(ctx context.Context, path string, repository string) (*Document, error) {
+func (s *QdrantStorage) GetDocumentByPath(ctx context.Context, path string, source string) (*Document, error) {
 	// Build filter for parent document with matching path
 	must := []*qdrant.Condition{
 		qdrant.NewMatch("type", "parent"),
 		qdrant.NewMatch("path", path),
 	}
-	if repository != "" {
-		must = append(must, qdrant.NewMatch("repository", repository))
+	if source != "" {
+		must = append(must, qdrant.NewMatch("source", source))
 	}
 
 	filter := &qdrant.Filter{
@@ -547,7 +1011,7 @@ func (s *QdrantStorage) GetDocumentByPath(ctx context.Context, path string, repo
 	}
 
 	results, err := s.client.Scroll(ctx, &qdrant.ScrollPoints{
-		CollectionName: CollectionName,
+		CollectionName: s.collection,
 		Filter:         filter,
 		Limit:          qdrant.PtrOf(uint32(1)),
 		WithPayload:    qdrant.NewWithPayload(true),
@@ -561,58 +1025,643 @@ func (s *QdrantStorage) GetDocumentByPath(ctx context.Context, path string, repo
 	}
 
 	point := results[0]
-	payload := point.Payload
+	return documentFromPayload(point.Id.GetUuid(), point.Payload), nil
+}
 
-	// Parse indexed_at timestamp
-	indexedAt, err := time.Parse(time.RFC3339, payload["indexed_at"].GetStringValue())
-	if err != nil {
-		indexedAt = time.Time{} // Use zero time if parse fails
+// GetDocumentByAlias retrieves a parent document by one of its Hugo aliases.
+// The alias is normalized with NormalizeAlias before matching.
+// Returns ErrDocumentNotFound if no document declares the alias.
+func (s *QdrantStorage) GetDocumentByAlias(ctx context.Context, alias string, source string) (*Document, error) {
+	must := []*qdrant.Condition{
+		qdrant.NewMatch("type", "parent"),
+		qdrant.NewMatch("aliases", NormalizeAlias(alias)),
+	}
+	if source != "" {
+		must = append(must, qdrant.NewMatch("source", source))
 	}
 
-	// Extract entities
-	var entities []string
-	if entitiesVal, ok := payload["entities"]; ok && entitiesVal.GetListValue() != nil {
-		for _, val := range entitiesVal.GetListValue().Values {
-			entities = append(entities, val.GetStringValue())
-		}
+	results, err := s.client.Scroll(ctx, &qdrant.ScrollPoints{
+		CollectionName: s.collection,
+		Filter:         &qdrant.Filter{Must: must},
+		Limit:          qdrant.PtrOf(uint32(1)),
+		WithPayload:    qdrant.NewWithPayload(true),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query document by alias: %w", err)
 	}
 
-	doc := &Document{
-		ID:      point.Id.GetUuid(),
-		Content: payload["content"].GetStringValue(),
-		Metadata: DocumentMetadata{
-			Path:       payload["path"].GetStringValue(),
-			URL:        payload["url"].GetStringValue(),
-			Repository: payload["repository"].GetStringValue(),
-			CommitSHA:  payload["commit_sha"].GetStringValue(),
-			IndexedAt:  indexedAt,
-			Summary:    payload["summary"].GetStringValue(),
-			Entities:   entities,
-		},
+	if len(results) == 0 {
+		return nil, ErrDocumentNotFound
 	}
 
-	return doc, nil
+	point := results[0]
+	return documentFromPayload(point.Id.GetUuid(), point.Payload), nil
 }
 
-// CollectionInfo contains collection statistics
-type CollectionInfo struct {
-	PointsCount uint64
-}
+// GetDocumentChunks returns the chunks of the document at path ordered by chunk index,
+// without vectors. Returns an empty slice if the path is not indexed.
+func (s *QdrantStorage) GetDocumentChunks(ctx context.Context, path string, source string) ([]*Chunk, error) {
+	chunks := []*Chunk{}
+	var offset *qdrant.PointId
 
-// GetCollectionInfo retrieves collection statistics including total points count.
-// Used for calculating total chunks in the index.
-func (s *QdrantStorage) GetCollectionInfo(ctx context.Context) (*CollectionInfo, error) {
-	collection, err := s.client.GetCollectionInfo(ctx, CollectionName)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get collection: %w", err)
+	must := []*qdrant.Condition{
+		qdrant.NewMatch("type", "chunk"),
+		qdrant.NewMatch("path", path),
 	}
-
-	pointsCount := uint64(0)
-	if collection.PointsCount != nil {
-		pointsCount = *collection.PointsCount
+	if source != "" {
+		must = append(must, qdrant.NewMatch("source", source))
 	}
 
-	return &CollectionInfo{
-		PointsCount: pointsCount,
-	}, nil
+	batchSize := uint32(100)
+
+	for {
+		results, err := s.client.Scroll(ctx, &qdrant.ScrollPoints{
+			CollectionName: s.collection,
+			Filter:         &qdrant.Filter{Must: must},
+			Limit:          qdrant.PtrOf(batchSize),
+			Offset:         offset,
+			WithPayload:    qdrant.NewWithPayload(true),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to scroll chunks: %w", err)
+		}
+
+		for _, result := range afterOffset(results, offset) {
+			chunks = append(chunks, chunkFromPayload(result.Id.GetUuid(), result.Payload))
+		}
+
+		if uint32(len(results)) < batchSize {
+			break
+		}
+		offset = results[len(results)-1].Id
+	}
+
+	sort.Slice(chunks, func(i, j int) bool { return chunks[i].ChunkIndex < chunks[j].ChunkIndex })
+	return chunks, nil
+}
+
+// GetDocumentEmbeddings returns the dense vectors of the chunks of the document at path.
+// Returns an empty slice if the path is not indexed.
+func (s *QdrantStorage) GetDocumentEmbeddings(ctx context.Context, path string, source string) ([][]float32, error) {
+	embeddings := [][]float32{}
+	var offset *qdrant.PointId
+
+	must := []*qdrant.Condition{
+		qdrant.NewMatch("type", "chunk"),
+		qdrant.NewMatch("path", path),
+	}
+	if source != "" {
+		must = append(must, qdrant.NewMatch("source", source))
+	}
+
+	batchSize := uint32(100)
+
+	for {
+		results, err := s.client.Scroll(ctx, &qdrant.ScrollPoints{
+			CollectionName: s.collection,
+			Filter:         &qdrant.Filter{Must: must},
+			Limit:          qdrant.PtrOf(batchSize),
+			Offset:         offset,
+			WithPayload:    qdrant.NewWithPayload(false),
+			WithVectors:    qdrant.NewWithVectorsInclude(denseVectorName),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to scroll chunk vectors: %w", err)
+		}
+
+		for _, result := range afterOffset(results, offset) {
+			if vector := result.GetVectors().GetVectors().GetVectors()[denseVectorName].GetData(); len(vector) > 0 {
+				embeddings = append(embeddings, vector)
+			}
+		}
+
+		if uint32(len(results)) < batchSize {
+			break
+		}
+		offset = results[len(results)-1].Id
+	}
+
+	return embeddings, nil
+}
+
+// GetChunkEmbeddings returns the dense vectors of the chunks with the given IDs.
+// Unknown IDs are omitted from the result.
+func (s *QdrantStorage) GetChunkEmbeddings(ctx context.Context, ids []string) (map[string][]float32, error) {
+	embeddings := make(map[string][]float32, len(ids))
+	if len(ids) == 0 {
+		return embeddings, nil
+	}
+
+	pointIDs := make([]*qdrant.PointId, len(ids))
+	for i, id := range ids {
+		pointIDs[i] = qdrant.NewIDUUID(id)
+	}
+
+	results, err := s.client.Get(ctx, &qdrant.GetPoints{
+		CollectionName: s.collection,
+		Ids:            pointIDs,
+		WithPayload:    qdrant.NewWithPayload(false),
+		WithVectors:    qdrant.NewWithVectorsInclude(denseVectorName),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chunk vectors: %w", err)
+	}
+
+	for _, result := range results {
+		if vector := result.GetVectors().GetVectors().GetVectors()[denseVectorName].GetData(); len(vector) > 0 {
+			embeddings[result.Id.GetUuid()] = vector
+		}
+	}
+	return embeddings, nil
+}
+
+// ListDocumentSHAs returns the stored blob SHA for every indexed document path.
+// Documents indexed before blob SHAs were recorded map to an empty string,
+// which incremental sync treats as changed.
+func (s *QdrantStorage) ListDocumentSHAs(ctx context.Context, source string) (map[string]string, error) {
+	shas := make(map[string]string)
+	var offset *qdrant.PointId
+
+	must := []*qdrant.Condition{
+		qdrant.NewMatch("type", "parent"),
+	}
+	if source != "" {
+		must = append(must, qdrant.NewMatch("source", source))
+	}
+
+	batchSize := uint32(100)
+
+	for {
+		results, err := s.client.Scroll(ctx, &qdrant.ScrollPoints{
+			CollectionName: s.collection,
+			Filter:         &qdrant.Filter{Must: must},
+			Limit:          qdrant.PtrOf(batchSize),
+			Offset:         offset,
+			WithPayload:    qdrant.NewWithPayloadInclude("path", "blob_sha"),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to scroll documents: %w", err)
+		}
+
+		for _, result := range results {
+			if path := result.Payload["path"].GetStringValue(); path != "" {
+				shas[path] = result.Payload["blob_sha"].GetStringValue()
+			}
+		}
+
+		if uint32(len(results)) < batchSize {
+			break
+		}
+		offset = results[len(results)-1].Id
+	}
+
+	return shas, nil
+}
+
+// LastIndexedAt returns the latest indexed_at among the source's parent documents.
+func (s *QdrantStorage) LastIndexedAt(ctx context.Context, source string) (time.Time, error) {
+	var latest time.Time
+	var offset *qdrant.PointId
+
+	must := []*qdrant.Condition{
+		qdrant.NewMatch("type", "parent"),
+	}
+	if source != "" {
+		must = append(must, qdrant.NewMatch("source", source))
+	}
+
+	batchSize := uint32(100)
+
+	for {
+		results, err := s.client.Scroll(ctx, &qdrant.ScrollPoints{
+			CollectionName: s.collection,
+			Filter:         &qdrant.Filter{Must: must},
+			Limit:          qdrant.PtrOf(batchSize),
+			Offset:         offset,
+			WithPayload:    qdrant.NewWithPayloadInclude("indexed_at"),
+		})
+		if err != nil {
+			return time.Time{}, fmt.Errorf("failed to scroll documents: %w", err)
+		}
+
+		for _, result := range results {
+			indexedAt, err := time.Parse(time.RFC3339, result.Payload["indexed_at"].GetStringValue())
+			if err == nil && indexedAt.After(latest) {
+				latest = indexedAt
+			}
+		}
+
+		if uint32(len(results)) < batchSize {
+			break
+		}
+		offset = results[len(results)-1].Id
+	}
+
+	return latest, nil
+}
+
+// ListEntities returns the sorted, de-duplicated union of all parent documents' entities.
+func (s *QdrantStorage) ListEntities(ctx context.Context, source string) ([]string, error) {
+	seen := make(map[string]bool)
+	var offset *qdrant.PointId
+
+	must := []*qdrant.Condition{
+		qdrant.NewMatch("type", "parent"),
+	}
+	if source != "" {
+		must = append(must, qdrant.NewMatch("source", source))
+	}
+
+	batchSize := uint32(100)
+
+	for {
+		results, err := s.client.Scroll(ctx, &qdrant.ScrollPoints{
+			CollectionName: s.collection,
+			Filter:         &qdrant.Filter{Must: must},
+			Limit:          qdrant.PtrOf(batchSize),
+			Offset:         offset,
+			WithPayload:    qdrant.NewWithPayloadInclude("entities"),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to scroll documents: %w", err)
+		}
+
+		for _, result := range results {
+			for _, v := range result.Payload["entities"].GetListValue().GetValues() {
+				if entity := v.GetStringValue(); entity != "" {
+					seen[entity] = true
+				}
+			}
+		}
+
+		if uint32(len(results)) < batchSize {
+			break
+		}
+		offset = results[len(results)-1].Id
+	}
+
+	return sortedKeys(seen), nil
+}
+
+// ListAliases maps every normalized alias to the path of the document declaring it.
+func (s *QdrantStorage) ListAliases(ctx context.Context, source string) (map[string]string, error) {
+	aliases := make(map[string]string)
+	var offset *qdrant.PointId
+
+	must := []*qdrant.Condition{
+		qdrant.NewMatch("type", "parent"),
+	}
+	if source != "" {
+		must = append(must, qdrant.NewMatch("source", source))
+	}
+
+	batchSize := uint32(100)
+
+	for {
+		results, err := s.client.Scroll(ctx, &qdrant.ScrollPoints{
+			CollectionName: s.collection,
+			Filter:         &qdrant.Filter{Must: must},
+			Limit:          qdrant.PtrOf(batchSize),
+			Offset:         offset,
+			WithPayload:    qdrant.NewWithPayloadInclude("path", "aliases"),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to scroll documents: %w", err)
+		}
+
+		for _, result := range results {
+			path := result.Payload["path"].GetStringValue()
+			for _, v := range result.Payload["aliases"].GetListValue().GetValues() {
+				if alias := v.GetStringValue(); alias != "" && path != "" {
+					aliases[alias] = path
+				}
+			}
+		}
+
+		if uint32(len(results)) < batchSize {
+			break
+		}
+		offset = results[len(results)-1].Id
+	}
+
+	return aliases, nil
+}
+
+// ListLinks maps every document path to the internal link targets it contains.
+func (s *QdrantStorage) ListLinks(ctx context.Context, source string) (map[string][]string, error) {
+	links := make(map[string][]string)
+	var offset *qdrant.PointId
+
+	must := []*qdrant.Condition{
+		qdrant.NewMatch("type", "parent"),
+	}
+	if source != "" {
+		must = append(must, qdrant.NewMatch("source", source))
+	}
+
+	batchSize := uint32(100)
+
+	for {
+		results, err := s.client.Scroll(ctx, &qdrant.ScrollPoints{
+			CollectionName: s.collection,
+			Filter:         &qdrant.Filter{Must: must},
+			Limit:          qdrant.PtrOf(batchSize),
+			Offset:         offset,
+			WithPayload:    qdrant.NewWithPayloadInclude("path", "links"),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to scroll documents: %w", err)
+		}
+
+		for _, result := range afterOffset(results, offset) {
+			path := result.Payload["path"].GetStringValue()
+			if path == "" {
+				continue
+			}
+			for _, v := range result.Payload["links"].GetListValue().GetValues() {
+				if link := v.GetStringValue(); link != "" {
+					links[path] = append(links[path], link)
+				}
+			}
+		}
+
+		if uint32(len(results)) < batchSize {
+			break
+		}
+		offset = results[len(results)-1].Id
+	}
+
+	return links, nil
+}
+
+// DeleteDocumentByPath removes the parent document and all chunks for a path.
+// Deleting a path that is not indexed is not an error.
+func (s *QdrantStorage) DeleteDocumentByPath(ctx context.Context, path string, source string) error {
+	must := []*qdrant.Condition{
+		qdrant.NewMatch("path", path),
+	}
+	if source != "" {
+		must = append(must, qdrant.NewMatch("source", source))
+	}
+
+	_, err := s.client.Delete(ctx, &qdrant.DeletePoints{
+		CollectionName: s.collection,
+		Points:         qdrant.NewPointsSelectorFilter(&qdrant.Filter{Must: must}),
+		Wait:           qdrant.PtrOf(true),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete document %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// UpdateCommitSHA stamps every parent document of a source with the given commit SHA.
+// Incremental sync uses this so unchanged documents report the commit they were verified against.
+func (s *QdrantStorage) UpdateCommitSHA(ctx context.Context, source string, commitSHA string) error {
+	_, err := s.client.SetPayload(ctx, &qdrant.SetPayloadPoints{
+		CollectionName: s.collection,
+		Payload: qdrant.NewValueMap(map[string]any{
+			"commit_sha": commitSHA,
+		}),
+		PointsSelector: qdrant.NewPointsSelectorFilter(&qdrant.Filter{
+			Must: []*qdrant.Condition{
+				qdrant.NewMatch("type", "parent"),
+				qdrant.NewMatch("source", source),
+			},
+		}),
+		Wait: qdrant.PtrOf(true),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update commit SHA: %w", err)
+	}
+
+	return nil
+}
+
+// CollectionInfo contains collection statistics
+type CollectionInfo struct {
+	PointsCount  uint64 // Documents, chunks and symbols; the meta point is not counted
+	ChunksCount  uint64 // Document chunks, included in PointsCount
+	SymbolsCount uint64 // Go API symbols, included in PointsCount
+}
+
+// GetCollectionInfo retrieves collection statistics including total points count.
+// Used for calculating total chunks in the index.
+func (s *QdrantStorage) GetCollectionInfo(ctx context.Context) (*CollectionInfo, error) {
+	// Count documents and chunks only; the meta point is not content
+	pointsCount, err := s.client.Count(ctx, &qdrant.CountPoints{
+		CollectionName: s.collection,
+		Filter: &qdrant.Filter{
+			MustNot: []*qdrant.Condition{qdrant.NewMatch("type", "meta")},
+		},
+		Exact: qdrant.PtrOf(true),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get collection: %w", err)
+	}
+
+	chunksCount, err := s.client.Count(ctx, &qdrant.CountPoints{
+		CollectionName: s.collection,
+		Filter: &qdrant.Filter{
+			Must: []*qdrant.Condition{qdrant.NewMatch("type", "chunk")},
+		},
+		Exact: qdrant.PtrOf(true),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to count chunks: %w", err)
+	}
+
+	symbolsCount, err := s.client.Count(ctx, &qdrant.CountPoints{
+		CollectionName: s.collection,
+		Filter: &qdrant.Filter{
+			Must: []*qdrant.Condition{qdrant.NewMatch("type", "symbol")},
+		},
+		Exact: qdrant.PtrOf(true),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to count symbols: %w", err)
+	}
+
+	return &CollectionInfo{
+		PointsCount:  pointsCount,
+		ChunksCount:  chunksCount,
+		SymbolsCount: symbolsCount,
+	}, nil
+}
+
+// UpsertSymbols stores Go API symbols as points of type "symbol", with the dense and
+// BM25 vectors of their Text so chunk searches that set SearchFilter.Symbols find them.
+func (s *QdrantStorage) UpsertSymbols(ctx context.Context, symbols []*Symbol) error {
+	if len(symbols) == 0 {
+		return nil
+	}
+
+	spec, err := s.loadSpec(ctx)
+	if err != nil {
+		return err
+	}
+	for _, symbol := range symbols {
+		if err := checkDimension(spec, len(symbol.Embedding), "symbol "+symbol.QualifiedName()); err != nil {
+			return err
+		}
+	}
+	sparse, err := s.SparseEnabled(ctx)
+	if err != nil {
+		return err
+	}
+
+	batchSize := 100
+	for i := 0; i < len(symbols); i += batchSize {
+		end := min(i+batchSize, len(symbols))
+
+		points := make([]*qdrant.PointStruct, 0, end-i)
+		for _, symbol := range symbols[i:end] {
+			vectors := map[string]*qdrant.Vector{
+				denseVectorName: qdrant.NewVector(symbol.Embedding...),
+			}
+			if sparse && len(symbol.Sparse.Indices) > 0 {
+				vectors[sparseVectorName] = qdrant.NewVectorSparse(symbol.Sparse.Indices, symbol.Sparse.Values)
+			}
+			points = append(points, &qdrant.PointStruct{
+				Id:      qdrant.NewIDUUID(symbol.ID),
+				Vectors: qdrant.NewVectorsMap(vectors),
+				Payload: qdrant.NewValueMap(map[string]any{
+					"type":         "symbol",
+					"name":         symbol.Name,
+					"kind":         symbol.Kind,
+					"package":      symbol.Package,
+					"package_name": symbol.PackageName,
+					"signature":    symbol.Signature,
+					"doc":          symbol.Doc,
+					"file":         symbol.File,
+					"line":         symbol.Line,
+					"repository":   symbol.Repository,
+					"commit_sha":   symbol.CommitSHA,
+					"symbol_keys":  stringList(symbol.lookupKeys()),
+				}),
+			})
+		}
+
+		if err := s.upsertWithRetry(ctx, points); err != nil {
+			return fmt.Errorf("failed to upsert symbols %d-%d: %w", i, end, err)
+		}
+	}
+
+	return nil
+}
+
+// DeleteSymbols removes all symbols of a repository (all repositories if empty).
+func (s *QdrantStorage) DeleteSymbols(ctx context.Context, repository string) error {
+	must := []*qdrant.Condition{
+		qdrant.NewMatch("type", "symbol"),
+	}
+	if repository != "" {
+		must = append(must, qdrant.NewMatch("repository", repository))
+	}
+
+	_, err := s.client.Delete(ctx, &qdrant.DeletePoints{
+		CollectionName: s.collection,
+		Points:         qdrant.NewPointsSelectorFilter(&qdrant.Filter{Must: must}),
+		Wait:           qdrant.PtrOf(true),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete symbols: %w", err)
+	}
+
+	return nil
+}
+
+// FindSymbols returns the symbols matching name, ordered by package and name.
+func (s *QdrantStorage) FindSymbols(ctx context.Context, name string, repository string) ([]*Symbol, error) {
+	must := []*qdrant.Condition{
+		qdrant.NewMatch("type", "symbol"),
+		qdrant.NewMatch("symbol_keys", SymbolKey(name)),
+	}
+
+	var symbols []*Symbol
+	err := s.scrollSymbols(ctx, must, repository, nil, func(id string, payload map[string]*qdrant.Value) {
+		symbols = append(symbols, symbolFromPayload(id, payload))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sortSymbols(symbols)
+	return symbols, nil
+}
+
+// ListSymbolNames returns the sorted qualified names of all symbols.
+func (s *QdrantStorage) ListSymbolNames(ctx context.Context, repository string) ([]string, error) {
+	must := []*qdrant.Condition{
+		qdrant.NewMatch("type", "symbol"),
+	}
+
+	seen := make(map[string]bool)
+	err := s.scrollSymbols(ctx, must, repository, []string{"name", "package_name"}, func(id string, payload map[string]*qdrant.Value) {
+		symbol := Symbol{Name: payload["name"].GetStringValue(), PackageName: payload["package_name"].GetStringValue()}
+		seen[symbol.QualifiedName()] = true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return sortedKeys(seen), nil
+}
+
+// scrollSymbols calls fn for every symbol point matching must and the optional
+// repository, reading only the given payload fields (all fields if nil).
+func (s *QdrantStorage) scrollSymbols(
+	ctx context.Context, must []*qdrant.Condition, repository string, fields []string,
+	fn func(id string, payload map[string]*qdrant.Value),
+) error {
+	if repository != "" {
+		must = append(must, qdrant.NewMatch("repository", repository))
+	}
+	withPayload := qdrant.NewWithPayload(true)
+	if fields != nil {
+		withPayload = qdrant.NewWithPayloadInclude(fields...)
+	}
+
+	batchSize := uint32(100)
+	var offset *qdrant.PointId
+
+	for {
+		results, err := s.client.Scroll(ctx, &qdrant.ScrollPoints{
+			CollectionName: s.collection,
+			Filter:         &qdrant.Filter{Must: must},
+			Limit:          qdrant.PtrOf(batchSize),
+			Offset:         offset,
+			WithPayload:    withPayload,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to scroll symbols: %w", err)
+		}
+
+		for _, result := range afterOffset(results, offset) {
+			fn(result.Id.GetUuid(), result.Payload)
+		}
+
+		if uint32(len(results)) < batchSize {
+			return nil
+		}
+		offset = results[len(results)-1].Id
+	}
+}
+
+// symbolFromPayload converts a symbol point payload into a Symbol.
+func symbolFromPayload(id string, payload map[string]*qdrant.Value) *Symbol {
+	return &Symbol{
+		ID:          id,
+		Name:        payload["name"].GetStringValue(),
+		Kind:        payload["kind"].GetStringValue(),
+		Package:     payload["package"].GetStringValue(),
+		PackageName: payload["package_name"].GetStringValue(),
+		Signature:   payload["signature"].GetStringValue(),
+		Doc:         payload["doc"].GetStringValue(),
+		File:        payload["file"].GetStringValue(),
+		Line:        int(payload["line"].GetIntegerValue()),
+		Repository:  payload["repository"].GetStringValue(),
+		CommitSHA:   payload["commit_sha"].GetStringValue(),
+	}
 }
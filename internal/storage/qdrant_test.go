@@ -4,6 +4,7 @@ package storage
 
 import (
 	"context"
+	"fmt"
 	"testing"
 	"time"
 
@@ -20,7 +21,7 @@ func setupTestStorage(t *testing.T) *QdrantStorage {
 		t.Skipf("Qdrant not available: %v", err)
 	}
 
-	err = storage.EnsureCollection(context.Background())
+	err = storage.EnsureCollection(context.Background(), LegacyEmbeddingSpec)
 	require.NoError(t, err, "Failed to ensure collection")
 
 	return storage
@@ -41,8 +42,10 @@ func TestDocumentRoundTrip(t *testing.T) {
 		Content: "# Test Document\n\nThis is test content with **markdown**.",
 		Metadata: DocumentMetadata{
 			Path:       "test/roundtrip.md",
-			URL:        "https://raw.githubusercontent.com/test/repo/main/test/roundtrip.md",
+			URL:        "https://github.com/test/repo/blob/abc123/test/roundtrip.md",
+			PageURL:    "https://www.cloudwego.io/docs/eino/test/roundtrip/",
 			Repository: "test/repo",
+			Source:     "test",
 			CommitSHA:  "abc123def456",
 			IndexedAt:  now,
 			Summary:    "A test document for roundtrip testing",
@@ -63,7 +66,9 @@ func TestDocumentRoundTrip(t *testing.T) {
 	assert.Equal(t, doc.Content, retrieved.Content)
 	assert.Equal(t, doc.Metadata.Path, retrieved.Metadata.Path)
 	assert.Equal(t, doc.Metadata.URL, retrieved.Metadata.URL)
+	assert.Equal(t, doc.Metadata.PageURL, retrieved.Metadata.PageURL)
 	assert.Equal(t, doc.Metadata.Repository, retrieved.Metadata.Repository)
+	assert.Equal(t, doc.Metadata.Source, retrieved.Metadata.Source)
 	assert.Equal(t, doc.Metadata.CommitSHA, retrieved.Metadata.CommitSHA)
 	assert.Equal(t, doc.Metadata.Summary, retrieved.Metadata.Summary)
 	assert.ElementsMatch(t, doc.Metadata.Entities, retrieved.Metadata.Entities)
@@ -89,6 +94,7 @@ func TestChunkSearchRoundTrip(t *testing.T) {
 		Metadata: DocumentMetadata{
 			Path:       "test/parent.md",
 			Repository: repo,
+			Source:     repo,
 			CommitSHA:  "parent123",
 			IndexedAt:  time.Now().UTC(),
 		},
@@ -98,7 +104,7 @@ func TestChunkSearchRoundTrip(t *testing.T) {
 
 	// Create chunk with fake embedding (1536 dimensions of 0.1)
 	chunkID := uuid.New().String()
-	embedding := make([]float32, VectorDimension)
+	embedding := make([]float32, LegacyEmbeddingSpec.Dimension)
 	for i := range embedding {
 		embedding[i] = 0.1
 	}
@@ -111,6 +117,7 @@ func TestChunkSearchRoundTrip(t *testing.T) {
 		Content:     "Introduction section content",
 		Path:        "test/parent.md",
 		Repository:  repo,
+		Source:      repo,
 		Embedding:   embedding,
 	}
 
@@ -150,6 +157,7 @@ func TestCommitSHATracking(t *testing.T) {
 		Metadata: DocumentMetadata{
 			Path:       "test/commit.md",
 			Repository: repo,
+			Source:     repo,
 			CommitSHA:  expectedSHA,
 			IndexedAt:  time.Now().UTC(),
 		},
@@ -278,6 +286,7 @@ func TestBatchChunkUpsert(t *testing.T) {
 		Metadata: DocumentMetadata{
 			Path:       "test/batch.md",
 			Repository: repo,
+			Source:     repo,
 			CommitSHA:  "batch123",
 			IndexedAt:  time.Now().UTC(),
 		},
@@ -287,7 +296,7 @@ func TestBatchChunkUpsert(t *testing.T) {
 
 	// Create 250 chunks (more than one batch of 100)
 	chunks := make([]*Chunk, 250)
-	embedding := make([]float32, VectorDimension)
+	embedding := make([]float32, LegacyEmbeddingSpec.Dimension)
 	for i := range embedding {
 		embedding[i] = 0.5
 	}
@@ -301,6 +310,7 @@ func TestBatchChunkUpsert(t *testing.T) {
 			Content:     "Chunk content",
 			Path:        "test/batch.md",
 			Repository:  repo,
+			Source:      repo,
 			Embedding:   embedding,
 		}
 	}
@@ -313,6 +323,47 @@ func TestBatchChunkUpsert(t *testing.T) {
 	results, err := storage.SearchChunks(ctx, embedding, 300, repo)
 	require.NoError(t, err)
 	assert.GreaterOrEqual(t, len(results), 250, "Expected at least 250 chunks in search results")
+
+	// Paging through the chunks returns each of them once, in order
+	stored, err := storage.GetDocumentChunks(ctx, "test/batch.md", repo)
+	require.NoError(t, err)
+	require.Len(t, stored, 250)
+	for i, chunk := range stored {
+		assert.Equal(t, i, chunk.ChunkIndex)
+	}
+
+	embeddings, err := storage.GetDocumentEmbeddings(ctx, "test/batch.md", repo)
+	require.NoError(t, err)
+	assert.Len(t, embeddings, 250)
+}
+
+func TestListLinks_Paging(t *testing.T) {
+	storage := setupTestStorage(t)
+	defer storage.Close()
+
+	ctx := context.Background()
+
+	// Use unique repository to avoid conflicts; more documents than one scroll page
+	repo := "test/links-" + uuid.New().String()
+	for i := 0; i < 150; i++ {
+		err := storage.UpsertDocument(ctx, &Document{
+			ID: uuid.New().String(),
+			Metadata: DocumentMetadata{
+				Path:      fmt.Sprintf("docs/doc%03d.md", i),
+				Source:    repo,
+				IndexedAt: time.Now().UTC(),
+				Links:     []string{"overview.md"},
+			},
+		})
+		require.NoError(t, err)
+	}
+
+	links, err := storage.ListLinks(ctx, repo)
+	require.NoError(t, err)
+	assert.Len(t, links, 150)
+	for path, targets := range links {
+		assert.Equal(t, []string{"overview.md"}, targets, "links of %s listed once", path)
+	}
 }
 
 func TestSearchChunksWithScores(t *testing.T) {
@@ -332,6 +383,7 @@ func TestSearchChunksWithScores(t *testing.T) {
 		Metadata: DocumentMetadata{
 			Path:       "test/scored.md",
 			Repository: repo,
+			Source:     repo,
 			CommitSHA:  "scored123",
 			IndexedAt:  time.Now().UTC(),
 		},
@@ -341,7 +393,7 @@ func TestSearchChunksWithScores(t *testing.T) {
 
 	// Create chunk with embedding
 	chunkID := uuid.New().String()
-	embedding := make([]float32, VectorDimension)
+	embedding := make([]float32, LegacyEmbeddingSpec.Dimension)
 	for i := range embedding {
 		embedding[i] = 0.1
 	}
@@ -354,6 +406,7 @@ func TestSearchChunksWithScores(t *testing.T) {
 		Content:     "Scored search content",
 		Path:        "test/scored.md",
 		Repository:  repo,
+		Source:      repo,
 		Embedding:   embedding,
 	}
 
@@ -361,7 +414,7 @@ func TestSearchChunksWithScores(t *testing.T) {
 	require.NoError(t, err, "Failed to upsert chunk")
 
 	// Search with same embedding - should get high score
-	results, err := storage.SearchChunksWithScores(ctx, embedding, 10, repo)
+	results, err := storage.SearchChunksWithScores(ctx, embedding, 10, SearchFilter{Source: repo})
 	require.NoError(t, err, "Failed to search chunks with scores")
 
 	// Assert chunk is found with a score
@@ -392,6 +445,7 @@ func TestListDocumentPaths(t *testing.T) {
 			Metadata: DocumentMetadata{
 				Path:       path,
 				Repository: repo,
+				Source:     repo,
 				CommitSHA:  "list123",
 				IndexedAt:  time.Now().UTC(),
 			},
@@ -442,6 +496,7 @@ func TestGetDocumentByPath(t *testing.T) {
 			Path:       path,
 			URL:        "https://example.com/test/by-path.md",
 			Repository: repo,
+			Source:     repo,
 			CommitSHA:  "bypath123",
 			IndexedAt:  time.Now().UTC().Truncate(time.Second),
 			Summary:    "Test summary for by-path document",
@@ -476,3 +531,141 @@ func TestGetDocumentByPath_NotFound(t *testing.T) {
 	_, err := storage.GetDocumentByPath(ctx, "nonexistent/path.md", "nonexistent/repo")
 	assert.ErrorIs(t, err, ErrDocumentNotFound, "Expected ErrDocumentNotFound for invalid path")
 }
+
+func TestIncrementalSyncHelpers(t *testing.T) {
+	storage := setupTestStorage(t)
+	defer storage.Close()
+
+	ctx := context.Background()
+
+	// Use unique repository to avoid conflicts with other tests
+	repo := "test/incremental-" + uuid.New().String()
+
+	// Create two documents, each with one chunk
+	embedding := make([]float32, LegacyEmbeddingSpec.Dimension)
+	for i := range embedding {
+		embedding[i] = 0.2
+	}
+	for path, sha := range map[string]string{"docs/keep.md": "sha-keep", "docs/drop.md": "sha-drop"} {
+		docID := uuid.New().String()
+		doc := &Document{
+			ID:      docID,
+			Content: "# " + path,
+			Metadata: DocumentMetadata{
+				Path:       path,
+				Repository: repo,
+				Source:     repo,
+				CommitSHA:  "old-commit",
+				BlobSHA:    sha,
+				IndexedAt:  time.Now().UTC(),
+			},
+		}
+		require.NoError(t, storage.UpsertDocument(ctx, doc))
+		require.NoError(t, storage.UpsertChunks(ctx, []*Chunk{{
+			ID:          uuid.New().String(),
+			ParentDocID: docID,
+			Content:     "Chunk for " + path,
+			Path:        path,
+			Repository:  repo,
+			Source:      repo,
+			Embedding:   embedding,
+		}}))
+	}
+
+	// Blob SHAs are returned per path
+	shas, err := storage.ListDocumentSHAs(ctx, repo)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"docs/keep.md": "sha-keep", "docs/drop.md": "sha-drop"}, shas)
+
+	// Deleting a path removes both the parent and its chunks
+	require.NoError(t, storage.DeleteDocumentByPath(ctx, "docs/drop.md", repo))
+
+	_, err = storage.GetDocumentByPath(ctx, "docs/drop.md", repo)
+	assert.ErrorIs(t, err, ErrDocumentNotFound)
+
+	results, err := storage.SearchChunks(ctx, embedding, 10, repo)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "docs/keep.md", results[0].Path)
+
+	// Commit SHA is restamped on remaining parents
+	require.NoError(t, storage.UpdateCommitSHA(ctx, repo, "new-commit"))
+	commitSHA, err := storage.GetCommitSHA(ctx, repo)
+	require.NoError(t, err)
+	assert.Equal(t, "new-commit", commitSHA)
+}
+
+func TestSymbols(t *testing.T) {
+	storage := setupTestStorage(t)
+	defer storage.Close()
+
+	ctx := context.Background()
+
+	// Use unique repository to avoid conflicts with other tests
+	repo := "test/symbols-" + uuid.New().String()
+
+	embedding := make([]float32, LegacyEmbeddingSpec.Dimension)
+	embedding[0] = 1.0
+
+	symbols := []*Symbol{
+		{ID: uuid.New().String(), Name: "NewGraph", Kind: "func", Package: "github.com/cloudwego/eino/compose", PackageName: "compose", Signature: "func NewGraph()", Line: 42, Repository: repo, Embedding: embedding},
+		{ID: uuid.New().String(), Name: "Graph.Compile", Kind: "method", Package: "github.com/cloudwego/eino/compose", PackageName: "compose", Repository: repo, Embedding: embedding},
+	}
+	require.NoError(t, storage.UpsertSymbols(ctx, symbols))
+
+	// Symbols are searchable only when the filter asks for them
+	results, err := storage.SearchChunksWithScores(ctx, embedding, 100, SearchFilter{Source: repo})
+	require.NoError(t, err)
+	assert.Empty(t, results)
+	results, err = storage.SearchChunksWithScores(ctx, embedding, 100, SearchFilter{Source: repo, Symbols: true})
+	require.NoError(t, err)
+	var hits []string
+	for _, result := range results {
+		if result.Symbol != nil && result.Repository == repo {
+			hits = append(hits, result.HeaderPath)
+		}
+	}
+	assert.ElementsMatch(t, []string{"compose.NewGraph", "compose.Graph.Compile"}, hits)
+
+	found, err := storage.FindSymbols(ctx, "compose.NewGraph", repo)
+	require.NoError(t, err)
+	require.Len(t, found, 1)
+	assert.Equal(t, "func NewGraph()", found[0].Signature)
+	assert.Equal(t, 42, found[0].Line)
+
+	names, err := storage.ListSymbolNames(ctx, repo)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"compose.Graph.Compile", "compose.NewGraph"}, names)
+
+	require.NoError(t, storage.DeleteSymbols(ctx, repo))
+	found, err = storage.FindSymbols(ctx, "Compile", repo)
+	require.NoError(t, err)
+	assert.Empty(t, found)
+}
+
+func TestFindSymbols_Paging(t *testing.T) {
+	storage := setupTestStorage(t)
+	defer storage.Close()
+
+	ctx := context.Background()
+
+	// Use unique repository to avoid conflicts; more symbols than one scroll page
+	repo := "test/symbols-" + uuid.New().String()
+	embedding := make([]float32, LegacyEmbeddingSpec.Dimension)
+	embedding[0] = 1.0
+
+	symbols := make([]*Symbol, 150)
+	for i := range symbols {
+		pkg := fmt.Sprintf("pkg%03d", i)
+		symbols[i] = &Symbol{ID: uuid.New().String(), Name: "New", Kind: "func", Package: "example.com/" + pkg, PackageName: pkg, Repository: repo, Embedding: embedding}
+	}
+	require.NoError(t, storage.UpsertSymbols(ctx, symbols))
+
+	found, err := storage.FindSymbols(ctx, "New", repo)
+	require.NoError(t, err)
+	require.Len(t, found, 150, "each symbol is returned once")
+	assert.Equal(t, "pkg000", found[0].PackageName)
+	assert.Equal(t, "pkg149", found[149].PackageName)
+
+	require.NoError(t, storage.DeleteSymbols(ctx, repo))
+}
@@ -0,0 +1,47 @@
+package storage
+
+import "fmt"
+
+// EmbeddingSpec identifies the vector space a collection was built in.
+// It is recorded when the collection is created; queries embedded with a different
+// model or dimension would return meaningless results, so stores refuse them.
+type EmbeddingSpec struct {
+	Model     string // Embedding model name, e.g. "text-embedding-3-small"
+	Dimension int    // Dense vector size
+}
+
+// LegacyEmbeddingSpec describes collections built before the spec was recorded,
+// when text-embedding-3-small was the only supported model.
+var LegacyEmbeddingSpec = EmbeddingSpec{Model: "text-embedding-3-small", Dimension: 1536}
+
+// String formats the spec for error messages.
+func (e EmbeddingSpec) String() string {
+	return fmt.Sprintf("%s (%d dimensions)", e.Model, e.Dimension)
+}
+
+// IsZero reports whether no spec has been recorded.
+func (e EmbeddingSpec) IsZero() bool {
+	return e.Model == "" && e.Dimension == 0
+}
+
+// checkEmbeddingSpec returns ErrEmbeddingMismatch if want differs from the recorded spec.
+// An unknown recorded model (legacy collections of unusual size) only checks the dimension.
+func checkEmbeddingSpec(recorded, want EmbeddingSpec) error {
+	if recorded.Dimension != want.Dimension || (recorded.Model != "" && recorded.Model != want.Model) {
+		return fmt.Errorf("%w: index was built with %s, embedder is %s; configure the same model or run a full sync",
+			ErrEmbeddingMismatch, recorded, want)
+	}
+	return nil
+}
+
+// checkDimension returns ErrDimensionMismatch if a vector of size got does not fit the spec.
+func checkDimension(spec EmbeddingSpec, got int, what string) error {
+	if spec.Dimension == 0 {
+		return fmt.Errorf("%w: no embedding model recorded, call EnsureCollection first", ErrDimensionMismatch)
+	}
+	if got != spec.Dimension {
+		return fmt.Errorf("%w: %s has %d dimensions, expected %d",
+			ErrDimensionMismatch, what, got, spec.Dimension)
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Store is the storage backend used by the indexer and MCP server.
+// QdrantStorage talks to a Qdrant server; EmbeddedStorage runs in-process
+// and persists to a local file.
+type Store interface {
+	// Health reports whether the backend is reachable.
+	Health(ctx context.Context) error
+	// EnsureCollection prepares the backend for reads and writes with the given embedding
+	// model, recording it on first use. Idempotent. Returns ErrEmbeddingMismatch if the
+	// existing index was built with a different model or dimension.
+	EnsureCollection(ctx context.Context, spec EmbeddingSpec) error
+	// EmbeddingSpec returns the embedding model the index was built with
+	// (zero if nothing has been recorded yet).
+	EmbeddingSpec(ctx context.Context) (EmbeddingSpec, error)
+	// ClearCollection removes all documents and chunks.
+	ClearCollection(ctx context.Context) error
+	// Close releases resources and flushes pending writes.
+	Close() error
+
+	UpsertDocument(ctx context.Context, doc *Document) error
+	UpsertChunks(ctx context.Context, chunks []*Chunk) error
+	DeleteDocumentByPath(ctx context.Context, path string, repository string) error
+	UpdateCommitSHA(ctx context.Context, repository string, commitSHA string) error
+
+	GetDocument(ctx context.Context, id string) (*Document, error)
+	GetDocumentByPath(ctx context.Context, path string, repository string) (*Document, error)
+	GetDocumentByAlias(ctx context.Context, alias string, repository string) (*Document, error)
+	GetDocumentChunks(ctx context.Context, path string, repository string) ([]*Chunk, error)
+	GetDocumentEmbeddings(ctx context.Context, path string, repository string) ([][]float32, error)
+	GetChunkEmbeddings(ctx context.Context, ids []string) (map[string][]float32, error)
+	SearchChunksWithScores(ctx context.Context, embedding []float32, limit int, filter SearchFilter) ([]*ScoredChunk, error)
+	SearchChunksSparse(ctx context.Context, query SparseVector, limit int, filter SearchFilter) ([]*ScoredChunk, error)
+	ListDocumentPaths(ctx context.Context, repository string) ([]string, error)
+	ListDocumentSHAs(ctx context.Context, repository string) (map[string]string, error)
+	// LastIndexedAt returns when the most recently indexed document of a repository was
+	// stored (zero if it has none).
+	LastIndexedAt(ctx context.Context, repository string) (time.Time, error)
+	ListEntities(ctx context.Context, repository string) ([]string, error)
+	ListAliases(ctx context.Context, repository string) (map[string]string, error)
+	ListLinks(ctx context.Context, repository string) (map[string][]string, error)
+	GetCommitSHA(ctx context.Context, repository string) (string, error)
+	GetCollectionInfo(ctx context.Context) (*CollectionInfo, error)
+
+	// UpsertSymbols stores Go API symbols.
+	UpsertSymbols(ctx context.Context, symbols []*Symbol) error
+	// DeleteSymbols removes all symbols of a repository (all repositories if empty).
+	DeleteSymbols(ctx context.Context, repository string) error
+	// FindSymbols returns the symbols whose name matches (see SymbolKey), ordered by
+	// package and name. The name may be bare ("NewGraph"), package-qualified
+	// ("compose.NewGraph") or import-path-qualified.
+	FindSymbols(ctx context.Context, name string, repository string) ([]*Symbol, error)
+	// ListSymbolNames returns the sorted qualified names of all symbols.
+	ListSymbolNames(ctx context.Context, repository string) ([]string, error)
+}
+
+// Compile-time interface checks.
+var (
+	_ Store = (*QdrantStorage)(nil)
+	_ Store = (*EmbeddedStorage)(nil)
+)
+
+// Supported storage backends.
+const (
+	BackendQdrant   = "qdrant"
+	BackendEmbedded = "embedded"
+)
+
+// Config selects and configures a storage backend.
+type Config struct {
+	Backend      string // BackendQdrant (default) or BackendEmbedded
+	QdrantHost   string // Qdrant hostname (qdrant backend)
+	QdrantPort   int    // Qdrant gRPC port (qdrant backend)
+	EmbeddedPath string // Index file path (embedded backend)
+}
+
+// Open creates the storage backend described by cfg.
+func Open(cfg Config) (Store, error) {
+	switch cfg.Backend {
+	case "", BackendQdrant:
+		return NewQdrantStorage(cfg.QdrantHost, cfg.QdrantPort)
+	case BackendEmbedded:
+		return NewEmbeddedStorage(cfg.EmbeddedPath)
+	default:
+		return nil, fmt.Errorf("unknown storage backend %q", cfg.Backend)
+	}
+}
+
+// sortedKeys returns the keys of a string set in ascending order.
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// sortSymbols orders symbols by import path, then name.
+func sortSymbols(symbols []*Symbol) {
+	sort.Slice(symbols, func(i, j int) bool {
+		if symbols[i].Package != symbols[j].Package {
+			return symbols[i].Package < symbols[j].Package
+		}
+		return symbols[i].Name < symbols[j].Name
+	})
+}
@@ -0,0 +1,246 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mike-a-ellis/eino-docs-mcp/internal/scheduler"
+)
+
+// defaultRetryDelay is how long the queue waits before retrying a batch that found
+// another sync running, and before the first retry of documents that failed to sync.
+const defaultRetryDelay = 30 * time.Second
+
+// maxRetryDelay caps the exponential backoff between retries of failing documents.
+const maxRetryDelay = 30 * time.Minute
+
+// maxAttempts is how many times the queue syncs a failing document before giving up
+// on it; the next incremental sync still picks it up.
+const maxAttempts = 8
+
+// Syncer runs syncs under a single-flight lock. *scheduler.Scheduler implements it.
+type Syncer interface {
+	SyncNow(ctx context.Context) (*scheduler.Run, error)
+	SyncPaths(ctx context.Context, paths map[string][]string) (*scheduler.Run, error)
+}
+
+// document identifies a queued document by its source's name and its path.
+type document struct {
+	source string
+	path   string
+}
+
+// Queue collects documents to re-index and syncs them one batch at a time. Pushes
+// that arrive while a batch is syncing are merged into the next batch, so a burst of
+// pushes costs one sync of the files they touched, however many pushes there were.
+// Nothing is dropped while the queue waits: a batch that finds another sync running
+// is merged back and retried. Documents that fail to sync, or a whole batch that
+// fails, are queued again with exponential backoff, up to maxAttempts times.
+// The queue lives in memory: work pending when the process exits is lost.
+type Queue struct {
+	syncer     Syncer
+	retryDelay time.Duration
+	logger     *slog.Logger
+
+	mu          sync.Mutex // Guards the fields below
+	paths       map[document]bool
+	all         bool             // A full incremental sync is pending; it covers any paths
+	attempts    map[document]int // Failed syncs of paths that are waiting for a retry
+	allAttempts int              // Failed full syncs waiting for a retry
+	wake        chan struct{}
+}
+
+// NewQueue creates a queue that syncs through syncer once Run is started.
+func NewQueue(syncer Syncer, logger *slog.Logger) *Queue {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &Queue{
+		syncer:     syncer,
+		retryDelay: defaultRetryDelay,
+		logger:     logger,
+		paths:      make(map[document]bool),
+		attempts:   make(map[document]int),
+		wake:       make(chan struct{}, 1),
+	}
+}
+
+// Enqueue adds documents of the named source, relative to its docs directory, to the
+// next batch.
+func (q *Queue) Enqueue(source string, paths []string) {
+	q.mu.Lock()
+	for _, path := range paths {
+		q.paths[document{source, path}] = true
+	}
+	q.mu.Unlock()
+	q.signal()
+}
+
+// EnqueueAll makes the next batch a full incremental sync.
+func (q *Queue) EnqueueAll() {
+	q.mu.Lock()
+	q.all = true
+	q.mu.Unlock()
+	q.signal()
+}
+
+// Pending returns the number of queued documents, and whether a full sync is queued.
+func (q *Queue) Pending() (paths int, all bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.paths), q.all
+}
+
+// Run syncs queued batches until ctx is cancelled.
+func (q *Queue) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-q.wake:
+		}
+
+		paths, all := q.take()
+		if len(paths) == 0 && !all {
+			continue
+		}
+
+		var run *scheduler.Run
+		var err error
+		if all {
+			run, err = q.syncer.SyncNow(ctx)
+		} else {
+			run, err = q.syncer.SyncPaths(ctx, paths)
+		}
+
+		switch {
+		case errors.Is(err, scheduler.ErrSyncInProgress):
+			q.logger.Info("Webhook: sync already running, retrying later", "paths", countPaths(paths), "full", all, "retry_in", q.retryDelay)
+			q.requeue(paths, all)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(q.retryDelay):
+				q.signal()
+			}
+		case err != nil:
+			q.logger.Warn("Webhook: sync failed", "paths", countPaths(paths), "full", all, "error", err)
+			q.settle(ctx, paths, all, paths, all)
+		default:
+			if run.Failed > 0 {
+				q.logger.Warn("Webhook: documents failed to sync", "failed", run.Failed, "error", run.Error)
+			}
+			q.settle(ctx, paths, all, run.FailedPaths, false)
+		}
+	}
+}
+
+// settle records the outcome of a batch of paths (or a full sync, when all is set):
+// successful paths are forgotten, and the failed ones (failedAll for the full sync)
+// are queued again once their backoff has passed.
+func (q *Queue) settle(ctx context.Context, paths map[string][]string, all bool, failed map[string][]string, failedAll bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for source, sourcePaths := range paths {
+		for _, path := range sourcePaths {
+			delete(q.attempts, document{source, path})
+		}
+	}
+	if all && !failedAll {
+		q.allAttempts = 0
+	}
+
+	retry := make(map[string][]string)
+	attempt := 0
+	for source, sourcePaths := range failed {
+		for _, path := range sourcePaths {
+			doc := document{source, path}
+			q.attempts[doc]++
+			if q.attempts[doc] >= maxAttempts {
+				q.logger.Warn("Webhook: giving up on document; the next incremental sync retries it", "source", source, "path", path, "attempts", q.attempts[doc])
+				delete(q.attempts, doc)
+				continue
+			}
+			retry[source] = append(retry[source], path)
+			attempt = max(attempt, q.attempts[doc])
+		}
+	}
+	if failedAll {
+		q.allAttempts++
+		if q.allAttempts >= maxAttempts {
+			q.logger.Warn("Webhook: giving up on full sync", "attempts", q.allAttempts)
+			q.allAttempts = 0
+			failedAll = false
+		} else {
+			attempt = max(attempt, q.allAttempts)
+		}
+	}
+	if len(retry) == 0 && !failedAll {
+		return
+	}
+
+	delay := min(q.retryDelay<<(attempt-1), maxRetryDelay)
+	q.logger.Info("Webhook: retrying failed sync later", "paths", countPaths(retry), "full", failedAll, "attempt", attempt, "retry_in", delay)
+	time.AfterFunc(delay, func() {
+		if ctx.Err() != nil {
+			return
+		}
+		q.requeue(retry, failedAll)
+		q.signal()
+	})
+}
+
+// take removes and returns the pending batch, its paths sorted within each source.
+func (q *Queue) take() (paths map[string][]string, all bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	all = q.all
+	if !all {
+		paths = make(map[string][]string)
+		for doc := range q.paths {
+			paths[doc.source] = append(paths[doc.source], doc.path)
+		}
+		for _, sourcePaths := range paths {
+			sort.Strings(sourcePaths)
+		}
+	}
+	q.paths = make(map[document]bool)
+	q.all = false
+	return paths, all
+}
+
+// requeue merges a batch that could not run back into the pending one.
+func (q *Queue) requeue(paths map[string][]string, all bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for source, sourcePaths := range paths {
+		for _, path := range sourcePaths {
+			q.paths[document{source, path}] = true
+		}
+	}
+	q.all = q.all || all
+}
+
+// countPaths returns the number of documents in a batch.
+func countPaths(paths map[string][]string) int {
+	n := 0
+	for _, sourcePaths := range paths {
+		n += len(sourcePaths)
+	}
+	return n
+}
+
+// signal wakes Run without blocking; one pending signal covers any number of calls.
+func (q *Queue) signal() {
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
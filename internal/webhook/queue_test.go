@@ -0,0 +1,142 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mike-a-ellis/eino-docs-mcp/internal/scheduler"
+)
+
+// stubSyncer records the batches it is given and reports the scheduler as busy for
+// the first busy calls. Accepted batches fail with the next of errs, or report the
+// next of failed as documents that failed.
+type stubSyncer struct {
+	mu     sync.Mutex
+	busy   int
+	errs   []error
+	failed []map[string][]string
+	calls  chan map[string][]string // Paths of each accepted batch; nil for a full sync
+}
+
+func (s *stubSyncer) SyncNow(ctx context.Context) (*scheduler.Run, error) {
+	return s.sync(nil)
+}
+
+func (s *stubSyncer) SyncPaths(ctx context.Context, paths map[string][]string) (*scheduler.Run, error) {
+	return s.sync(paths)
+}
+
+func (s *stubSyncer) sync(paths map[string][]string) (*scheduler.Run, error) {
+	s.mu.Lock()
+	if s.busy > 0 {
+		s.busy--
+		s.mu.Unlock()
+		return nil, scheduler.ErrSyncInProgress
+	}
+	var err error
+	if len(s.errs) > 0 {
+		err, s.errs = s.errs[0], s.errs[1:]
+	}
+	run := &scheduler.Run{Outcome: scheduler.OutcomeSuccess}
+	if len(s.failed) > 0 {
+		run.FailedPaths, s.failed = s.failed[0], s.failed[1:]
+		run.Failed = countPaths(run.FailedPaths)
+		run.Outcome = scheduler.OutcomePartial
+	}
+	s.mu.Unlock()
+
+	s.calls <- paths
+	if err != nil {
+		return &scheduler.Run{Outcome: scheduler.OutcomeFailed, Error: err.Error()}, err
+	}
+	return run, nil
+}
+
+func nextCall(t *testing.T, calls chan map[string][]string) map[string][]string {
+	t.Helper()
+	select {
+	case paths := <-calls:
+		return paths
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for a sync")
+		return nil
+	}
+}
+
+// TestQueue_RetriesBusyBatch verifies a batch that finds a sync running is kept and
+// merged with pushes that arrive before the retry.
+func TestQueue_RetriesBusyBatch(t *testing.T) {
+	syncer := &stubSyncer{busy: 1, calls: make(chan map[string][]string)}
+	queue := NewQueue(syncer, nil)
+	queue.retryDelay = 50 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	queue.Enqueue("eino", []string{"overview.md", "core/graph.md"})
+	queue.Enqueue("eino", []string{"overview.md"})
+	go queue.Run(ctx)
+
+	time.Sleep(10 * time.Millisecond) // First attempt finds the scheduler busy
+	queue.Enqueue("eino", []string{"core/agent.md"})
+	queue.Enqueue("eino-ext", []string{"overview.md"})
+
+	want := map[string][]string{
+		"eino":     {"core/agent.md", "core/graph.md", "overview.md"},
+		"eino-ext": {"overview.md"},
+	}
+	if got := nextCall(t, syncer.calls); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected batch %v, got %v", want, got)
+	}
+	if paths, all := queue.Pending(); paths != 0 || all {
+		t.Errorf("expected an empty queue, got %d paths (all=%v)", paths, all)
+	}
+}
+
+// TestQueue_FullSync verifies a queued full sync covers queued paths.
+func TestQueue_FullSync(t *testing.T) {
+	syncer := &stubSyncer{calls: make(chan map[string][]string)}
+	queue := NewQueue(syncer, nil)
+
+	queue.Enqueue("eino", []string{"overview.md"})
+	queue.EnqueueAll()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go queue.Run(ctx)
+
+	if got := nextCall(t, syncer.calls); got != nil {
+		t.Errorf("expected a full sync, got paths %v", got)
+	}
+	if paths, all := queue.Pending(); paths != 0 || all {
+		t.Errorf("expected an empty queue, got %d paths (all=%v)", paths, all)
+	}
+}
+
+// TestQueue_RetriesFailures verifies a failed batch is retried whole and a partial one
+// retries only the documents that failed.
+func TestQueue_RetriesFailures(t *testing.T) {
+	syncer := &stubSyncer{
+		errs:   []error{errors.New("github unavailable")},
+		failed: []map[string][]string{nil, {"eino": {"core/graph.md"}}},
+		calls:  make(chan map[string][]string),
+	}
+	queue := NewQueue(syncer, nil)
+	queue.retryDelay = 10 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go queue.Run(ctx)
+
+	batch := map[string][]string{"eino": {"core/graph.md", "overview.md"}}
+	queue.Enqueue("eino", batch["eino"])
+	for i, want := range []map[string][]string{batch, batch, {"eino": {"core/graph.md"}}} {
+		if got := nextCall(t, syncer.calls); !reflect.DeepEqual(got, want) {
+			t.Errorf("call %d: expected batch %v, got %v", i, want, got)
+		}
+	}
+}
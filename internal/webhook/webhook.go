@@ -0,0 +1,259 @@
+// Package webhook receives GitHub push webhooks and queues the documents they touch
+// for re-indexing, so the index follows upstream without waiting for a poll.
+package webhook
+
+import (
+	"encoding/json"
+	"log/slog"
+	"mime"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/google/go-github/v81/github"
+
+	"github.com/mike-a-ellis/eino-docs-mcp/internal/source"
+)
+
+// maxPayloadBytes caps request bodies at the largest payload GitHub delivers.
+const maxPayloadBytes = 25 << 20
+
+// maxPushCommits is the most commits GitHub lists in a push payload. A push at the
+// limit may have changed files the payload does not mention.
+const maxPushCommits = 2048
+
+// maxDeliveries is the number of recent delivery IDs remembered for replay protection.
+const maxDeliveries = 1000
+
+// Response is the JSON body returned for deliveries that pass signature verification.
+type Response struct {
+	Status string              `json:"status"`           // queued, full_sync, duplicate, ignored or pong
+	Reason string              `json:"reason,omitempty"` // Why a delivery was ignored
+	Paths  map[string][]string `json:"paths,omitempty"`  // Documents queued for re-indexing, by source
+}
+
+// Handler verifies and parses GitHub webhook deliveries for the repositories of the
+// configured sources and queues re-indexing of the markdown files under a source's
+// docs directory that a push to its branch added, modified or removed.
+type Handler struct {
+	secret     []byte
+	sources    []source.Config
+	queue      *Queue
+	deliveries *deliveries
+	logger     *slog.Logger
+}
+
+// NewHandler creates a handler for pushes to the sources' repositories. secret is the
+// webhook's secret; it must not be empty, as unsigned deliveries are rejected.
+func NewHandler(secret []byte, sources []source.Config, queue *Queue, logger *slog.Logger) *Handler {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &Handler{
+		secret:     secret,
+		sources:    sources,
+		queue:      queue,
+		deliveries: newDeliveries(maxDeliveries),
+		logger:     logger,
+	}
+}
+
+// ServeHTTP handles one delivery. Deliveries with a missing or invalid
+// X-Hub-Signature-256 are rejected with 401. A delivery ID already seen, such as a
+// redelivery from the GitHub UI, is acknowledged without queueing anything again.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	signature := r.Header.Get(github.SHA256SignatureHeader)
+	if len(h.secret) == 0 || signature == "" {
+		http.Error(w, "missing signature", http.StatusUnauthorized)
+		return
+	}
+	contentType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		http.Error(w, "invalid content type", http.StatusBadRequest)
+		return
+	}
+	body := http.MaxBytesReader(w, r.Body, maxPayloadBytes)
+	payload, err := github.ValidatePayloadFromBody(contentType, body, signature, h.secret)
+	if err != nil {
+		h.logger.Warn("Webhook: rejected delivery", "delivery", github.DeliveryID(r), "error", err)
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	deliveryID := github.DeliveryID(r)
+	if deliveryID == "" {
+		http.Error(w, "missing delivery ID", http.StatusBadRequest)
+		return
+	}
+	if !h.deliveries.add(deliveryID) {
+		h.logger.Info("Webhook: ignoring duplicate delivery", "delivery", deliveryID)
+		writeResponse(w, http.StatusOK, Response{Status: "duplicate"})
+		return
+	}
+
+	eventType := github.WebHookType(r)
+	switch eventType {
+	case "ping":
+		writeResponse(w, http.StatusOK, Response{Status: "pong"})
+		return
+	case "push":
+	default:
+		writeResponse(w, http.StatusOK, Response{Status: "ignored", Reason: "event " + eventType + " is not handled"})
+		return
+	}
+
+	event, err := github.ParseWebHook(eventType, payload)
+	if err != nil {
+		h.deliveries.remove(deliveryID)
+		http.Error(w, "invalid push payload", http.StatusBadRequest)
+		return
+	}
+	push, ok := event.(*github.PushEvent)
+	if !ok {
+		h.deliveries.remove(deliveryID)
+		http.Error(w, "invalid push payload", http.StatusBadRequest)
+		return
+	}
+
+	sources, reason := h.pushedSources(push)
+	if reason != "" {
+		writeResponse(w, http.StatusOK, Response{Status: "ignored", Reason: reason})
+		return
+	}
+
+	// Force pushes can drop commits the payload never lists, and very large pushes
+	// are truncated: re-check every document instead
+	if push.GetForced() || len(push.Commits) >= maxPushCommits {
+		h.logger.Info("Webhook: queueing full sync", "delivery", deliveryID, "forced", push.GetForced(), "commits", len(push.Commits))
+		h.queue.EnqueueAll()
+		writeResponse(w, http.StatusAccepted, Response{Status: "full_sync"})
+		return
+	}
+
+	paths := make(map[string][]string)
+	for _, src := range sources {
+		if changed := changedDocs(push, src.BasePath); len(changed) > 0 {
+			paths[src.Name] = changed
+		}
+	}
+	if len(paths) == 0 {
+		writeResponse(w, http.StatusOK, Response{Status: "ignored", Reason: "no documentation changes"})
+		return
+	}
+	for name, changed := range paths {
+		h.logger.Info("Webhook: queueing documents", "delivery", deliveryID, "source", name, "after", push.GetAfter(), "paths", len(changed))
+		h.queue.Enqueue(name, changed)
+	}
+	writeResponse(w, http.StatusAccepted, Response{Status: "queued", Paths: paths})
+}
+
+// pushedSources returns the sources indexed from the repository and branch a push
+// updated, or explains why the push does not affect the index.
+func (h *Handler) pushedSources(push *github.PushEvent) ([]source.Config, string) {
+	repo := push.GetRepo()
+	var sources []source.Config
+	indexed := false // Some source is indexed from the repository
+	for _, src := range h.sources {
+		if !strings.EqualFold(repo.GetFullName(), src.Repository()) {
+			continue
+		}
+		indexed = true
+		if push.GetRef() == "refs/heads/"+src.Ref {
+			sources = append(sources, src)
+		}
+	}
+	switch {
+	case !indexed:
+		return nil, "push to " + repo.GetFullName() + ", which no source indexes"
+	case len(sources) == 0:
+		return nil, "push to " + push.GetRef() + ", not an indexed branch"
+	case push.GetDeleted():
+		return nil, "branch deleted"
+	}
+	return sources, ""
+}
+
+// changedDocs returns the markdown files under basePath that any commit of the push
+// added, modified or removed, relative to basePath and sorted. Whether each one still
+// exists is settled when it is re-indexed.
+func changedDocs(push *github.PushEvent, basePath string) []string {
+	prefix := strings.Trim(basePath, "/") + "/"
+	seen := make(map[string]bool)
+	for _, commit := range push.Commits {
+		for _, files := range [][]string{commit.Added, commit.Modified, commit.Removed} {
+			for _, file := range files {
+				rel, ok := strings.CutPrefix(file, prefix)
+				if ok && strings.HasSuffix(rel, ".md") {
+					seen[rel] = true
+				}
+			}
+		}
+	}
+
+	paths := make([]string, 0, len(seen))
+	for path := range seen {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+func writeResponse(w http.ResponseWriter, status int, response Response) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(response)
+}
+
+// deliveries remembers the most recent delivery IDs, oldest evicted first.
+type deliveries struct {
+	mu    sync.Mutex
+	limit int
+	seen  map[string]bool
+	order []string // Delivery IDs in arrival order
+}
+
+func newDeliveries(limit int) *deliveries {
+	return &deliveries{limit: limit, seen: make(map[string]bool)}
+}
+
+// add records id and reports whether it was new.
+func (d *deliveries) add(id string) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.seen[id] {
+		return false
+	}
+	d.seen[id] = true
+	d.order = append(d.order, id)
+	if len(d.order) > d.limit {
+		delete(d.seen, d.order[0])
+		d.order = d.order[1:]
+	}
+	return true
+}
+
+// remove forgets id, so a redelivery of a payload that could not be handled is
+// processed again.
+func (d *deliveries) remove(id string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if !d.seen[id] {
+		return
+	}
+	delete(d.seen, id)
+	for i, seen := range d.order {
+		if seen == id {
+			d.order = append(d.order[:i], d.order[i+1:]...)
+			break
+		}
+	}
+}
@@ -0,0 +1,168 @@
+package webhook
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/mike-a-ellis/eino-docs-mcp/internal/source"
+)
+
+const testSecret = "It's a Secret to Everybody"
+
+// newTestHandler returns a handler for the Eino docs and its queue, which is not run.
+func newTestHandler() (*Handler, *Queue) {
+	queue := NewQueue(nil, nil)
+	return NewHandler([]byte(testSecret), []source.Config{source.DefaultConfig}, queue, nil), queue
+}
+
+// deliver posts payload the way GitHub does, signed with secret unless it is empty.
+func deliver(t *testing.T, h http.Handler, event, deliveryID, secret string, payload []byte) (*httptest.ResponseRecorder, Response) {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/github", bytes.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-GitHub-Event", event)
+	req.Header.Set("X-GitHub-Delivery", deliveryID)
+	if secret != "" {
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write(payload)
+		req.Header.Set("X-Hub-Signature-256", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	var response Response
+	if rec.Header().Get("Content-Type") == "application/json" {
+		if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+			t.Fatalf("decode response: %v", err)
+		}
+	}
+	return rec, response
+}
+
+func readPayload(t *testing.T) []byte {
+	t.Helper()
+	payload, err := os.ReadFile("testdata/push.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return payload
+}
+
+// TestHandler_Push verifies a recorded push queues the docs it touched, once.
+func TestHandler_Push(t *testing.T) {
+	h, queue := newTestHandler()
+	payload := readPayload(t)
+
+	rec, response := deliver(t, h, "push", "72d3162e-cc78-11e3-81ab-4c9367dc0958", testSecret, payload)
+	if rec.Code != http.StatusAccepted || response.Status != "queued" {
+		t.Fatalf("expected 202 queued, got %d %+v", rec.Code, response)
+	}
+	want := map[string][]string{"eino": {
+		"core_modules/chain/chain_guide.md",
+		"core_modules/flow_integration_components/react_agent_manual.md",
+		"overview/_index.md",
+	}}
+	if !reflect.DeepEqual(response.Paths, want) {
+		t.Errorf("expected paths %v, got %v", want, response.Paths)
+	}
+	if paths, all := queue.Pending(); paths != 3 || all {
+		t.Errorf("expected 3 queued paths, got %d (all=%v)", paths, all)
+	}
+
+	// A redelivery is acknowledged without queueing again
+	queue.take()
+	rec, response = deliver(t, h, "push", "72d3162e-cc78-11e3-81ab-4c9367dc0958", testSecret, payload)
+	if rec.Code != http.StatusOK || response.Status != "duplicate" {
+		t.Errorf("expected 200 duplicate, got %d %+v", rec.Code, response)
+	}
+	if paths, _ := queue.Pending(); paths != 0 {
+		t.Errorf("duplicate delivery queued %d paths", paths)
+	}
+}
+
+// TestHandler_Signature verifies unsigned and wrongly signed deliveries are rejected
+// without using up their delivery ID.
+func TestHandler_Signature(t *testing.T) {
+	h, queue := newTestHandler()
+	payload := readPayload(t)
+
+	for name, secret := range map[string]string{"unsigned": "", "wrong secret": "guess"} {
+		rec, _ := deliver(t, h, "push", "delivery-1", secret, payload)
+		if rec.Code != http.StatusUnauthorized {
+			t.Errorf("%s: expected 401, got %d", name, rec.Code)
+		}
+	}
+	if paths, _ := queue.Pending(); paths != 0 {
+		t.Errorf("rejected deliveries queued %d paths", paths)
+	}
+
+	if rec, _ := deliver(t, h, "push", "delivery-1", testSecret, payload); rec.Code != http.StatusAccepted {
+		t.Errorf("expected the signed delivery to be accepted, got %d", rec.Code)
+	}
+}
+
+// TestHandler_Events verifies pings, other branches and force pushes.
+func TestHandler_Events(t *testing.T) {
+	h, queue := newTestHandler()
+	payload := readPayload(t)
+
+	if _, response := deliver(t, h, "ping", "delivery-ping", testSecret, []byte(`{"zen":"Keep it logically awesome."}`)); response.Status != "pong" {
+		t.Errorf("ping: got %+v", response)
+	}
+	if _, response := deliver(t, h, "issues", "delivery-issue", testSecret, []byte(`{}`)); response.Status != "ignored" {
+		t.Errorf("issues event: got %+v", response)
+	}
+
+	branch := bytes.Replace(payload, []byte(`"refs/heads/main"`), []byte(`"refs/heads/preview"`), 1)
+	if _, response := deliver(t, h, "push", "delivery-branch", testSecret, branch); response.Status != "ignored" {
+		t.Errorf("push to another branch: got %+v", response)
+	}
+	if paths, all := queue.Pending(); paths != 0 || all {
+		t.Errorf("ignored events queued work: %d paths (all=%v)", paths, all)
+	}
+
+	forced := bytes.Replace(payload, []byte(`"forced": false`), []byte(`"forced": true`), 1)
+	rec, response := deliver(t, h, "push", "delivery-forced", testSecret, forced)
+	if rec.Code != http.StatusAccepted || response.Status != "full_sync" {
+		t.Errorf("force push: got %d %+v", rec.Code, response)
+	}
+	if _, all := queue.Pending(); !all {
+		t.Error("force push did not queue a full sync")
+	}
+}
+
+// TestHandler_Sources verifies a push is split between the sources indexed from the
+// pushed repository and branch.
+func TestHandler_Sources(t *testing.T) {
+	queue := NewQueue(nil, nil)
+	hertz := source.Config{Name: "hertz", Owner: "cloudwego", Repo: "cloudwego.github.io", Ref: "main", BasePath: "content/en/docs/hertz"}
+	preview := source.Config{Name: "preview", Owner: "cloudwego", Repo: "cloudwego.github.io", Ref: "preview", BasePath: "content/en/docs/eino"}
+	h := NewHandler([]byte(testSecret), []source.Config{source.DefaultConfig, hertz, preview}, queue, nil)
+
+	rec, response := deliver(t, h, "push", "delivery-1", testSecret, readPayload(t))
+	if rec.Code != http.StatusAccepted || response.Status != "queued" {
+		t.Fatalf("expected 202 queued, got %d %+v", rec.Code, response)
+	}
+	if got := response.Paths["hertz"]; !reflect.DeepEqual(got, []string{"overview/_index.md"}) {
+		t.Errorf("expected the hertz overview, got %v", got)
+	}
+	if len(response.Paths["eino"]) != 3 || response.Paths["preview"] != nil {
+		t.Errorf("expected 3 eino paths and none for another branch, got %v", response.Paths)
+	}
+	if paths, _ := queue.Pending(); paths != 4 {
+		t.Errorf("expected 4 queued paths, got %d", paths)
+	}
+
+	other := bytes.Replace(readPayload(t), []byte(`"full_name": "cloudwego/cloudwego.github.io"`), []byte(`"full_name": "cloudwego/eino"`), 1)
+	if _, response := deliver(t, h, "push", "delivery-2", testSecret, other); response.Status != "ignored" {
+		t.Errorf("push to another repository: got %+v", response)
+	}
+}
@@ -0,0 +1,10 @@
+// Package prompts holds the built-in MCP prompt templates, which are compiled into the
+// server. The template format is described in internal/prompts.
+package prompts
+
+import "embed"
+
+// Templates holds the *.md prompt templates in this directory.
+//
+//go:embed *.md
+var Templates embed.FS